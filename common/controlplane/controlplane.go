@@ -1,6 +1,7 @@
 package controlplane
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -10,8 +11,10 @@ import (
 	"github.com/openebs/openebs-e2e/common"
 	"github.com/openebs/openebs-e2e/common/e2e_config"
 
+	"github.com/openebs/openebs-e2e/common/controlplane/fake"
 	v1 "github.com/openebs/openebs-e2e/common/controlplane/v1"
 	v1rest "github.com/openebs/openebs-e2e/common/controlplane/v1-rest-api"
+	"github.com/openebs/openebs-e2e/common/controlplane/watch"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -50,6 +53,7 @@ type ControlPlaneInterface interface {
 	CanDeleteMsv() bool
 	DeleteMsv(volName string) error
 	ListMsvs() ([]common.MayastorVolume, error)
+	ForEachMsv(fn func(common.MayastorVolume) error) error
 	SetMsvReplicaCount(uuid string, replicaCount int) error
 	GetMsvState(uuid string) (string, error)
 	GetMsvReplicas(volName string) ([]common.MsvReplica, error)
@@ -67,6 +71,7 @@ type ControlPlaneInterface interface {
 	SetVolumeMaxSnapshotCount(uuid string, maxSnapshotCount int32) error
 	GetMsvMaxSnapshotCount(uuid string) (int32, error)
 	GetMsvDeviceUri(uuid string) (string, error)
+	WatchMsv(uuid string) (*watch.MsvWatch, error)
 
 	// Mayastor Node abstraction
 
@@ -74,6 +79,11 @@ type ControlPlaneInterface interface {
 	ListMsns() ([]common.MayastorNode, error)
 	GetMsNodeStatus(node string) (string, error)
 	UpdateNodeLabel(nodeName string, labelKey, labelValue string) error
+	ListNodeBlockDevices(nodeName string, all bool) ([]common.BlockDevice, error)
+
+	// App node abstraction, app nodes are the hosts of NVMe initiators registered with the control plane
+	GetAppNode(id string) (*common.AppNode, error)
+	ListAppNodes() ([]common.AppNode, error)
 
 	NodeStateOffline() string
 	NodeStateOnline() string
@@ -111,6 +121,15 @@ type ControlPlaneInterface interface {
 	GetSnapshots() ([]common.SnapshotSchema, error)
 	GetVolumeSnapshotTopology() ([]common.SnapshotSchema, error)
 	GetPerSnapshotVolumeSnapshotTopology(snapshotId string) (common.SnapshotSchema, error)
+
+	//nexus
+	ListNexuses(node string) ([]common.MayastorNexus, error)
+	GetNexus(uuid string) (*common.MayastorNexus, error)
+	AddNexusChild(uuid string, childUri string) error
+	RemoveNexusChild(uuid string, childUri string) error
+	FaultNexusChild(uuid string, childUri string) error
+	ShareNexus(uuid string) (string, error)
+	UnshareNexus(uuid string) error
 }
 
 var ifc ControlPlaneInterface
@@ -119,6 +138,15 @@ var once sync.Once
 
 func getControlPlane() ControlPlaneInterface {
 	once.Do(func() {
+		switch selection := e2e_config.GetConfig().ControlPlane; selection {
+		case "fake", "fake-rest":
+			cp, _, err := makeFakeControlPlane(selection, fake.DefaultState())
+			if err != nil {
+				panic(err)
+			}
+			ifc = cp
+			return
+		}
 		version := e2e_config.GetConfig().MayastorVersion
 		verComponents := strings.Split(version, ".")
 		major, err := strconv.Atoi(verComponents[0])
@@ -146,6 +174,72 @@ func getControlPlane() ControlPlaneInterface {
 	return ifc
 }
 
+// makeFakeControlPlane makes the fake control plane of the selection on state, see e2e_config ControlPlane,
+// the returned function stops the in-process REST server of "fake-rest"
+func makeFakeControlPlane(selection string, state *fake.State) (ControlPlaneInterface, func(), error) {
+	switch selection {
+	case "fake":
+		logf.Log.Info("*** Using fake control plane ***")
+		return fake.MakeCP(state), func() {}, nil
+	case "fake-rest":
+		logf.Log.Info("*** Using REST API for communication with fake control plane ***")
+		srv := fake.NewRestServer(state)
+		cp, err := v1rest.MakeCPForHost(strings.TrimPrefix(srv.URL, "http://"))
+		if err != nil {
+			srv.Close()
+			return nil, nil, err
+		}
+		return cp, srv.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unknown fake control plane %s", selection)
+}
+
+// UseFakeControlPlane replaces the control plane with the fake control plane of the selection,
+// "fake" or "fake-rest", on state, eg: for unit tests of helpers which use the control plane.
+// The returned function restores the previous control plane, it must not be called concurrently
+// with other calls of the control plane.
+func UseFakeControlPlane(selection string, state *fake.State) (func(), error) {
+	cp, stop, err := makeFakeControlPlane(selection, state)
+	if err != nil {
+		return nil, err
+	}
+	selected := false
+	once.Do(func() { selected = true })
+	prev := ifc
+	ifc = cp
+	return func() {
+		ifc = prev
+		if selected {
+			// the control plane had not been selected by configuration yet
+			once = sync.Once{}
+		}
+		stop()
+	}, nil
+}
+
+// WithContext returns the control plane with its operations bounded by ctx, so that
+// operations in progress are cancelled when ctx is done, eg: with a Ginkgo SpecContext
+//
+//	It("...", func(ctx SpecContext) {
+//		msv, err := controlplane.WithContext(ctx).GetMSV(uuid)
+//	}, SpecTimeout(5*time.Minute))
+//
+// Operations are also bounded by the control plane call timeout of the configuration,
+// a control plane which does not support contexts is returned unchanged.
+func WithContext(ctx context.Context) ControlPlaneInterface {
+	switch cp := getControlPlane().(type) {
+	case v1.CPv1:
+		return cp.WithContext(ctx)
+	case v1rest.CPv1RestApi:
+		return cp.WithContext(ctx)
+	case fake.CPFake:
+		return cp.WithContext(ctx)
+	default:
+		logf.Log.Info("control plane does not support contexts, operations are not bounded by the context", "controlplane", fmt.Sprintf("%T", cp))
+		return cp
+	}
+}
+
 func VolStateHealthy() string {
 	return getControlPlane().VolStateHealthy()
 }
@@ -246,6 +340,12 @@ func ListMsvs() ([]common.MayastorVolume, error) {
 	return getControlPlane().ListMsvs()
 }
 
+// ForEachMsv calls fn for each volume without listing all volumes at once
+// where the control plane backend supports it, iteration stops on the first error
+func ForEachMsv(fn func(common.MayastorVolume) error) error {
+	return getControlPlane().ForEachMsv(fn)
+}
+
 func ListRestoredMsvs() ([]common.MayastorVolume, error) {
 	return getControlPlane().ListRestoredMsvs()
 }
@@ -306,6 +406,13 @@ func GetMsvDeviceUri(uuid string) (string, error) {
 	return getControlPlane().GetMsvDeviceUri(uuid)
 }
 
+// WatchMsv delivers the changes of the spec and state of a volume on a channel,
+// the watch must be stopped by the caller. The volume state is sampled every
+// e2e_config WatchResyncInterval, transitions between samples are missed.
+func WatchMsv(uuid string) (*watch.MsvWatch, error) {
+	return getControlPlane().WatchMsv(uuid)
+}
+
 func SetVolumeMaxSnapshotCount(uuid string, maxSnapshotCount int32) error {
 	return getControlPlane().SetVolumeMaxSnapshotCount(uuid, maxSnapshotCount)
 }
@@ -338,6 +445,23 @@ func UpdateNodeLabel(nodeName string, labelKey, labelValue string) error {
 	return getControlPlane().UpdateNodeLabel(nodeName, labelKey, labelValue)
 }
 
+// ListNodeBlockDevices returns the block devices of the node which are available
+// for use by pools, or all block devices of the node if all is true
+func ListNodeBlockDevices(nodeName string, all bool) ([]common.BlockDevice, error) {
+	return getControlPlane().ListNodeBlockDevices(nodeName, all)
+}
+
+// GetAppNode returns the app node registered with the control plane
+func GetAppNode(id string) (*common.AppNode, error) {
+	return getControlPlane().GetAppNode(id)
+}
+
+// ListAppNodes returns the app nodes registered with the control plane,
+// app nodes are registered by the CSI node plugin of the nodes
+func ListAppNodes() ([]common.AppNode, error) {
+	return getControlPlane().ListAppNodes()
+}
+
 func NodeStateOnline() string {
 	return getControlPlane().NodeStateOnline()
 }
@@ -457,3 +581,36 @@ func GetVolumeSnapshotTopology() ([]common.SnapshotSchema, error) {
 func GetPerSnapshotVolumeSnapshotTopology(snapshotId string) (common.SnapshotSchema, error) {
 	return getControlPlane().GetPerSnapshotVolumeSnapshotTopology(snapshotId)
 }
+
+// ListNexuses returns the nexuses on the node, or on all nodes if node is ""
+func ListNexuses(node string) ([]common.MayastorNexus, error) {
+	return getControlPlane().ListNexuses(node)
+}
+
+func GetNexus(uuid string) (*common.MayastorNexus, error) {
+	return getControlPlane().GetNexus(uuid)
+}
+
+// AddNexusChild adds the child with the uri, eg: a replica uri, to the nexus
+func AddNexusChild(uuid string, childUri string) error {
+	return getControlPlane().AddNexusChild(uuid, childUri)
+}
+
+func RemoveNexusChild(uuid string, childUri string) error {
+	return getControlPlane().RemoveNexusChild(uuid, childUri)
+}
+
+// FaultNexusChild faults a child of the nexus, not all control plane backends support it,
+// unsupported nexus operations fail with cperrors.ErrUnsupported
+func FaultNexusChild(uuid string, childUri string) error {
+	return getControlPlane().FaultNexusChild(uuid, childUri)
+}
+
+// ShareNexus shares the nexus over nvmf, returning the uri of the share
+func ShareNexus(uuid string) (string, error) {
+	return getControlPlane().ShareNexus(uuid)
+}
+
+func UnshareNexus(uuid string) error {
+	return getControlPlane().UnshareNexus(uuid)
+}
@@ -0,0 +1,121 @@
+// Package cperrors defines the kinds of error reported by the control plane.
+// The control plane backends classify the errors they return, so that the
+// kind of an error is tested with errors.Is whichever backend is in use, eg:
+//
+//	if errors.Is(err, cperrors.ErrNotFound) {
+//		// already deleted
+//	}
+package cperrors
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+)
+
+var (
+	ErrNotFound             = errors.New("not found")
+	ErrAlreadyExists        = errors.New("already exists")
+	ErrConflict             = errors.New("conflict")
+	ErrTimeout              = errors.New("timeout")
+	ErrUnavailable          = errors.New("unavailable")
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrInsufficientCapacity = errors.New("insufficient capacity")
+	ErrUnsupported          = errors.New("unsupported")
+)
+
+// Error is an error of the control plane classified by kind, the message is
+// that of the wrapped error so that existing checks of messages are unaffected
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Wrap classifies err as kind, err is returned unchanged if either is nil
+func Wrap(err error, kind error) error {
+	if err == nil || kind == nil {
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// restKinds maps the kinds of the control plane RestJsonError to error kinds,
+// kinds which are not listed are not classified
+var restKinds = map[string]error{
+	"NotFound":              ErrNotFound,
+	"AlreadyExists":         ErrAlreadyExists,
+	"AlreadyShared":         ErrAlreadyExists,
+	"AlreadyPublished":      ErrAlreadyExists,
+	"Conflict":              ErrConflict,
+	"Aborted":               ErrConflict,
+	"InUse":                 ErrConflict,
+	"Deleting":              ErrConflict,
+	"Timeout":               ErrTimeout,
+	"DeadlineExceeded":      ErrTimeout,
+	"Unavailable":           ErrUnavailable,
+	"FailedPrecondition":    ErrPreconditionFailed,
+	"NotShared":             ErrPreconditionFailed,
+	"NotPublished":          ErrPreconditionFailed,
+	"ResourceExhausted":     ErrInsufficientCapacity,
+	"CapacityLimitExceeded": ErrInsufficientCapacity,
+}
+
+// KindFromRest returns the error kind of a RestJsonError kind, or nil
+func KindFromRest(restKind string) error {
+	return restKinds[restKind]
+}
+
+// KindFromStatusCode returns the error kind of an HTTP status code, or nil
+func KindFromStatusCode(statusCode int) error {
+	switch statusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrTimeout
+	case http.StatusServiceUnavailable:
+		return ErrUnavailable
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusInsufficientStorage:
+		return ErrInsufficientCapacity
+	}
+	return nil
+}
+
+// FromRest classifies an error of the REST API by the kind of the RestJsonError
+// returned, or failing that by the status code of the response
+func FromRest(err error, restKind string, statusCode int) error {
+	kind := KindFromRest(restKind)
+	if kind == nil {
+		kind = KindFromStatusCode(statusCode)
+	}
+	return Wrap(err, kind)
+}
+
+var pluginKindRe = regexp.MustCompile(`RestJsonError.*kind:\s*(\w+)`)
+
+// FromPluginOutput classifies an error of the kubectl plugin by the kind of the
+// RestJsonError in the output of the plugin, eg:
+// Error error in response: status code '404 Not Found', content: 'RestJsonError { details: "...", message: "...", kind: NotFound }'
+func FromPluginOutput(err error, output string) error {
+	frags := pluginKindRe.FindStringSubmatch(output)
+	if len(frags) != 2 {
+		return err
+	}
+	return Wrap(err, KindFromRest(frags[1]))
+}
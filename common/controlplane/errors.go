@@ -0,0 +1,34 @@
+package controlplane
+
+import (
+	"errors"
+
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+)
+
+// Kinds of control plane error, the errors returned by the control plane
+// are tested with errors.Is, eg: errors.Is(err, controlplane.ErrInsufficientCapacity)
+var (
+	ErrNotFound             = cperrors.ErrNotFound
+	ErrAlreadyExists        = cperrors.ErrAlreadyExists
+	ErrConflict             = cperrors.ErrConflict
+	ErrTimeout              = cperrors.ErrTimeout
+	ErrUnavailable          = cperrors.ErrUnavailable
+	ErrPreconditionFailed   = cperrors.ErrPreconditionFailed
+	ErrInsufficientCapacity = cperrors.ErrInsufficientCapacity
+	ErrUnsupported          = cperrors.ErrUnsupported
+)
+
+// IsNotFound returns true if the error is a control plane NotFound error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IgnoreNotFound returns nil if the error is a control plane NotFound error,
+// so that cleanup code treats resources which no longer exist as deleted
+func IgnoreNotFound(err error) error {
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
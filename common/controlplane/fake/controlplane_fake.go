@@ -0,0 +1,582 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+	"github.com/openebs/openebs-e2e/common/generated/openapi"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrTimeout is reported as a timeout by IsTimeoutError, see State.InjectError
+var ErrTimeout = cperrors.Wrap(errors.New("fake control plane timeout"), cperrors.ErrTimeout)
+
+// errorOf returns an error of the kind, see cperrors
+func errorOf(kind error, format string, args ...interface{}) error {
+	return cperrors.Wrap(fmt.Errorf(format, args...), kind)
+}
+
+// CPFake implements the control plane interface on an in-memory state,
+// the resource state strings are those of the REST API
+type CPFake struct {
+	state *State
+	// ctx bounds the calls of the control plane, calls fail once ctx is done
+	ctx context.Context
+}
+
+// MakeCP makes a control plane object which uses the state
+func MakeCP(state *State) CPFake {
+	logf.Log.Info("Control Plane v1 - Fake")
+	return CPFake{state: state, ctx: context.Background()}
+}
+
+// WithContext returns the control plane with its calls bounded by ctx
+func (cp CPFake) WithContext(ctx context.Context) CPFake {
+	cp.ctx = ctx
+	return cp
+}
+
+// poll records a read of the state, failing if the context of the control plane is done
+func (cp CPFake) poll() error {
+	if cp.ctx != nil && cp.ctx.Err() != nil {
+		if errors.Is(cp.ctx.Err(), context.DeadlineExceeded) {
+			return cperrors.Wrap(cp.ctx.Err(), cperrors.ErrTimeout)
+		}
+		return cp.ctx.Err()
+	}
+	return cp.state.poll()
+}
+
+// State returns the state of the control plane
+func (cp CPFake) State() *State {
+	return cp.state
+}
+
+func (cp CPFake) Version() string {
+	return "1.0.0"
+}
+
+func (cp CPFake) MajorVersion() int {
+	return 1
+}
+
+func (cp CPFake) IsTimeoutError(err error) bool {
+	return errors.Is(err, cperrors.ErrTimeout)
+}
+
+func (cp CPFake) VolStateHealthy() string {
+	return string(openapi.VOLUMESTATUS_ONLINE)
+}
+
+func (cp CPFake) VolStateUnknown() string {
+	return string(openapi.VOLUMESTATUS_UNKNOWN)
+}
+
+func (cp CPFake) VolStateDegraded() string {
+	return string(openapi.VOLUMESTATUS_DEGRADED)
+}
+
+func (cp CPFake) VolStateFaulted() string {
+	return string(openapi.VOLUMESTATUS_FAULTED)
+}
+
+func (cp CPFake) ChildStateOnline() string {
+	return string(openapi.CHILDSTATE_ONLINE)
+}
+
+func (cp CPFake) ChildStateDegraded() string {
+	return string(openapi.CHILDSTATE_DEGRADED)
+}
+
+func (cp CPFake) ChildStateUnknown() string {
+	return string(openapi.CHILDSTATE_UNKNOWN)
+}
+
+func (cp CPFake) ChildStateFaulted() string {
+	return string(openapi.CHILDSTATE_FAULTED)
+}
+
+func (cp CPFake) NexusStateUnknown() string {
+	return string(openapi.NEXUSSTATE_UNKNOWN)
+}
+
+func (cp CPFake) NexusStateOnline() string {
+	return string(openapi.NEXUSSTATE_ONLINE)
+}
+
+func (cp CPFake) NexusStateDegraded() string {
+	return string(openapi.NEXUSSTATE_DEGRADED)
+}
+
+func (cp CPFake) NexusStateFaulted() string {
+	return string(openapi.NEXUSSTATE_FAULTED)
+}
+
+func (cp CPFake) MspStateOnline() string {
+	return string(openapi.POOLSTATUS_ONLINE)
+}
+
+func (cp CPFake) NodeStateOffline() string {
+	return string(openapi.NODESTATUS_OFFLINE)
+}
+
+func (cp CPFake) NodeStateOnline() string {
+	return string(openapi.NODESTATUS_ONLINE)
+}
+
+func (cp CPFake) NodeStateUnknown() string {
+	return string(openapi.NODESTATUS_UNKNOWN)
+}
+
+func (cp CPFake) NodeStateEmpty() string {
+	return ""
+}
+
+func (cp CPFake) ReplicaStateUnknown() string {
+	return string(openapi.REPLICASTATE_UNKNOWN)
+}
+
+func (cp CPFake) ReplicaStateOnline() string {
+	return string(openapi.REPLICASTATE_ONLINE)
+}
+
+func (cp CPFake) ReplicaStateDegraded() string {
+	return string(openapi.REPLICASTATE_DEGRADED)
+}
+
+func (cp CPFake) ReplicaStateFaulted() string {
+	return string(openapi.REPLICASTATE_FAULTED)
+}
+
+// getVolume returns the volume after recording the read
+func (cp CPFake) getVolume(uuid string) (common.MayastorVolume, error) {
+	if err := cp.poll(); err != nil {
+		return common.MayastorVolume{}, err
+	}
+	vol, ok := cp.state.getVolume(uuid)
+	if !ok {
+		return vol, errorOf(cperrors.ErrNotFound, "volume %s not found", uuid)
+	}
+	return vol, nil
+}
+
+// listVolumes returns the volumes after recording the read
+func (cp CPFake) listVolumes() ([]common.MayastorVolume, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	return cp.state.listVolumes(), nil
+}
+
+// GetMSV returns nil and no error if the volume is being created
+func (cp CPFake) GetMSV(uuid string) (*common.MayastorVolume, error) {
+	vol, err := cp.getVolume(uuid)
+	if err != nil {
+		return nil, fmt.Errorf("GetMSV: %v", err)
+	}
+	if vol.Spec.Status == string(openapi.CREATING) {
+		return nil, nil
+	}
+	return &vol, nil
+}
+
+func (cp CPFake) GetMsvNodes(uuid string) (string, []string) {
+	var replicaNodes []string
+	vol, err := cp.getVolume(uuid)
+	if err != nil {
+		return "", nil
+	}
+	for _, replica := range vol.State.ReplicaTopology {
+		replicaNodes = append(replicaNodes, replica.Node)
+	}
+	return vol.State.Target.Node, replicaNodes
+}
+
+func (cp CPFake) CanDeleteMsv() bool {
+	return true
+}
+
+func (cp CPFake) DeleteMsv(uuid string) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	if !cp.state.RemoveVolume(uuid) {
+		return errorOf(cperrors.ErrNotFound, "volume %s not found", uuid)
+	}
+	return nil
+}
+
+func (cp CPFake) ListMsvs() ([]common.MayastorVolume, error) {
+	return cp.listVolumes()
+}
+
+func (cp CPFake) ForEachMsv(fn func(common.MayastorVolume) error) error {
+	msvs, err := cp.listVolumes()
+	if err != nil {
+		return err
+	}
+	for _, msv := range msvs {
+		if err = fn(msv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cp CPFake) ListRestoredMsvs() ([]common.MayastorVolume, error) {
+	var restored []common.MayastorVolume
+	vols, err := cp.listVolumes()
+	for _, vol := range vols {
+		if vol.Spec.ContentSource.Snapshot.Snapshot != "" {
+			restored = append(restored, vol)
+		}
+	}
+	return restored, err
+}
+
+// SetMsvReplicaCount changes the replica count of the volume spec,
+// the replicas are added or removed by scripted steps
+func (cp CPFake) SetMsvReplicaCount(uuid string, replicaCount int) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	return cp.state.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		vol.Spec.Num_replicas = replicaCount
+	})
+}
+
+func (cp CPFake) GetMsvState(uuid string) (string, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Status, err
+}
+
+func (cp CPFake) GetMsvReplicas(uuid string) ([]common.MsvReplica, error) {
+	var replicas []common.MsvReplica
+	vol, err := cp.getVolume(uuid)
+	if err != nil {
+		return nil, err
+	}
+	for replicaUuid, replica := range vol.State.ReplicaTopology {
+		replicas = append(replicas, common.MsvReplica{
+			Uuid:    replicaUuid,
+			Uri:     ChildUri(replica, replicaUuid),
+			Replica: replica,
+		})
+	}
+	return replicas, nil
+}
+
+func (cp CPFake) GetMsvReplicaTopology(uuid string) (common.ReplicaTopology, error) {
+	vol, err := cp.getVolume(uuid)
+	if err != nil {
+		return nil, err
+	}
+	return vol.State.ReplicaTopology, nil
+}
+
+func (cp CPFake) GetMsvNexusChildren(uuid string) ([]common.TargetChild, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Target.Children, err
+}
+
+func (cp CPFake) GetMsvNexusState(uuid string) (string, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Target.State, err
+}
+
+func (cp CPFake) IsMsvPublished(uuid string) bool {
+	vol, err := cp.getVolume(uuid)
+	return err == nil && vol.Spec.Target.Node != ""
+}
+
+func (cp CPFake) IsMsvDeleted(uuid string) bool {
+	if err := cp.poll(); err != nil {
+		return false
+	}
+	_, ok := cp.state.getVolume(uuid)
+	return !ok
+}
+
+func (cp CPFake) CheckForMsvs() (bool, error) {
+	vols, err := cp.listVolumes()
+	return err == nil && len(vols) != 0, err
+}
+
+func (cp CPFake) CheckAllMsvsAreHealthy() error {
+	vols, err := cp.listVolumes()
+	if err != nil {
+		return err
+	}
+	allHealthy := true
+	for _, vol := range vols {
+		if vol.State.Status != cp.VolStateHealthy() {
+			allHealthy = false
+			logf.Log.Info("CheckAllMsvsAreHealthy", "vol", vol)
+		}
+	}
+	if !allHealthy {
+		return fmt.Errorf("all MSVs were not healthy")
+	}
+	return nil
+}
+
+func (cp CPFake) GetMsvTargetNode(uuid string) (string, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Target.Node, err
+}
+
+func (cp CPFake) GetMsvTargetUuid(uuid string) (string, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Target.Uuid, err
+}
+
+func (cp CPFake) GetMsvSize(uuid string) (int64, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Size, err
+}
+
+func (cp CPFake) GetMsvDeviceUri(uuid string) (string, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.State.Target.DeviceUri, err
+}
+
+func (cp CPFake) SetVolumeMaxSnapshotCount(uuid string, maxSnapshotCount int32) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	return cp.state.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		vol.Spec.MaxSnapshots = maxSnapshotCount
+	})
+}
+
+func (cp CPFake) GetMsvMaxSnapshotCount(uuid string) (int32, error) {
+	vol, err := cp.getVolume(uuid)
+	return vol.Spec.MaxSnapshots, err
+}
+
+func (cp CPFake) GetMSN(nodeName string) (*common.MayastorNode, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	node, ok := cp.state.getNode(nodeName)
+	if !ok {
+		return nil, errorOf(cperrors.ErrNotFound, "node %s not found", nodeName)
+	}
+	return &node, nil
+}
+
+func (cp CPFake) ListMsns() ([]common.MayastorNode, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	return cp.state.listNodes(), nil
+}
+
+func (cp CPFake) GetMsNodeStatus(nodeName string) (string, error) {
+	node, err := cp.GetMSN(nodeName)
+	if err != nil {
+		return "", err
+	}
+	return node.State.Status, nil
+}
+
+// UpdateNodeLabel only checks that the node exists, the fake does not keep node labels
+func (cp CPFake) UpdateNodeLabel(nodeName string, labelKey, labelValue string) error {
+	_, err := cp.GetMSN(nodeName)
+	return err
+}
+
+func (cp CPFake) CreatePoolOnInstall() bool {
+	return true
+}
+
+func (cp CPFake) GetMsPool(poolName string) (*common.MayastorPool, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	pool, ok := cp.state.getPool(poolName)
+	if !ok {
+		return nil, errorOf(cperrors.ErrNotFound, "pool %s not found", poolName)
+	}
+	return &pool, nil
+}
+
+func (cp CPFake) ListMsPools() ([]common.MayastorPool, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	return cp.state.listPools(), nil
+}
+
+func (cp CPFake) CordonNode(nodeName string, cordonLabel string) error {
+	if _, err := cp.GetMSN(nodeName); err != nil {
+		return err
+	}
+	cp.state.mutex.Lock()
+	defer cp.state.mutex.Unlock()
+	for _, label := range cp.state.cordons[nodeName] {
+		if label == cordonLabel {
+			return fmt.Errorf("node %s is already cordoned with label %s", nodeName, cordonLabel)
+		}
+	}
+	cp.state.cordons[nodeName] = append(cp.state.cordons[nodeName], cordonLabel)
+	return nil
+}
+
+func (cp CPFake) GetCordonNodeLabels(nodeName string) ([]string, error) {
+	if _, err := cp.GetMSN(nodeName); err != nil {
+		return nil, err
+	}
+	cp.state.mutex.Lock()
+	defer cp.state.mutex.Unlock()
+	return append([]string{}, cp.state.cordons[nodeName]...), nil
+}
+
+// UnCordonNode removes a cordon or drain label from the node
+func (cp CPFake) UnCordonNode(nodeName string, cordonLabel string) error {
+	if _, err := cp.GetMSN(nodeName); err != nil {
+		return err
+	}
+	cp.state.mutex.Lock()
+	defer cp.state.mutex.Unlock()
+	for _, labels := range []map[string][]string{cp.state.cordons, cp.state.drains} {
+		for ix, label := range labels[nodeName] {
+			if label == cordonLabel {
+				labels[nodeName] = append(labels[nodeName][:ix:ix], labels[nodeName][ix+1:]...)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("node %s is not cordoned with label %s", nodeName, cordonLabel)
+}
+
+// DrainNode drains the node immediately
+func (cp CPFake) DrainNode(nodeName string, drainLabel string, drainTimeOut int) error {
+	if _, err := cp.GetMSN(nodeName); err != nil {
+		return err
+	}
+	cp.state.mutex.Lock()
+	defer cp.state.mutex.Unlock()
+	cp.state.drains[nodeName] = append(cp.state.drains[nodeName], drainLabel)
+	return nil
+}
+
+// GetDrainNodeLabels returns draining, drained labels and error,
+// nodes are never draining as the fake drains them immediately
+func (cp CPFake) GetDrainNodeLabels(nodeName string) ([]string, []string, error) {
+	if _, err := cp.GetMSN(nodeName); err != nil {
+		return nil, nil, err
+	}
+	cp.state.mutex.Lock()
+	defer cp.state.mutex.Unlock()
+	return []string{}, append([]string{}, cp.state.drains[nodeName]...), nil
+}
+
+func (cp CPFake) Upgrade(isUpgradingToUnstableBranch, isPartialRebuildDisableNeeded bool) (string, error) {
+	return "", fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) UpgradeWithSkipDataPlaneRestart(isUpgradingToUnstableBranch, isPartialRebuildDisableNeeded bool) error {
+	return fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) UpgradeWithSkipReplicaRebuild(isUpgradingToUnstableBranch, isPartialRebuildDisableNeeded bool) error {
+	return fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) UpgradeWithSkipCordonNodeValidation(isUpgradingToUnstableBranch, isPartialRebuildDisableNeeded bool) error {
+	return fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) UpgradeWithSkipSingleReplicaValidation(isUpgradingToUnstableBranch, isPartialRebuildDisableNeeded bool) error {
+	return fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) GetUpgradeStatus() (string, error) {
+	return "", fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) GetToUpgradeVersion() (string, error) {
+	return "", fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) DeleteUpgrade() error {
+	return fmt.Errorf("upgrade is not supported by the fake control plane")
+}
+
+func (cp CPFake) GetSnapshots() ([]common.SnapshotSchema, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	return cp.state.listSnapshots(), nil
+}
+
+func (cp CPFake) GetSnapshot(snapshotId string) (common.SnapshotSchema, error) {
+	if err := cp.poll(); err != nil {
+		return common.SnapshotSchema{}, err
+	}
+	snapshot, ok := cp.state.getSnapshot(snapshotId)
+	if !ok {
+		return snapshot, errorOf(cperrors.ErrNotFound, "snapshot not found, snapshot: %s", snapshotId)
+	}
+	return snapshot, nil
+}
+
+func (cp CPFake) GetVolumeSnapshots(volUuid string) ([]common.SnapshotSchema, error) {
+	var volSnapshots []common.SnapshotSchema
+	snapshots, err := cp.GetSnapshots()
+	for _, snapshot := range snapshots {
+		if snapshot.Definition.Spec.SourceVolume == volUuid {
+			volSnapshots = append(volSnapshots, snapshot)
+		}
+	}
+	return volSnapshots, err
+}
+
+func (cp CPFake) GetVolumeSnapshot(volUuid string, snapshotId string) (common.SnapshotSchema, error) {
+	snapshot, err := cp.GetSnapshot(snapshotId)
+	if err == nil && snapshot.Definition.Spec.SourceVolume != volUuid {
+		return common.SnapshotSchema{}, errorOf(cperrors.ErrNotFound, "snapshot %s of volume %s not found", snapshotId, volUuid)
+	}
+	return snapshot, err
+}
+
+func (cp CPFake) GetVolumeSnapshotTopology() ([]common.SnapshotSchema, error) {
+	return cp.GetSnapshots()
+}
+
+func (cp CPFake) GetPerSnapshotVolumeSnapshotTopology(snapshotId string) (common.SnapshotSchema, error) {
+	return cp.GetSnapshot(snapshotId)
+}
+
+func (cp CPFake) ListNodeBlockDevices(nodeName string, all bool) ([]common.BlockDevice, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	if _, ok := cp.state.getNode(nodeName); !ok {
+		return nil, errorOf(cperrors.ErrNotFound, "node %s not found", nodeName)
+	}
+	return cp.state.listBlockDevices(nodeName, all), nil
+}
+
+func (cp CPFake) GetAppNode(id string) (*common.AppNode, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	appNode, ok := cp.state.getAppNode(id)
+	if !ok {
+		return nil, errorOf(cperrors.ErrNotFound, "app node %s not found", id)
+	}
+	return &appNode, nil
+}
+
+func (cp CPFake) ListAppNodes() ([]common.AppNode, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	return cp.state.listAppNodes(), nil
+}
@@ -0,0 +1,148 @@
+package fake_test
+
+import (
+	"testing"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane"
+	"github.com/openebs/openebs-e2e/common/controlplane/fake"
+	"github.com/openebs/openebs-e2e/common/generated/openapi"
+	"github.com/openebs/openebs-e2e/common/mayastor/partial_rebuild"
+)
+
+const (
+	volUuid      = "0c08667c-8b59-4a2c-9a5d-1e0e5f0b5c11"
+	replicaUuid1 = "5a1b6b4e-0d7f-4c55-8f1e-3c1f2a4b7d01"
+	replicaUuid2 = "5a1b6b4e-0d7f-4c55-8f1e-3c1f2a4b7d02"
+	volSize      = 1 << 30
+)
+
+// the control plane selections of the fake, see e2e_config ControlPlane
+var selections = []string{"fake", "fake-rest"}
+
+// useFake selects the fake control plane on a new state for the duration of the test
+func useFake(t *testing.T, selection string) *fake.State {
+	state := fake.NewState()
+	restore, err := controlplane.UseFakeControlPlane(selection, state)
+	if err != nil {
+		t.Fatalf("failed to use the %s control plane: %v", selection, err)
+	}
+	t.Cleanup(restore)
+	state.AddNode(fake.OnlineNode("node-1"))
+	state.AddNode(fake.OnlineNode("node-2"))
+	state.AddPool(fake.OnlinePool("pool-1", "node-1", "/dev/sdb", 10*volSize))
+	state.AddPool(fake.OnlinePool("pool-2", "node-2", "/dev/sdb", 10*volSize))
+	state.AddVolume(fake.HealthyVolume(volUuid, volSize, "node-1",
+		fake.VolumeReplica{Uuid: replicaUuid1, Node: "node-1", Pool: "pool-1"},
+		fake.VolumeReplica{Uuid: replicaUuid2, Node: "node-2", Pool: "pool-2"},
+	))
+	return state
+}
+
+// startRebuild degrades the volume to rebuild the child of the second replica, the nexus
+// has only the child of the first replica until the rebuild starts
+func startRebuild(t *testing.T, state *fake.State) {
+	err := state.UpdateVolume(volUuid, func(vol *common.MayastorVolume) {
+		vol.State.Status = string(openapi.VOLUMESTATUS_DEGRADED)
+		vol.State.Target.State = string(openapi.NEXUSSTATE_DEGRADED)
+		vol.State.Target.Rebuilds = 1
+		vol.State.Target.Children = vol.State.Target.Children[:1]
+	})
+	if err != nil {
+		t.Fatalf("failed to degrade volume: %v", err)
+	}
+}
+
+func addRebuildingChild(s *fake.State) {
+	_ = s.UpdateVolume(volUuid, func(vol *common.MayastorVolume) {
+		progress := int32(50)
+		vol.State.Target.Children = append(vol.State.Target.Children, common.TargetChild{
+			State:           string(openapi.CHILDSTATE_DEGRADED),
+			Uri:             fake.ChildUri(vol.State.ReplicaTopology[replicaUuid2], replicaUuid2),
+			RebuildProgress: &progress,
+		})
+	})
+}
+
+func completeRebuild(s *fake.State) {
+	_ = s.SetChildState(volUuid, replicaUuid2, string(openapi.CHILDSTATE_ONLINE), nil)
+	_ = s.UpdateVolume(volUuid, func(vol *common.MayastorVolume) {
+		vol.State.Status = string(openapi.VOLUMESTATUS_ONLINE)
+		vol.State.Target.State = string(openapi.NEXUSSTATE_ONLINE)
+		vol.State.Target.Rebuilds = 0
+	})
+}
+
+func TestWaitForRebuildComplete(t *testing.T) {
+	for _, selection := range selections {
+		t.Run(selection, func(t *testing.T) {
+			state := useFake(t, selection)
+			startRebuild(t, state)
+			state.Script(
+				fake.Step{AfterPolls: 1, Apply: addRebuildingChild},
+				fake.Step{AfterPolls: 1, Apply: completeRebuild},
+			)
+
+			complete, err := partial_rebuild.WaitForRebuildComplete(volUuid, 30)
+			if err != nil {
+				t.Fatalf("WaitForRebuildComplete failed: %v", err)
+			}
+			if !complete {
+				t.Fatalf("rebuild of volume %s did not complete", volUuid)
+			}
+			if !state.ScriptDone() {
+				t.Fatalf("rebuild completed before all steps were applied")
+			}
+		})
+	}
+}
+
+func TestWaitForRebuildCompleteTimeout(t *testing.T) {
+	for _, selection := range selections {
+		t.Run(selection, func(t *testing.T) {
+			state := useFake(t, selection)
+			startRebuild(t, state)
+			state.Script(fake.Step{AfterPolls: 1, Apply: addRebuildingChild})
+
+			complete, err := partial_rebuild.WaitForRebuildComplete(volUuid, 3)
+			if err != nil {
+				t.Fatalf("WaitForRebuildComplete failed: %v", err)
+			}
+			if complete {
+				t.Fatalf("rebuild of volume %s completed with a degraded child", volUuid)
+			}
+		})
+	}
+}
+
+func TestCheckAllMsvsAreHealthy(t *testing.T) {
+	for _, selection := range selections {
+		t.Run(selection, func(t *testing.T) {
+			state := useFake(t, selection)
+			if err := controlplane.CheckAllMsvsAreHealthy(); err != nil {
+				t.Fatalf("healthy volume reported as not healthy: %v", err)
+			}
+
+			startRebuild(t, state)
+			state.Script(
+				fake.Step{AfterPolls: 2, Apply: addRebuildingChild},
+				fake.Step{AfterPolls: 2, Apply: completeRebuild},
+			)
+			if err := controlplane.CheckAllMsvsAreHealthy(); err == nil {
+				t.Fatalf("degraded volume reported as healthy")
+			}
+
+			// the REST client may read the state more than once per check,
+			// so check until all scripted steps have been applied
+			for ix := 0; ix < 8 && !state.ScriptDone(); ix++ {
+				_ = controlplane.CheckAllMsvsAreHealthy()
+			}
+			if !state.ScriptDone() {
+				t.Fatalf("scripted steps were not applied, polls %d", state.Polls())
+			}
+			if err := controlplane.CheckAllMsvsAreHealthy(); err != nil {
+				t.Fatalf("volume reported as not healthy after rebuild: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package fake
+
+import (
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+	"github.com/openebs/openebs-e2e/common/generated/openapi"
+)
+
+// nexusDeviceUri returns the uri of a nexus shared over nvmf
+func nexusDeviceUri(node string, uuid string) string {
+	return fmt.Sprintf("nvmf://%s:8420/nqn.2019-05.io.openebs:%s", node, uuid)
+}
+
+// listNexuses returns the targets of the volumes on the node, or on all nodes if node is ""
+func (s *State) listNexuses(node string) []common.MayastorNexus {
+	var nexuses []common.MayastorNexus
+	for _, vol := range s.listVolumes() {
+		target := vol.State.Target
+		if target.Uuid != "" && (node == "" || target.Node == node) {
+			nexuses = append(nexuses, target)
+		}
+	}
+	return nexuses
+}
+
+func (s *State) getNexus(uuid string) (common.MayastorNexus, bool) {
+	for _, nexus := range s.listNexuses("") {
+		if nexus.Uuid == uuid {
+			return nexus, true
+		}
+	}
+	return common.MayastorNexus{}, false
+}
+
+// updateNexus changes the volume which has the nexus as target
+func (s *State) updateNexus(uuid string, update func(vol *common.MayastorVolume) error) error {
+	for _, vol := range s.listVolumes() {
+		if vol.State.Target.Uuid != uuid {
+			continue
+		}
+		var err error
+		if updateErr := s.UpdateVolume(vol.Spec.Uuid, func(vol *common.MayastorVolume) {
+			err = update(vol)
+		}); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	return errorOf(cperrors.ErrNotFound, "nexus %s not found", uuid)
+}
+
+// degradeNexus marks the nexus and its volume as degraded
+func degradeNexus(vol *common.MayastorVolume) {
+	vol.State.Target.State = string(openapi.NEXUSSTATE_DEGRADED)
+	vol.State.Status = string(openapi.VOLUMESTATUS_DEGRADED)
+}
+
+// AddNexusChild adds a child to a nexus, the child is degraded until it is rebuilt, see SetChildState
+func (s *State) AddNexusChild(uuid string, childUri string) error {
+	return s.updateNexus(uuid, func(vol *common.MayastorVolume) error {
+		for _, child := range vol.State.Target.Children {
+			if child.Uri == childUri {
+				return errorOf(cperrors.ErrAlreadyExists, "nexus %s already has child %s", uuid, childUri)
+			}
+		}
+		progress := int32(0)
+		vol.State.Target.Children = append(vol.State.Target.Children, common.TargetChild{
+			State:           string(openapi.CHILDSTATE_DEGRADED),
+			Uri:             childUri,
+			RebuildProgress: &progress,
+		})
+		vol.State.Target.Rebuilds++
+		degradeNexus(vol)
+		return nil
+	})
+}
+
+// RemoveNexusChild removes a child from a nexus
+func (s *State) RemoveNexusChild(uuid string, childUri string) error {
+	return s.updateNexus(uuid, func(vol *common.MayastorVolume) error {
+		children := vol.State.Target.Children
+		for ix, child := range children {
+			if child.Uri == childUri {
+				vol.State.Target.Children = append(children[:ix:ix], children[ix+1:]...)
+				return nil
+			}
+		}
+		return errorOf(cperrors.ErrNotFound, "nexus %s has no child %s", uuid, childUri)
+	})
+}
+
+// FaultNexusChild faults a child of a nexus, the nexus is degraded
+func (s *State) FaultNexusChild(uuid string, childUri string) error {
+	return s.updateNexus(uuid, func(vol *common.MayastorVolume) error {
+		children := vol.State.Target.Children
+		for ix := range children {
+			if children[ix].Uri == childUri {
+				children[ix].State = string(openapi.CHILDSTATE_FAULTED)
+				children[ix].RebuildProgress = nil
+				degradeNexus(vol)
+				return nil
+			}
+		}
+		return errorOf(cperrors.ErrNotFound, "nexus %s has no child %s", uuid, childUri)
+	})
+}
+
+// ShareNexus shares a nexus over nvmf, returning the uri of the share
+func (s *State) ShareNexus(uuid string) (string, error) {
+	var uri string
+	err := s.updateNexus(uuid, func(vol *common.MayastorVolume) error {
+		target := &vol.State.Target
+		uri = nexusDeviceUri(target.Node, uuid)
+		target.DeviceUri = uri
+		target.Protocol = string(openapi.PROTOCOL_NVMF)
+		return nil
+	})
+	return uri, err
+}
+
+// UnshareNexus removes the share of a nexus
+func (s *State) UnshareNexus(uuid string) error {
+	return s.updateNexus(uuid, func(vol *common.MayastorVolume) error {
+		vol.State.Target.DeviceUri = ""
+		vol.State.Target.Protocol = string(openapi.PROTOCOL_NONE)
+		return nil
+	})
+}
+
+func (cp CPFake) ListNexuses(node string) ([]common.MayastorNexus, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	return cp.state.listNexuses(node), nil
+}
+
+func (cp CPFake) GetNexus(uuid string) (*common.MayastorNexus, error) {
+	if err := cp.poll(); err != nil {
+		return nil, err
+	}
+	nexus, ok := cp.state.getNexus(uuid)
+	if !ok {
+		return nil, errorOf(cperrors.ErrNotFound, "nexus %s not found", uuid)
+	}
+	return &nexus, nil
+}
+
+func (cp CPFake) AddNexusChild(uuid string, childUri string) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	return cp.state.AddNexusChild(uuid, childUri)
+}
+
+func (cp CPFake) RemoveNexusChild(uuid string, childUri string) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	return cp.state.RemoveNexusChild(uuid, childUri)
+}
+
+func (cp CPFake) FaultNexusChild(uuid string, childUri string) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	return cp.state.FaultNexusChild(uuid, childUri)
+}
+
+func (cp CPFake) ShareNexus(uuid string) (string, error) {
+	if err := cp.poll(); err != nil {
+		return "", err
+	}
+	return cp.state.ShareNexus(uuid)
+}
+
+func (cp CPFake) UnshareNexus(uuid string) error {
+	if err := cp.poll(); err != nil {
+		return err
+	}
+	return cp.state.UnshareNexus(uuid)
+}
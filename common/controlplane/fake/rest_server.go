@@ -0,0 +1,623 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+	"github.com/openebs/openebs-e2e/common/generated/openapi"
+)
+
+// restBasePath is the base path of the REST API, see openapi.NewConfiguration
+const restBasePath = "/v0"
+
+// restError is a REST API error response
+type restError struct {
+	status int
+	kind   string
+	msg    string
+}
+
+func notFound(format string, args ...interface{}) *restError {
+	return &restError{status: http.StatusNotFound, kind: "NotFound", msg: fmt.Sprintf(format, args...)}
+}
+
+func invalidArgument(format string, args ...interface{}) *restError {
+	return &restError{status: http.StatusBadRequest, kind: "InvalidArgument", msg: fmt.Sprintf(format, args...)}
+}
+
+// restKinds are the REST API error kinds and statuses reported for the kinds of error
+var restKinds = []struct {
+	kind     error
+	status   int
+	restKind string
+}{
+	{cperrors.ErrNotFound, http.StatusNotFound, "NotFound"},
+	{cperrors.ErrAlreadyExists, http.StatusUnprocessableEntity, "AlreadyExists"},
+	{cperrors.ErrConflict, http.StatusConflict, "Conflict"},
+	{cperrors.ErrTimeout, http.StatusRequestTimeout, "Timeout"},
+	{cperrors.ErrUnavailable, http.StatusServiceUnavailable, "Unavailable"},
+	{cperrors.ErrPreconditionFailed, http.StatusPreconditionFailed, "FailedPrecondition"},
+	{cperrors.ErrInsufficientCapacity, http.StatusInsufficientStorage, "ResourceExhausted"},
+}
+
+// stateError converts an error of the state, or injected in the state, to a REST API error
+func stateError(err error) *restError {
+	for _, k := range restKinds {
+		if errors.Is(err, k.kind) {
+			return &restError{status: k.status, kind: k.restKind, msg: err.Error()}
+		}
+	}
+	return &restError{status: http.StatusInternalServerError, kind: "Internal", msg: err.Error()}
+}
+
+// restServer serves the subset of the REST API used by the test library from a State
+type restServer struct {
+	state   *State
+	mutex   sync.Mutex
+	watches map[string][]string
+}
+
+// NewRestServer starts a fake of the control plane REST API serving the state,
+// the v1 REST API control plane is pointed at it with v1_rest_api.MakeCPForHost.
+// The server must be closed by the caller.
+func NewRestServer(state *State) *httptest.Server {
+	rs := &restServer{
+		state:   state,
+		watches: make(map[string][]string),
+	}
+	state.subscribe(rs.callWatches)
+	return httptest.NewServer(rs)
+}
+
+// ServeHTTP routes the request by method and path
+func (rs *restServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, restBasePath+"/") {
+		writeRestError(w, notFound("path %s not found", r.URL.Path))
+		return
+	}
+	if err := rs.state.poll(); err != nil {
+		writeRestError(w, stateError(err))
+		return
+	}
+	// path segments are split before unescaping, a child uri is a single escaped segment
+	path := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), restBasePath+"/"), "/")
+	for ix := range path {
+		segment, err := url.PathUnescape(path[ix])
+		if err != nil {
+			writeRestError(w, invalidArgument("invalid path %s", r.URL.EscapedPath()))
+			return
+		}
+		path[ix] = segment
+	}
+	var resp interface{}
+	var rerr *restError
+	switch {
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "volumes":
+		resp, rerr = rs.getVolumes(r)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "volumes" && path[1] == "snapshots":
+		resp, rerr = rs.getSnapshots(r)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "volumes":
+		resp, rerr = rs.getVolume(path[1])
+	case r.Method == http.MethodDelete && len(path) == 2 && path[0] == "volumes":
+		rerr = rs.delVolume(path[1])
+	case r.Method == http.MethodPut && len(path) == 4 && path[0] == "volumes" && path[2] == "replica_count":
+		resp, rerr = rs.putVolumeReplicaCount(path[1], path[3])
+	case r.Method == http.MethodPut && len(path) == 3 && path[0] == "volumes" && path[2] == "property":
+		resp, rerr = rs.putVolumeProperty(path[1], r)
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "nodes":
+		resp = rs.getNodes()
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "nodes":
+		resp, rerr = rs.getNode(path[1])
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "app-nodes":
+		resp, rerr = rs.getAppNodes(r)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "app-nodes":
+		resp, rerr = rs.getAppNode(path[1])
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "pools":
+		resp = rs.getPools()
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "pools":
+		resp, rerr = rs.getPool(path[1])
+	case r.Method == http.MethodGet && len(path) == 1 && path[0] == "nexuses":
+		resp = rs.getNexuses("")
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "nexuses":
+		resp, rerr = rs.getNexus(path[1])
+	case r.Method == http.MethodGet && len(path) == 3 && path[0] == "nodes" && path[2] == "block_devices":
+		resp, rerr = rs.getNodeBlockDevices(r, path[1])
+	case r.Method == http.MethodGet && len(path) == 3 && path[0] == "nodes" && path[2] == "nexuses":
+		resp = rs.getNexuses(path[1])
+	case len(path) >= 4 && path[0] == "nodes" && path[2] == "nexuses":
+		resp, rerr = rs.nodeNexus(r, path[1], path[3], path[4:])
+	case len(path) == 3 && path[0] == "watches" && path[1] == "volumes":
+		resp, rerr = rs.volumeWatch(r, path[2])
+	default:
+		rerr = notFound("%s %s is not supported by the fake REST server", r.Method, r.URL.Path)
+	}
+	if rerr != nil {
+		writeRestError(w, rerr)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeRestError(w http.ResponseWriter, rerr *restError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rerr.status)
+	_ = json.NewEncoder(w).Encode(openapi.NewRestJsonError(rerr.msg, rerr.msg, rerr.kind))
+}
+
+// getVolumes returns a page of the volumes, or the volume volume_id
+func (rs *restServer) getVolumes(r *http.Request) (interface{}, *restError) {
+	query := r.URL.Query()
+	vols := rs.state.listVolumes()
+	if uuid := query.Get("volume_id"); uuid != "" {
+		var selected []common.MayastorVolume
+		for _, vol := range vols {
+			if vol.Spec.Uuid == uuid {
+				selected = append(selected, vol)
+			}
+		}
+		vols = selected
+	}
+	start, end, rerr := pageRange(query, len(vols))
+	if rerr != nil {
+		return nil, rerr
+	}
+	volumes := openapi.NewVolumes([]openapi.Volume{})
+	for _, vol := range vols[start:end] {
+		volumes.Entries = append(volumes.Entries, msvToVol(vol))
+	}
+	if end < len(vols) {
+		volumes.SetNextToken(int32(end))
+	}
+	return volumes, nil
+}
+
+// pageRange returns the range of a page of a list of count entries
+// selected by the max_entries and starting_token query parameters
+func pageRange(query url.Values, count int) (int, int, *restError) {
+	maxEntries, err := strconv.Atoi(query.Get("max_entries"))
+	if err != nil || maxEntries <= 0 {
+		return 0, 0, invalidArgument("invalid max_entries %s", query.Get("max_entries"))
+	}
+	start := 0
+	if token := query.Get("starting_token"); token != "" {
+		if start, err = strconv.Atoi(token); err != nil || start < 0 {
+			return 0, 0, invalidArgument("invalid starting_token %s", token)
+		}
+	}
+	if start > count {
+		start = count
+	}
+	end := start + maxEntries
+	if end > count {
+		end = count
+	}
+	return start, end, nil
+}
+
+// getSnapshots returns a page of the snapshots, selected by volume_id and snapshot_id
+func (rs *restServer) getSnapshots(r *http.Request) (interface{}, *restError) {
+	query := r.URL.Query()
+	var selected []common.SnapshotSchema
+	for _, snapshot := range rs.state.listSnapshots() {
+		if volUuid := query.Get("volume_id"); volUuid != "" && snapshot.Definition.Spec.SourceVolume != volUuid {
+			continue
+		}
+		if snapshotId := query.Get("snapshot_id"); snapshotId != "" && snapshot.Definition.Spec.UUID != snapshotId {
+			continue
+		}
+		selected = append(selected, snapshot)
+	}
+	start, end, rerr := pageRange(query, len(selected))
+	if rerr != nil {
+		return nil, rerr
+	}
+	snapshots := openapi.NewVolumeSnapshots([]openapi.VolumeSnapshot{})
+	for _, snapshot := range selected[start:end] {
+		snapshots.Entries = append(snapshots.Entries, schemaToSnapshot(snapshot))
+	}
+	if end < len(selected) {
+		snapshots.SetNextToken(int32(end))
+	}
+	return snapshots, nil
+}
+
+func (rs *restServer) getVolume(uuid string) (interface{}, *restError) {
+	vol, ok := rs.state.getVolume(uuid)
+	if !ok {
+		return nil, notFound("volume %s not found", uuid)
+	}
+	return msvToVol(vol), nil
+}
+
+func (rs *restServer) delVolume(uuid string) *restError {
+	if !rs.state.RemoveVolume(uuid) {
+		return notFound("volume %s not found", uuid)
+	}
+	return nil
+}
+
+func (rs *restServer) putVolumeReplicaCount(uuid string, count string) (interface{}, *restError) {
+	replicaCount, err := strconv.Atoi(count)
+	if err != nil || replicaCount < 1 {
+		return nil, invalidArgument("invalid replica count %s", count)
+	}
+	if err = rs.state.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		vol.Spec.Num_replicas = replicaCount
+	}); err != nil {
+		return nil, stateError(err)
+	}
+	return rs.getVolume(uuid)
+}
+
+func (rs *restServer) putVolumeProperty(uuid string, r *http.Request) (interface{}, *restError) {
+	var body openapi.SetVolumePropertyBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, invalidArgument("invalid volume property, error: %v", err)
+	}
+	if err := rs.state.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		if body.MaxSnapshots != nil {
+			vol.Spec.MaxSnapshots = *body.MaxSnapshots
+		}
+	}); err != nil {
+		return nil, stateError(err)
+	}
+	return rs.getVolume(uuid)
+}
+
+func (rs *restServer) getNodes() interface{} {
+	nodes := []openapi.Node{}
+	for _, node := range rs.state.listNodes() {
+		nodes = append(nodes, msnToNode(node))
+	}
+	return nodes
+}
+
+func (rs *restServer) getNode(name string) (interface{}, *restError) {
+	node, ok := rs.state.getNode(name)
+	if !ok {
+		return nil, notFound("node %s not found", name)
+	}
+	return msnToNode(node), nil
+}
+
+func (rs *restServer) getNodeBlockDevices(r *http.Request, name string) (interface{}, *restError) {
+	if _, ok := rs.state.getNode(name); !ok {
+		return nil, notFound("node %s not found", name)
+	}
+	all := r.URL.Query().Get("all") == "true"
+	devices := []openapi.BlockDevice{}
+	for _, device := range rs.state.listBlockDevices(name, all) {
+		devices = append(devices, bdToBlockDevice(device))
+	}
+	return devices, nil
+}
+
+// getAppNodes returns a page of the app nodes
+func (rs *restServer) getAppNodes(r *http.Request) (interface{}, *restError) {
+	entries := rs.state.listAppNodes()
+	start, end, rerr := pageRange(r.URL.Query(), len(entries))
+	if rerr != nil {
+		return nil, rerr
+	}
+	appNodes := openapi.NewAppNodes([]openapi.AppNode{})
+	for _, appNode := range entries[start:end] {
+		appNodes.Entries = append(appNodes.Entries, appNodeToRest(appNode))
+	}
+	if end < len(entries) {
+		appNodes.SetNextToken(int32(end))
+	}
+	return appNodes, nil
+}
+
+func (rs *restServer) getAppNode(id string) (interface{}, *restError) {
+	appNode, ok := rs.state.getAppNode(id)
+	if !ok {
+		return nil, notFound("app node %s not found", id)
+	}
+	return appNodeToRest(appNode), nil
+}
+
+func (rs *restServer) getPools() interface{} {
+	pools := []openapi.Pool{}
+	for _, pool := range rs.state.listPools() {
+		pools = append(pools, mspToPool(pool))
+	}
+	return pools
+}
+
+func (rs *restServer) getPool(name string) (interface{}, *restError) {
+	pool, ok := rs.state.getPool(name)
+	if !ok {
+		return nil, notFound("pool %s not found", name)
+	}
+	return mspToPool(pool), nil
+}
+
+func (rs *restServer) getNexuses(node string) interface{} {
+	nexuses := []openapi.Nexus{}
+	for _, nexus := range rs.state.listNexuses(node) {
+		nexuses = append(nexuses, msnToNexus(nexus))
+	}
+	return nexuses
+}
+
+func (rs *restServer) getNexus(uuid string) (interface{}, *restError) {
+	nexus, ok := rs.state.getNexus(uuid)
+	if !ok {
+		return nil, notFound("nexus %s not found", uuid)
+	}
+	return msnToNexus(nexus), nil
+}
+
+// nodeNexus serves the nexus on a node, its children and its share
+func (rs *restServer) nodeNexus(r *http.Request, node string, uuid string, path []string) (interface{}, *restError) {
+	nexus, ok := rs.state.getNexus(uuid)
+	if !ok || nexus.Node != node {
+		return nil, notFound("nexus %s not found on node %s", uuid, node)
+	}
+	var err error
+	switch {
+	case r.Method == http.MethodGet && len(path) == 0:
+		return msnToNexus(nexus), nil
+	case len(path) == 2 && path[0] == "children":
+		childUri := path[1]
+		switch r.Method {
+		case http.MethodPut:
+			if err = rs.state.AddNexusChild(uuid, childUri); err == nil {
+				nexus, _ = rs.state.getNexus(uuid)
+				for _, child := range nexus.Children {
+					if child.Uri == childUri {
+						return childToRest(child), nil
+					}
+				}
+			}
+		case http.MethodDelete:
+			err = rs.state.RemoveNexusChild(uuid, childUri)
+		default:
+			return nil, notFound("%s %s is not supported by the fake REST server", r.Method, r.URL.Path)
+		}
+	case r.Method == http.MethodPut && len(path) == 2 && path[0] == "share":
+		if path[1] != string(openapi.NEXUSSHAREPROTOCOL_NVMF) {
+			return nil, invalidArgument("unsupported share protocol %s", path[1])
+		}
+		var uri string
+		if uri, err = rs.state.ShareNexus(uuid); err == nil {
+			return uri, nil
+		}
+	case r.Method == http.MethodDelete && len(path) == 1 && path[0] == "share":
+		err = rs.state.UnshareNexus(uuid)
+	default:
+		return nil, notFound("%s %s is not supported by the fake REST server", r.Method, r.URL.Path)
+	}
+	if err != nil {
+		return nil, stateError(err)
+	}
+	return nil, nil
+}
+
+// volumeWatch adds, lists or deletes the callbacks of a volume
+func (rs *restServer) volumeWatch(r *http.Request, uuid string) (interface{}, *restError) {
+	if _, ok := rs.state.getVolume(uuid); !ok && r.Method != http.MethodDelete {
+		return nil, notFound("volume %s not found", uuid)
+	}
+	callback := r.URL.Query().Get("callback")
+	if callback == "" && r.Method != http.MethodGet {
+		return nil, invalidArgument("callback is required")
+	}
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	callbacks := rs.watches[uuid]
+	switch r.Method {
+	case http.MethodGet:
+		watches := []openapi.RestWatch{}
+		for _, cb := range callbacks {
+			watches = append(watches, *openapi.NewRestWatch(cb, uuid))
+		}
+		return watches, nil
+	case http.MethodPut:
+		for _, cb := range callbacks {
+			if cb == callback {
+				return nil, nil
+			}
+		}
+		rs.watches[uuid] = append(callbacks, callback)
+		return nil, nil
+	case http.MethodDelete:
+		for ix, cb := range callbacks {
+			if cb == callback {
+				rs.watches[uuid] = append(callbacks[:ix:ix], callbacks[ix+1:]...)
+				return nil, nil
+			}
+		}
+		return nil, notFound("volume %s has no watch %s", uuid, callback)
+	}
+	return nil, notFound("%s %s is not supported by the fake REST server", r.Method, r.URL.Path)
+}
+
+// callWatches calls the callbacks of a changed volume, the callbacks of a removed volume are removed
+func (rs *restServer) callWatches(uuid string) {
+	rs.mutex.Lock()
+	callbacks := rs.watches[uuid]
+	if _, ok := rs.state.getVolume(uuid); !ok {
+		delete(rs.watches, uuid)
+	}
+	rs.mutex.Unlock()
+	for _, callback := range callbacks {
+		go func(callback string) {
+			req, err := http.NewRequest(http.MethodPut, callback, nil)
+			if err != nil {
+				return
+			}
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				_ = resp.Body.Close()
+			}
+		}(callback)
+	}
+}
+
+// msvToVol converts a volume to its REST API representation
+func msvToVol(msv common.MayastorVolume) openapi.Volume {
+	spec := openapi.NewVolumeSpec(
+		int32(msv.Spec.Num_replicas),
+		msv.Spec.Size,
+		openapi.SpecStatus(msv.Spec.Status),
+		msv.Spec.Uuid,
+		*openapi.NewVolumePolicy(msv.Spec.Policy.Self_heal),
+		msv.Spec.Thin,
+		msv.Spec.NumSnapshots,
+	)
+	spec.SetAsThin(msv.Spec.AsThin)
+	spec.SetMaxSnapshots(msv.Spec.MaxSnapshots)
+	if msv.Spec.Target.Node != "" {
+		target := openapi.NewVolumeTarget(msv.Spec.Target.Node)
+		target.SetProtocol(openapi.VolumeShareProtocol(msv.Spec.Target.Protocol))
+		spec.SetTarget(*target)
+	}
+	if source := msv.Spec.ContentSource.Snapshot; source.Snapshot != "" {
+		contentSource := openapi.NewVolumeContentSource()
+		contentSource.SetSnapshot(*openapi.NewSnapshotAsSource(source.Snapshot, source.Volume))
+		spec.SetContentSource(*contentSource)
+	}
+
+	topology := make(map[string]openapi.ReplicaTopology)
+	for replicaUuid, replica := range msv.State.ReplicaTopology {
+		rt := openapi.NewReplicaTopology(openapi.ReplicaState(replica.State))
+		rt.SetNode(replica.Node)
+		rt.SetPool(replica.Pool)
+		if replica.ChildStatus != "" {
+			rt.SetChildStatus(openapi.ChildState(replica.ChildStatus))
+		}
+		rt.SetUsage(*openapi.NewReplicaUsage(replica.Usage.Capacity, replica.Usage.Allocated,
+			replica.Usage.AllocatedSnapshots, replica.Usage.AllocatedAllSnapshots))
+		topology[replicaUuid] = *rt
+	}
+	state := openapi.NewVolumeState(msv.State.Size, openapi.VolumeStatus(msv.State.Status), msv.State.Uuid, topology)
+	usage := msv.State.Usage
+	state.SetUsage(*openapi.NewVolumeUsage(usage.Capacity, usage.Allocated, usage.AllocatedReplica,
+		usage.AllocatedSnapshots, usage.AllocatedAllSnapshots, usage.TotalAllocated,
+		usage.TotalAllocatedReplicas, usage.TotalAllocatedSnapshots))
+	if target := msv.State.Target; target.Uuid != "" {
+		state.SetTarget(msnToNexus(target))
+	}
+	return *openapi.NewVolume(*spec, *state)
+}
+
+// msnToNexus converts a nexus to its REST API representation
+func msnToNexus(nexus common.MayastorNexus) openapi.Nexus {
+	children := []openapi.Child{}
+	for _, child := range nexus.Children {
+		children = append(children, childToRest(child))
+	}
+	return *openapi.NewNexus(children, nexus.DeviceUri, nexus.Node, nexus.Rebuilds,
+		openapi.Protocol(nexus.Protocol), nexus.Size, openapi.NexusState(nexus.State), nexus.Uuid)
+}
+
+// msnToNode converts a node to its REST API representation
+func msnToNode(msn common.MayastorNode) openapi.Node {
+	node := openapi.NewNode(msn.Name)
+	node.SetSpec(*openapi.NewNodeSpec(msn.Spec.GrpcEndpoint, msn.Spec.ID))
+	node.SetState(*openapi.NewNodeState(msn.State.GrpcEndpoint, msn.State.ID, openapi.NodeStatus(msn.State.Status)))
+	return *node
+}
+
+// appNodeToRest converts an app node to its REST API representation
+func appNodeToRest(appNode common.AppNode) openapi.AppNode {
+	spec := openapi.NewAppNodeSpec(appNode.Spec.Id, appNode.Spec.Endpoint)
+	if appNode.Spec.Labels != nil {
+		spec.SetLabels(appNode.Spec.Labels)
+	}
+	node := openapi.NewAppNode(appNode.Id, *spec)
+	if appNode.State.Id != "" {
+		node.SetState(*openapi.NewAppNodeState(appNode.State.Id, appNode.State.Endpoint, appNode.State.Status))
+	}
+	return *node
+}
+
+// mspToPool converts a pool to its REST API representation
+func mspToPool(msp common.MayastorPool) openapi.Pool {
+	pool := openapi.NewPool(msp.Name)
+	pool.SetSpec(*openapi.NewPoolSpec(msp.Spec.Disks, msp.Name, msp.Spec.Node, openapi.CREATED))
+	pool.SetState(*openapi.NewPoolState(int64(msp.Status.Capacity), msp.Status.Disks, msp.Name,
+		msp.Spec.Node, openapi.PoolStatus(msp.Status.State), int64(msp.Status.Used)))
+	return *pool
+}
+
+// childToRest converts a nexus child to its REST API representation
+func childToRest(child common.TargetChild) openapi.Child {
+	c := openapi.NewChild(openapi.ChildState(child.State), child.Uri)
+	c.RebuildProgress = child.RebuildProgress
+	return *c
+}
+
+// bdToBlockDevice converts a block device to its REST API representation
+func bdToBlockDevice(bd common.BlockDevice) openapi.BlockDevice {
+	device := openapi.NewBlockDevice(bd.Available, bd.ConnectionType, bd.Devlinks, bd.Devmajor, bd.Devminor,
+		bd.Devname, bd.Devpath, bd.Devtype, bd.Model, bd.Size)
+	if bd.Devlinks == nil {
+		device.Devlinks = []string{}
+	}
+	if bd.IsRotational != nil {
+		device.SetIsRotational(*bd.IsRotational)
+	}
+	if bd.Filesystem.Fstype != "" {
+		device.SetFilesystem(openapi.BlockDeviceFilesystem(bd.Filesystem))
+	}
+	if bd.Partition.Name != "" {
+		device.SetPartition(openapi.BlockDevicePartition(bd.Partition))
+	}
+	return *device
+}
+
+// schemaToSnapshot converts a snapshot to its REST API representation
+func schemaToSnapshot(schema common.SnapshotSchema) openapi.VolumeSnapshot {
+	def := schema.Definition
+	transactions := make(map[string][]openapi.ReplicaSnapshot)
+	for txn, replicas := range def.Metadata.Transactions {
+		for _, replica := range replicas {
+			transactions[txn] = append(transactions[txn],
+				*openapi.NewReplicaSnapshot(replica.UUID, replica.SourceID, openapi.SpecStatus(replica.Status)))
+		}
+	}
+	metadata := openapi.NewVolumeSnapshotMetadata(openapi.SpecStatus(def.Metadata.Status), schema.State.AllocatedSize,
+		schema.State.AllocatedSize, schema.State.AllocatedSize, def.Metadata.TxnID, transactions,
+		int32(def.Metadata.NumRestores), int32(def.Metadata.NumSnapshotReplicas))
+	if timestamp, err := time.Parse(time.RFC3339, def.Metadata.Timestamp); err == nil {
+		metadata.SetTimestamp(timestamp)
+	}
+
+	replicaSnapshots := []openapi.ReplicaSnapshotState{}
+	for _, replica := range schema.State.ReplicaSnapshots {
+		state := openapi.NewReplicaSnapshotState()
+		if online := replica.Online; online.UUID != "" {
+			timestamp, _ := time.Parse(time.RFC3339, online.Timestamp)
+			state.SetOnline(*openapi.NewOnlineReplicaSnapshotState(online.UUID, online.SourceID, online.PoolID,
+				online.PoolUUID, timestamp, online.Size, online.AllocatedSize, online.PredecessorAllocSize))
+		}
+		if offline := replica.Offline; offline.UUID != "" {
+			state.SetOffline(*openapi.NewOfflineReplicaSnapshotState(offline.UUID, offline.SourceID, offline.PoolID, offline.PoolUUID))
+		}
+		replicaSnapshots = append(replicaSnapshots, *state)
+	}
+	state := openapi.NewVolumeSnapshotState(schema.State.UUID, schema.State.AllocatedSize, schema.State.SourceVolume,
+		schema.State.ReadyAsSource, replicaSnapshots)
+	if timestamp, err := time.Parse(time.RFC3339, schema.State.Timestamp); err == nil {
+		state.SetTimestamp(timestamp)
+	}
+	return *openapi.NewVolumeSnapshot(*openapi.NewVolumeSnapshotDefinition(*metadata,
+		*openapi.NewVolumeSnapshotSpec(def.Spec.UUID, def.Spec.SourceVolume)), *state)
+}
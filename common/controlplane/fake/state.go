@@ -0,0 +1,599 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+	"github.com/openebs/openebs-e2e/common/generated/openapi"
+)
+
+// Step is a scripted change of the control plane state, Apply is called
+// once AfterPolls reads of the state have been made since the previous step
+type Step struct {
+	AfterPolls int
+	Apply      func(s *State)
+}
+
+// State is the in-memory state of the fake control plane, it is shared by
+// the fake ControlPlaneInterface implementation and the fake REST server.
+// Resources are stored and returned by value so that callers cannot
+// change the state other than through State methods.
+type State struct {
+	mutex       sync.Mutex
+	volumes     map[string]common.MayastorVolume
+	nodes       map[string]common.MayastorNode
+	pools       map[string]common.MayastorPool
+	snapshots   map[string]common.SnapshotSchema
+	devices     map[string][]common.BlockDevice
+	appNodes    map[string]common.AppNode
+	cordons     map[string][]string
+	drains      map[string][]string
+	polls       int
+	script      []Step
+	lastStep    int
+	injectedErr error
+	errCount    int
+	listeners   map[int]func(uuid string)
+	listenerId  int
+}
+
+var (
+	defaultState *State
+	defaultOnce  sync.Once
+)
+
+// NewState returns an empty control plane state
+func NewState() *State {
+	return &State{
+		volumes:   make(map[string]common.MayastorVolume),
+		nodes:     make(map[string]common.MayastorNode),
+		pools:     make(map[string]common.MayastorPool),
+		snapshots: make(map[string]common.SnapshotSchema),
+		devices:   make(map[string][]common.BlockDevice),
+		appNodes:  make(map[string]common.AppNode),
+		cordons:   make(map[string][]string),
+		drains:    make(map[string][]string),
+		listeners: make(map[int]func(uuid string)),
+	}
+}
+
+// DefaultState returns the state used when the fake control plane is selected by configuration
+func DefaultState() *State {
+	defaultOnce.Do(func() {
+		defaultState = NewState()
+	})
+	return defaultState
+}
+
+// Reset removes all resources, scripted steps and injected errors
+func (s *State) Reset() {
+	fresh := NewState()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.volumes = fresh.volumes
+	s.nodes = fresh.nodes
+	s.pools = fresh.pools
+	s.snapshots = fresh.snapshots
+	s.devices = fresh.devices
+	s.appNodes = fresh.appNodes
+	s.cordons = fresh.cordons
+	s.drains = fresh.drains
+	s.polls = 0
+	s.script = nil
+	s.lastStep = 0
+	s.injectedErr = nil
+	s.errCount = 0
+}
+
+// Script queues steps which change the state as it is polled,
+// eg: a volume which becomes healthy after being read 3 times
+func (s *State) Script(steps ...Step) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.script) == 0 {
+		s.lastStep = s.polls
+	}
+	s.script = append(s.script, steps...)
+}
+
+// ScriptDone returns true once all scripted steps have been applied
+func (s *State) ScriptDone() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.script) == 0
+}
+
+// InjectError makes the next count reads of the state fail with err
+func (s *State) InjectError(err error, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.injectedErr = err
+	s.errCount = count
+}
+
+// poll records a read of the state, applying the scripted steps which are due,
+// and returns the injected error if any
+func (s *State) poll() error {
+	var due []Step
+	var err error
+	s.mutex.Lock()
+	s.polls++
+	for len(s.script) != 0 && s.polls-s.lastStep >= s.script[0].AfterPolls {
+		due = append(due, s.script[0])
+		s.script = s.script[1:]
+		s.lastStep = s.polls
+	}
+	if s.errCount > 0 {
+		s.errCount--
+		err = s.injectedErr
+	}
+	s.mutex.Unlock()
+
+	// steps use the State methods, so they are applied without holding the lock
+	for _, step := range due {
+		step.Apply(s)
+	}
+	return err
+}
+
+// Polls returns the number of reads of the state
+func (s *State) Polls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.polls
+}
+
+// subscribe registers a function called with the uuid of a volume after it is
+// added, changed or removed, the returned function removes the registration
+func (s *State) subscribe(fn func(uuid string)) func() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listenerId++
+	id := s.listenerId
+	s.listeners[id] = fn
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// notifyVolume calls the registered functions without holding the lock
+func (s *State) notifyVolume(uuid string) {
+	s.mutex.Lock()
+	listeners := make([]func(uuid string), 0, len(s.listeners))
+	for _, fn := range s.listeners {
+		listeners = append(listeners, fn)
+	}
+	s.mutex.Unlock()
+	for _, fn := range listeners {
+		fn(uuid)
+	}
+}
+
+// AddNode adds or replaces a node
+func (s *State) AddNode(node common.MayastorNode) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nodes[node.Name] = node
+}
+
+// AddPool adds or replaces a pool
+func (s *State) AddPool(pool common.MayastorPool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.pools[pool.Name] = pool
+}
+
+// AddBlockDevice adds a block device to a node
+func (s *State) AddBlockDevice(node string, device common.BlockDevice) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.devices[node] = append(s.devices[node], device)
+}
+
+// AddVolume adds or replaces a volume
+func (s *State) AddVolume(vol common.MayastorVolume) {
+	s.mutex.Lock()
+	s.volumes[vol.Spec.Uuid] = copyVolume(vol)
+	s.mutex.Unlock()
+	s.notifyVolume(vol.Spec.Uuid)
+}
+
+// AddSnapshot adds or replaces a snapshot
+func (s *State) AddSnapshot(snapshot common.SnapshotSchema) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.snapshots[snapshot.Definition.Spec.UUID] = snapshot
+}
+
+// AddAppNode adds or replaces an app node, as registered by the CSI node plugin
+func (s *State) AddAppNode(appNode common.AppNode) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.appNodes[appNode.Id] = appNode
+}
+
+// RemoveVolume removes a volume, returning false if it does not exist
+func (s *State) RemoveVolume(uuid string) bool {
+	s.mutex.Lock()
+	_, ok := s.volumes[uuid]
+	delete(s.volumes, uuid)
+	s.mutex.Unlock()
+	if ok {
+		s.notifyVolume(uuid)
+	}
+	return ok
+}
+
+// RemoveSnapshot removes a snapshot, returning false if it does not exist
+func (s *State) RemoveSnapshot(snapshotId string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.snapshots[snapshotId]
+	delete(s.snapshots, snapshotId)
+	return ok
+}
+
+// RemoveAppNode removes an app node, as deregistered by the CSI node plugin,
+// returning false if it does not exist
+func (s *State) RemoveAppNode(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, ok := s.appNodes[id]
+	delete(s.appNodes, id)
+	return ok
+}
+
+// UpdateVolume changes a volume
+func (s *State) UpdateVolume(uuid string, update func(vol *common.MayastorVolume)) error {
+	s.mutex.Lock()
+	vol, ok := s.volumes[uuid]
+	if !ok {
+		s.mutex.Unlock()
+		return errorOf(cperrors.ErrNotFound, "volume %s not found", uuid)
+	}
+	vol = copyVolume(vol)
+	update(&vol)
+	s.volumes[uuid] = vol
+	s.mutex.Unlock()
+	s.notifyVolume(uuid)
+	return nil
+}
+
+// UpdateNode changes a node
+func (s *State) UpdateNode(name string, update func(node *common.MayastorNode)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	node, ok := s.nodes[name]
+	if !ok {
+		return errorOf(cperrors.ErrNotFound, "node %s not found", name)
+	}
+	update(&node)
+	s.nodes[name] = node
+	return nil
+}
+
+// UpdatePool changes a pool
+func (s *State) UpdatePool(name string, update func(pool *common.MayastorPool)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pool, ok := s.pools[name]
+	if !ok {
+		return errorOf(cperrors.ErrNotFound, "pool %s not found", name)
+	}
+	update(&pool)
+	s.pools[name] = pool
+	return nil
+}
+
+// SetVolumeStatus sets the status of a volume and of its nexus,
+// the statuses are those of the REST API, eg: Online, Degraded
+func (s *State) SetVolumeStatus(uuid string, status string) error {
+	return s.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		vol.State.Status = status
+		if vol.State.Target.Uuid != "" {
+			vol.State.Target.State = status
+		}
+	})
+}
+
+// SetChildState sets the state of the nexus child of a volume with the replica uuid,
+// the rebuild progress is cleared if rebuildProgress is nil
+func (s *State) SetChildState(uuid string, replicaUuid string, state string, rebuildProgress *int32) error {
+	var err error
+	updateErr := s.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		children := vol.State.Target.Children
+		for ix := range children {
+			if children[ix].Uri == ChildUri(vol.State.ReplicaTopology[replicaUuid], replicaUuid) {
+				children[ix].State = state
+				children[ix].RebuildProgress = rebuildProgress
+				return
+			}
+		}
+		err = errorOf(cperrors.ErrNotFound, "volume %s has no child for replica %s", uuid, replicaUuid)
+	})
+	if updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// SetReplicaState sets the state of a replica of a volume
+func (s *State) SetReplicaState(uuid string, replicaUuid string, state string) error {
+	var err error
+	updateErr := s.UpdateVolume(uuid, func(vol *common.MayastorVolume) {
+		replica, ok := vol.State.ReplicaTopology[replicaUuid]
+		if !ok {
+			err = errorOf(cperrors.ErrNotFound, "volume %s has no replica %s", uuid, replicaUuid)
+			return
+		}
+		replica.State = state
+		vol.State.ReplicaTopology[replicaUuid] = replica
+	})
+	if updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// SetNodeStatus sets the status of a node, eg: Online, Offline
+func (s *State) SetNodeStatus(name string, status string) error {
+	return s.UpdateNode(name, func(node *common.MayastorNode) {
+		node.State.Status = status
+	})
+}
+
+// SetPoolState sets the state of a pool, eg: Online, Degraded
+func (s *State) SetPoolState(name string, state string) error {
+	return s.UpdatePool(name, func(pool *common.MayastorPool) {
+		pool.Status.State = state
+	})
+}
+
+func (s *State) getVolume(uuid string) (common.MayastorVolume, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	vol, ok := s.volumes[uuid]
+	return copyVolume(vol), ok
+}
+
+// listVolumes returns the volumes ordered by uuid
+func (s *State) listVolumes() []common.MayastorVolume {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	vols := make([]common.MayastorVolume, 0, len(s.volumes))
+	for _, vol := range s.volumes {
+		vols = append(vols, copyVolume(vol))
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].Spec.Uuid < vols[j].Spec.Uuid })
+	return vols
+}
+
+// listBlockDevices returns the available block devices of the node, or all if all is true
+func (s *State) listBlockDevices(node string, all bool) []common.BlockDevice {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var devices []common.BlockDevice
+	for _, device := range s.devices[node] {
+		if all || device.Available {
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
+
+func (s *State) getNode(name string) (common.MayastorNode, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	node, ok := s.nodes[name]
+	return node, ok
+}
+
+// listNodes returns the nodes ordered by name
+func (s *State) listNodes() []common.MayastorNode {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	nodes := make([]common.MayastorNode, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes
+}
+
+func (s *State) getAppNode(id string) (common.AppNode, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	appNode, ok := s.appNodes[id]
+	return appNode, ok
+}
+
+// listAppNodes returns the app nodes ordered by id
+func (s *State) listAppNodes() []common.AppNode {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	appNodes := make([]common.AppNode, 0, len(s.appNodes))
+	for _, appNode := range s.appNodes {
+		appNodes = append(appNodes, appNode)
+	}
+	sort.Slice(appNodes, func(i, j int) bool { return appNodes[i].Id < appNodes[j].Id })
+	return appNodes
+}
+
+func (s *State) getPool(name string) (common.MayastorPool, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pool, ok := s.pools[name]
+	return pool, ok
+}
+
+// listPools returns the pools ordered by name
+func (s *State) listPools() []common.MayastorPool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pools := make([]common.MayastorPool, 0, len(s.pools))
+	for _, pool := range s.pools {
+		pools = append(pools, pool)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools
+}
+
+func (s *State) getSnapshot(snapshotId string) (common.SnapshotSchema, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	snapshot, ok := s.snapshots[snapshotId]
+	return snapshot, ok
+}
+
+// listSnapshots returns the snapshots ordered by uuid
+func (s *State) listSnapshots() []common.SnapshotSchema {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	snapshots := make([]common.SnapshotSchema, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Definition.Spec.UUID < snapshots[j].Definition.Spec.UUID
+	})
+	return snapshots
+}
+
+// copyVolume returns a copy of the volume which does not share
+// the nexus children and the replica topology
+func copyVolume(vol common.MayastorVolume) common.MayastorVolume {
+	vol.State.Target.Children = append([]common.TargetChild(nil), vol.State.Target.Children...)
+	if vol.State.ReplicaTopology != nil {
+		topology := make(common.ReplicaTopology, len(vol.State.ReplicaTopology))
+		for k, v := range vol.State.ReplicaTopology {
+			topology[k] = v
+		}
+		vol.State.ReplicaTopology = topology
+	}
+	return vol
+}
+
+// ChildUri returns the uri of the nexus child of a replica used by the fake control plane
+func ChildUri(replica common.Replica, replicaUuid string) string {
+	return fmt.Sprintf("bdev:///%s?uuid=%s&pool=%s", replicaUuid, replicaUuid, replica.Pool)
+}
+
+// OnlineNode returns an online node
+func OnlineNode(name string) common.MayastorNode {
+	return common.MayastorNode{
+		Name: name,
+		Spec: common.MayastorNodeSpec{
+			GrpcEndpoint: name + ":10124",
+			ID:           name,
+		},
+		State: common.MayastorNodeState{
+			GrpcEndpoint: name + ":10124",
+			ID:           name,
+			Status:       string(openapi.NODESTATUS_ONLINE),
+		},
+	}
+}
+
+// OnlinePool returns an online pool on a node with no space used
+func OnlinePool(name string, node string, disk string, capacity uint64) common.MayastorPool {
+	spec := common.MayastorPoolSpec{
+		Disks: []string{disk},
+		Node:  node,
+	}
+	return common.MayastorPool{
+		Name: name,
+		Spec: spec,
+		Status: common.MayastorPoolStatus{
+			Avail:    capacity,
+			Capacity: capacity,
+			Disks:    []string{disk},
+			Spec:     spec,
+			State:    string(openapi.POOLSTATUS_ONLINE),
+		},
+	}
+}
+
+// VolumeReplica places a replica of a volume on a pool
+type VolumeReplica struct {
+	Uuid string
+	Node string
+	Pool string
+}
+
+// BlankDisk returns an available disk without partitions or filesystem of size bytes
+func BlankDisk(devname string, size int64) common.BlockDevice {
+	return common.BlockDevice{
+		Available:      true,
+		ConnectionType: "scsi",
+		Devlinks:       []string{"/dev/disk/by-id/scsi-" + strings.TrimPrefix(devname, "/dev/")},
+		Devname:        devname,
+		Devpath:        "/devices/virtual/block/" + strings.TrimPrefix(devname, "/dev/"),
+		Devtype:        "disk",
+		Model:          "fake disk",
+		Size:           size / 512,
+	}
+}
+
+// HealthyVolume returns a healthy volume with the replicas, published on
+// targetNode with a nexus having a child per replica, or unpublished
+// if targetNode is empty
+func HealthyVolume(uuid string, size int64, targetNode string, replicas ...VolumeReplica) common.MayastorVolume {
+	topology := make(common.ReplicaTopology, len(replicas))
+	var children []common.TargetChild
+	for _, r := range replicas {
+		replica := common.Replica{
+			Node:        r.Node,
+			Pool:        r.Pool,
+			State:       string(openapi.REPLICASTATE_ONLINE),
+			ChildStatus: string(openapi.CHILDSTATE_ONLINE),
+			Usage: common.ReplicaUsage{
+				Capacity: size,
+			},
+		}
+		topology[r.Uuid] = replica
+		children = append(children, common.TargetChild{
+			State: string(openapi.CHILDSTATE_ONLINE),
+			Uri:   ChildUri(replica, r.Uuid),
+		})
+	}
+	vol := common.MayastorVolume{
+		Spec: common.MsvSpec{
+			Num_replicas: len(replicas),
+			Size:         size,
+			Status:       string(openapi.CREATED),
+			Uuid:         uuid,
+			Policy:       common.Policy{Self_heal: true},
+		},
+		State: common.MsvState{
+			Size:            size,
+			Status:          string(openapi.VOLUMESTATUS_ONLINE),
+			Uuid:            uuid,
+			ReplicaTopology: topology,
+			Usage: common.Usage{
+				Capacity: size,
+			},
+		},
+	}
+	if targetNode != "" {
+		vol.Spec.Target = common.SpecTarget{
+			Protocol: string(openapi.PROTOCOL_NVMF),
+			Node:     targetNode,
+		}
+		vol.State.Target = common.StateTarget{
+			Children:  children,
+			DeviceUri: nexusDeviceUri(targetNode, uuid),
+			Node:      targetNode,
+			Protocol:  string(openapi.PROTOCOL_NVMF),
+			Size:      size,
+			State:     string(openapi.NEXUSSTATE_ONLINE),
+			Uuid:      uuid,
+		}
+	}
+	return vol
+}
@@ -0,0 +1,21 @@
+package fake
+
+import (
+	"github.com/openebs/openebs-e2e/common/controlplane/watch"
+)
+
+// WatchMsv watches the volume, the watch is notified of every change of the volume in the state
+func (cp CPFake) WatchMsv(uuid string) (*watch.MsvWatch, error) {
+	w := watch.NewMsvWatch(uuid, cp, watch.DefaultResync)
+	unsubscribe := cp.state.subscribe(func(changed string) {
+		if changed == uuid {
+			w.Notify()
+		}
+	})
+	w.OnStop(func() error {
+		unsubscribe()
+		return nil
+	})
+	w.Start()
+	return w, nil
+}
@@ -1,10 +1,13 @@
 package v1_rest_api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
 	cpV1 "github.com/openebs/openebs-e2e/common/controlplane/v1"
 	"github.com/openebs/openebs-e2e/common/generated/openapi"
 
@@ -124,9 +127,29 @@ func MakeCP() (CPv1RestApi, error) {
 	return cp, err
 }
 
+// MakeCPForHost make control plane object which uses the REST API served at host,
+// eg: the fake REST server of the fake control plane
+func MakeCPForHost(host string) (CPv1RestApi, error) {
+	cp := CPv1RestApi{
+		oa: MakeWrapperForHost(host),
+	}
+	logf.Log.Info("Control Plane v1 - Rest API", "host", host)
+	return cp, nil
+}
+
+// WithContext returns the control plane with its REST API calls bounded by ctx,
+// eg: the SpecContext of a Ginkgo spec
+func (cp CPv1RestApi) WithContext(ctx context.Context) CPv1RestApi {
+	cp.oa.ctx = ctx
+	return cp
+}
+
 var re = regexp.MustCompile(`(statusCode=408)`)
 
 func (cp CPv1RestApi) IsTimeoutError(err error) bool {
+	if errors.Is(err, cperrors.ErrTimeout) {
+		return true
+	}
 	str := fmt.Sprintf("%v", err)
 	frags := re.FindSubmatch([]byte(str))
 	return len(frags) == 2 && string(frags[1]) == "statusCode=408"
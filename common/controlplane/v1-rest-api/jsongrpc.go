@@ -0,0 +1,21 @@
+package v1_rest_api
+
+import (
+	"fmt"
+)
+
+// JsonGrpc calls an SPDK json-rpc method on the io-engine of the node through the
+// JsonGrpc API of the control plane, the io-engine passes the call to SPDK as a
+// JsonRpc.JsonRpcCall, so method is an SPDK method, eg: bdev_get_bdevs, not an
+// io-engine gRPC method. params are the parameters of the SPDK method and the
+// returned map is the result of the call.
+func (cp CPv1RestApi) JsonGrpc(node string, method string, params map[string]interface{}) (map[string]interface{}, error) {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	response, err, _ := cp.oa.putNodeJsonGrpc(node, method, params)
+	if err != nil {
+		return nil, fmt.Errorf("JsonGrpc %s on node %s: %w", method, node, err)
+	}
+	return response, nil
+}
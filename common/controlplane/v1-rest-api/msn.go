@@ -64,3 +64,56 @@ func (cp CPv1RestApi) GetMsNodeStatus(nodeName string) (string, error) {
 func (cp CPv1RestApi) UpdateNodeLabel(nodeName string, labelKey, labelValue string) error {
 	panic(fmt.Errorf("not implemented REST api"))
 }
+
+// ListNodeBlockDevices returns the block devices of the node which are available
+// for use by pools, or all block devices of the node if all is true
+func (cp CPv1RestApi) ListNodeBlockDevices(nodeName string, all bool) ([]common.BlockDevice, error) {
+	devices, err, _ := cp.oa.getNodeBlockDevices(nodeName, all)
+	if err != nil {
+		return nil, fmt.Errorf("ListNodeBlockDevices: node %s, %w", nodeName, err)
+	}
+	var bds []common.BlockDevice
+	for _, device := range devices {
+		bds = append(bds, blockDeviceToBd(device))
+	}
+	return bds, nil
+}
+
+func oaAppNodeToAppNode(oaAppNode openapi.AppNode) common.AppNode {
+	appNode := common.AppNode{
+		Id: oaAppNode.GetId(),
+		Spec: common.AppNodeSpec{
+			Id:       oaAppNode.Spec.Id,
+			Endpoint: oaAppNode.Spec.Endpoint,
+			Labels:   oaAppNode.Spec.GetLabels(),
+		},
+	}
+	if oaAppNode.State != nil {
+		appNode.State = common.AppNodeState{
+			Id:       oaAppNode.State.Id,
+			Endpoint: oaAppNode.State.Endpoint,
+			Status:   oaAppNode.State.Status,
+		}
+	}
+	return appNode
+}
+
+// GetAppNode returns the app node registered with the control plane
+func (cp CPv1RestApi) GetAppNode(id string) (*common.AppNode, error) {
+	oaAppNode, err, _ := cp.oa.getAppNode(id)
+	if err != nil {
+		return nil, fmt.Errorf("GetAppNode: %w", err)
+	}
+	appNode := oaAppNodeToAppNode(oaAppNode)
+	return &appNode, nil
+}
+
+// ListAppNodes returns the app nodes registered with the control plane,
+// the app nodes are fetched a page at a time
+func (cp CPv1RestApi) ListAppNodes() ([]common.AppNode, error) {
+	appNodes, err := cp.AppNodes().Collect()
+	if err != nil {
+		return nil, fmt.Errorf("ListAppNodes: %w", err)
+	}
+	return appNodes, nil
+}
@@ -2,10 +2,12 @@ package v1_rest_api
 
 // Utility functions for Mayastor CRDs
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
 	openapiClient "github.com/openebs/openebs-e2e/common/generated/openapi"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -17,7 +19,7 @@ func (cp CPv1RestApi) GetMSV(uuid string) (*common.MayastorVolume, error) {
 	vol, err, _ := cp.oa.getVolume(uuid)
 
 	if err != nil {
-		return nil, fmt.Errorf("GetMSV: %v", err)
+		return nil, fmt.Errorf("GetMSV: %w", err)
 	}
 
 	// still being created
@@ -79,18 +81,11 @@ func (cp CPv1RestApi) DeleteMsv(uuid string) error {
 }
 
 func (cp CPv1RestApi) ListMsvs() ([]common.MayastorVolume, error) {
-	vols, err, _ := cp.oa.getVolumes()
+	msvs, err := cp.Volumes().Collect()
 	if err != nil {
-		return nil, fmt.Errorf("ListMsvs: %v", err)
+		return nil, fmt.Errorf("ListMsvs: %w", err)
 	}
-
-	var msvs []common.MayastorVolume
-	if err == nil {
-		for _, vol := range vols.Entries {
-			msvs = append(msvs, cp.oa.volToMsv(vol))
-		}
-	}
-	return msvs, err
+	return msvs, nil
 }
 
 func (cp CPv1RestApi) ListRestoredMsvs() ([]common.MayastorVolume, error) {
@@ -202,31 +197,29 @@ func (cp CPv1RestApi) IsMsvPublished(uuid string) bool {
 }
 
 func (cp CPv1RestApi) IsMsvDeleted(uuid string) bool {
-	_, err, responseStatusCode := cp.oa.getVolume(uuid)
-	return err != nil && responseStatusCode == 404
+	_, err, _ := cp.oa.getVolume(uuid)
+	return errors.Is(err, cperrors.ErrNotFound)
 }
 
 func (cp CPv1RestApi) CheckForMsvs() (bool, error) {
-	vols, err, _ := cp.oa.getVolumes()
-	return err == nil && len(vols.Entries) != 0, err
+	it := cp.oa.rawVolumes()
+	found := it.Next()
+	return found, it.Err()
 }
 
 func (cp CPv1RestApi) CheckAllMsvsAreHealthy() error {
 	allHealthy := true
-	vols, err, _ := cp.oa.getVolumes()
-
-	if err == nil {
-		for _, vol := range vols.Entries {
-			if vol.State.GetStatus() != openapiClient.VOLUMESTATUS_ONLINE {
-				allHealthy = false
-				logf.Log.Info("CheckAllMsvsAreHealthy", "vol", vol)
-			}
-		}
-		if !allHealthy {
-			err = fmt.Errorf("all MSVs were not healthy")
+	err := cp.oa.rawVolumes().ForEach(func(vol openapiClient.Volume) error {
+		if vol.State.GetStatus() != openapiClient.VOLUMESTATUS_ONLINE {
+			allHealthy = false
+			logf.Log.Info("CheckAllMsvsAreHealthy", "vol", vol)
 		}
-	}
+		return nil
+	})
 
+	if err == nil && !allHealthy {
+		err = fmt.Errorf("all MSVs were not healthy")
+	}
 	return err
 }
 
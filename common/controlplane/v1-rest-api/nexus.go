@@ -0,0 +1,92 @@
+package v1_rest_api
+
+import (
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+)
+
+// ListNexuses returns the nexuses on the node, or on all nodes if node is ""
+func (cp CPv1RestApi) ListNexuses(node string) ([]common.MayastorNexus, error) {
+	nexuses, err, _ := cp.oa.getNexuses(node)
+	if err != nil {
+		return nil, fmt.Errorf("ListNexuses: node %s, %w", node, err)
+	}
+	var msns []common.MayastorNexus
+	for _, nexus := range nexuses {
+		msns = append(msns, nexusToMayastorNexus(nexus))
+	}
+	return msns, nil
+}
+
+func (cp CPv1RestApi) GetNexus(uuid string) (*common.MayastorNexus, error) {
+	nexus, err, _ := cp.oa.getNexus(uuid)
+	if err != nil {
+		return nil, fmt.Errorf("GetNexus: %w", err)
+	}
+	msn := nexusToMayastorNexus(nexus)
+	return &msn, nil
+}
+
+// nexusNode returns the node of the nexus, the REST API changes a nexus through its node
+func (cp CPv1RestApi) nexusNode(uuid string) (string, error) {
+	nexus, err, _ := cp.oa.getNexus(uuid)
+	if err != nil {
+		return "", err
+	}
+	return nexus.Node, nil
+}
+
+// AddNexusChild adds the child with the uri, eg: a replica uri, to the nexus
+func (cp CPv1RestApi) AddNexusChild(uuid string, childUri string) error {
+	node, err := cp.nexusNode(uuid)
+	if err != nil {
+		return fmt.Errorf("AddNexusChild: %w", err)
+	}
+	if _, err, _ = cp.oa.putNexusChild(node, uuid, childUri); err != nil {
+		return fmt.Errorf("AddNexusChild: nexus %s child %s, %w", uuid, childUri, err)
+	}
+	return nil
+}
+
+func (cp CPv1RestApi) RemoveNexusChild(uuid string, childUri string) error {
+	node, err := cp.nexusNode(uuid)
+	if err != nil {
+		return fmt.Errorf("RemoveNexusChild: %w", err)
+	}
+	if err, _ = cp.oa.delNexusChild(node, uuid, childUri); err != nil {
+		return fmt.Errorf("RemoveNexusChild: nexus %s child %s, %w", uuid, childUri, err)
+	}
+	return nil
+}
+
+// FaultNexusChild is not supported, the REST API has no operation to fault a child, use RemoveNexusChild
+func (cp CPv1RestApi) FaultNexusChild(uuid string, childUri string) error {
+	return cperrors.Wrap(fmt.Errorf("FaultNexusChild: faulting a nexus child is not supported by the REST API, nexus %s child %s",
+		uuid, childUri), cperrors.ErrUnsupported)
+}
+
+// ShareNexus shares the nexus over nvmf, returning the uri of the share
+func (cp CPv1RestApi) ShareNexus(uuid string) (string, error) {
+	node, err := cp.nexusNode(uuid)
+	if err != nil {
+		return "", fmt.Errorf("ShareNexus: %w", err)
+	}
+	uri, err, _ := cp.oa.putNexusShare(node, uuid)
+	if err != nil {
+		return "", fmt.Errorf("ShareNexus: nexus %s, %w", uuid, err)
+	}
+	return uri, nil
+}
+
+func (cp CPv1RestApi) UnshareNexus(uuid string) error {
+	node, err := cp.nexusNode(uuid)
+	if err != nil {
+		return fmt.Errorf("UnshareNexus: %w", err)
+	}
+	if err, _ = cp.oa.delNexusShare(node, uuid); err != nil {
+		return fmt.Errorf("UnshareNexus: nexus %s, %w", uuid, err)
+	}
+	return nil
+}
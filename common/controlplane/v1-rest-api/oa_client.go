@@ -4,12 +4,17 @@ import (
 	"context"
 
 	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
 	"github.com/openebs/openebs-e2e/common/e2e_config"
 	"github.com/openebs/openebs-e2e/common/k8s_portforward"
 
-	//	"encoding/json"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 
 	openapiClient "github.com/openebs/openebs-e2e/common/generated/openapi"
 )
@@ -18,6 +23,21 @@ type OAClientWrapper struct {
 	nodes   []string
 	clients []*openapiClient.APIClient
 	clindex uint
+	// ctx bounds the REST API calls, nil for no bound other than the call timeout
+	ctx context.Context
+}
+
+// defaultCallTimeout is the REST API call timeout if the configuration does not set a valid one
+const defaultCallTimeout = 120 * time.Second
+
+// callContext returns the context of a REST API call, bounded by the context
+// of the wrapper and the control plane call timeout of the configuration
+func (oacw OAClientWrapper) callContext() (context.Context, context.CancelFunc) {
+	ctx := oacw.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, e2e_config.ParseTimeout(e2e_config.GetConfig().ControlPlaneCallTimeout, defaultCallTimeout))
 }
 
 func MakeWrapper(nodeIPs []string) OAClientWrapper {
@@ -42,6 +62,42 @@ func MakeWrapper(nodeIPs []string) OAClientWrapper {
 	return oacw
 }
 
+// MakeWrapperForHost makes a wrapper for the REST API served at host, eg: a fake REST server
+func MakeWrapperForHost(host string) OAClientWrapper {
+	cfg := openapiClient.NewConfiguration()
+	cfg.Host = host
+	cfg.Scheme = `http`
+	return OAClientWrapper{
+		nodes:   []string{host},
+		clients: []*openapiClient.APIClient{openapiClient.NewAPIClient(cfg)},
+	}
+}
+
+// restError classifies an error of the REST API, see cperrors.FromRest
+func restError(err error, statusCode int) error {
+	if err == nil {
+		return nil
+	}
+	var kind string
+	var oaErr *openapiClient.GenericOpenAPIError
+	if errors.As(err, &oaErr) {
+		if restErr, ok := oaErr.Model().(openapiClient.RestJsonError); ok {
+			kind = restErr.Kind
+		}
+	}
+	if kind == "" && errors.Is(err, context.DeadlineExceeded) {
+		return cperrors.Wrap(err, cperrors.ErrTimeout)
+	}
+	return cperrors.FromRest(err, kind, statusCode)
+}
+
+func responseStatusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
 func (oacw *OAClientWrapper) client() *openapiClient.APIClient {
 	oacw.clindex = (1 + oacw.clindex) % uint(len(oacw.clients))
 	return oacw.clients[oacw.clindex]
@@ -49,49 +105,46 @@ func (oacw *OAClientWrapper) client() *openapiClient.APIClient {
 
 func (oacw OAClientWrapper) getVolume(uuid string) (openapiClient.Volume, error, int) {
 	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
 
-	req := oacw.client().VolumesAPI.GetVolume(context.TODO(), uuid)
+	req := oacw.client().VolumesAPI.GetVolume(ctx, uuid)
 	volume, resp, err := req.Execute()
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
-	return *volume, err, statusCode
-}
-
-func (oacw OAClientWrapper) getVolumes() (openapiClient.Volumes, error, int) {
-	var volumes *openapiClient.Volumes
-	var statusCode int
-	var resp *http.Response
-	var err error
-
-	// FIXME: Handle pagination
-	req := oacw.client().VolumesAPI.GetVolumes(context.TODO()).StartingToken(0).MaxEntries(1000)
-	volumes, resp, err = req.Execute()
-	if resp != nil {
-		statusCode = resp.StatusCode
+	err = restError(err, statusCode)
+	if volume == nil {
+		return openapiClient.Volume{}, err, statusCode
 	}
-	return *volumes, err, statusCode
+	return *volume, err, statusCode
 }
 
 func (oacw OAClientWrapper) deleteVolume(uuid string) (error, int) {
 	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
 
-	req := oacw.client().VolumesAPI.DelVolume(context.TODO(), uuid)
+	req := oacw.client().VolumesAPI.DelVolume(ctx, uuid)
 	resp, err := req.Execute()
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
+	err = restError(err, statusCode)
 	return err, statusCode
 }
 
 func (oacw OAClientWrapper) putReplicaCount(uuid string, replicaCount int) (error, int) {
 	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
 
-	req := oacw.client().VolumesAPI.PutVolumeReplicaCount(context.TODO(), uuid, int32(replicaCount))
+	req := oacw.client().VolumesAPI.PutVolumeReplicaCount(ctx, uuid, int32(replicaCount))
 	_, resp, err := req.Execute()
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
+	err = restError(err, statusCode)
 	return err, statusCode
 }
 
@@ -115,20 +168,27 @@ func (oacw OAClientWrapper) volToMsv(vol openapiClient.Volume) common.MayastorVo
 	}
 
 	for k, topology := range volState.ReplicaTopology {
+		replicaUsage := topology.GetUsage()
 		replicaTopology[k] = common.Replica{
 			Node:        topology.GetNode(),
 			State:       string(topology.GetState()),
 			Pool:        topology.GetPool(),
 			ChildStatus: string(topology.GetChildStatus()),
 			Usage: common.ReplicaUsage{
-				Capacity:              topology.Usage.Capacity,
-				Allocated:             topology.Usage.Allocated,
-				AllocatedSnapshots:    topology.Usage.AllocatedSnapshots,
-				AllocatedAllSnapshots: topology.Usage.AllocatedAllSnapshots,
+				Capacity:              replicaUsage.Capacity,
+				Allocated:             replicaUsage.Allocated,
+				AllocatedSnapshots:    replicaUsage.AllocatedSnapshots,
+				AllocatedAllSnapshots: replicaUsage.AllocatedAllSnapshots,
 			},
 		}
 	}
 
+	var snapshotSource common.Snapshot
+	if contentSource, ok := volSpec.GetContentSourceOk(); ok && contentSource.Snapshot != nil {
+		snapshotSource = common.Snapshot(*contentSource.Snapshot)
+	}
+	volUsage := volState.GetUsage()
+
 	return common.MayastorVolume{
 		Spec: common.MsvSpec{
 			Num_replicas: int(volSpec.NumReplicas),
@@ -144,11 +204,11 @@ func (oacw OAClientWrapper) volToMsv(vol openapiClient.Volume) common.MayastorVo
 				Self_heal: volSpec.GetPolicy().SelfHeal,
 			},
 			NumSnapshots: volSpec.NumSnapshots,
-			AsThin:       *volSpec.AsThin,
+			AsThin:       volSpec.GetAsThin(),
 			ContentSource: common.ContentSource{
-				Snapshot: common.Snapshot(*volSpec.GetContentSource().Snapshot),
+				Snapshot: snapshotSource,
 			},
-			MaxSnapshots: *volSpec.MaxSnapshots,
+			MaxSnapshots: volSpec.GetMaxSnapshots(),
 		},
 		State: common.MsvState{
 			Target: common.StateTarget{
@@ -166,64 +226,330 @@ func (oacw OAClientWrapper) volToMsv(vol openapiClient.Volume) common.MayastorVo
 			Uuid:            volState.GetUuid(),
 			ReplicaTopology: replicaTopology,
 			Usage: common.Usage{
-				Capacity:                volState.Usage.Capacity,
-				Allocated:               volState.Usage.Allocated,
-				AllocatedReplica:        volState.Usage.AllocatedReplica,
-				AllocatedSnapshots:      volState.Usage.AllocatedSnapshots,
-				AllocatedAllSnapshots:   volState.Usage.AllocatedAllSnapshots,
-				TotalAllocated:          volState.Usage.TotalAllocated,
-				TotalAllocatedSnapshots: volState.Usage.TotalAllocatedSnapshots,
-				TotalAllocatedReplicas:  volState.Usage.TotalAllocatedReplicas.(int64),
+				Capacity:                volUsage.Capacity,
+				Allocated:               volUsage.Allocated,
+				AllocatedReplica:        volUsage.AllocatedReplica,
+				AllocatedSnapshots:      volUsage.AllocatedSnapshots,
+				AllocatedAllSnapshots:   volUsage.AllocatedAllSnapshots,
+				TotalAllocated:          volUsage.TotalAllocated,
+				TotalAllocatedSnapshots: volUsage.TotalAllocatedSnapshots,
+				TotalAllocatedReplicas:  anyToInt64(volUsage.TotalAllocatedReplicas),
 			},
 		},
 	}
 }
 
 func (oacw OAClientWrapper) getNode(nodeName string) (openapiClient.Node, error) {
-	req := oacw.client().NodesAPI.GetNode(context.TODO(), nodeName)
-	node, _, err := req.Execute()
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().NodesAPI.GetNode(ctx, nodeName)
+	node, resp, err := req.Execute()
+	err = restError(err, responseStatusCode(resp))
+	if node == nil {
+		return openapiClient.Node{}, err
+	}
 	return *node, err
 }
 
+func (oacw OAClientWrapper) getAppNode(id string) (openapiClient.AppNode, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().AppNodesAPI.GetAppNode(ctx, id)
+	appNode, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	if appNode == nil {
+		return openapiClient.AppNode{}, err, statusCode
+	}
+	return *appNode, err, statusCode
+}
+
 func (oacw OAClientWrapper) getNodes() ([]openapiClient.Node, error) {
-	req := oacw.client().NodesAPI.GetNodes(context.TODO())
-	nodes, _, err := req.Execute()
-	return nodes, err
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().NodesAPI.GetNodes(ctx)
+	nodes, resp, err := req.Execute()
+	return nodes, restError(err, responseStatusCode(resp))
 }
 
 func (oacw OAClientWrapper) getPool(poolName string) (openapiClient.Pool, error, int) {
 	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
 
-	req := oacw.client().PoolsAPI.GetPool(context.TODO(), poolName)
+	req := oacw.client().PoolsAPI.GetPool(ctx, poolName)
 	pool, resp, err := req.Execute()
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
+	err = restError(err, statusCode)
+	if pool == nil {
+		return openapiClient.Pool{}, err, statusCode
+	}
 	return *pool, err, statusCode
 }
 
 func (oacw OAClientWrapper) getPools() ([]openapiClient.Pool, error, int) {
 	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
 
-	req := oacw.client().PoolsAPI.GetPools(context.TODO())
+	req := oacw.client().PoolsAPI.GetPools(ctx)
 	pools, resp, err := req.Execute()
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
+	err = restError(err, statusCode)
 	return pools, err, statusCode
 }
 
 func (oacw OAClientWrapper) setVolumeMaxSnapshotCount(uuid string, maxSnapshotCount int32) (error, int) {
 	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
 
-	req := oacw.client().VolumesAPI.PutVolumeProperty(context.TODO(), uuid)
+	req := oacw.client().VolumesAPI.PutVolumeProperty(ctx, uuid)
 	maxCount := maxSnapshotCount
-	req.SetVolumePropertyBody(openapiClient.SetVolumePropertyBody{
+	req = req.SetVolumePropertyBody(openapiClient.SetVolumePropertyBody{
 		MaxSnapshots: &maxCount,
 	})
 	_, resp, err := req.Execute()
 	if resp != nil {
 		statusCode = resp.StatusCode
 	}
+	err = restError(err, statusCode)
+	return err, statusCode
+}
+
+// anyToInt64 converts a number of an untyped schema property to int64,
+// JSON numbers are decoded as float64
+func anyToInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	case int:
+		return int64(n)
+	}
+	return 0
+}
+
+func (oacw OAClientWrapper) putWatchVolume(uuid string, callback string) (error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().WatchesAPI.PutWatchVolume(ctx, uuid).Callback(callback)
+	resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return err, statusCode
+}
+
+func (oacw OAClientWrapper) delWatchVolume(uuid string, callback string) (error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().WatchesAPI.DelWatchVolume(ctx, uuid).Callback(callback)
+	resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return err, statusCode
+}
+
+// restNode returns host:port of a node serving the REST API, used to find the route from the test host
+func (oacw OAClientWrapper) restNode() string {
+	if _, _, err := net.SplitHostPort(oacw.nodes[0]); err == nil {
+		return oacw.nodes[0]
+	}
+	return net.JoinHostPort(oacw.nodes[0], "80")
+}
+
+func (oacw OAClientWrapper) getNexuses(node string) ([]openapiClient.Nexus, error, int) {
+	var nexuses []openapiClient.Nexus
+	var resp *http.Response
+	var err error
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	if node == "" {
+		nexuses, resp, err = oacw.client().NexusesAPI.GetNexuses(ctx).Execute()
+	} else {
+		nexuses, resp, err = oacw.client().NexusesAPI.GetNodeNexuses(ctx, node).Execute()
+	}
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return nexuses, err, statusCode
+}
+
+func (oacw OAClientWrapper) getNexus(uuid string) (openapiClient.Nexus, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().NexusesAPI.GetNexus(ctx, uuid)
+	nexus, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	if nexus == nil {
+		return openapiClient.Nexus{}, err, statusCode
+	}
+	return *nexus, err, statusCode
+}
+
+func (oacw OAClientWrapper) putNexusChild(node string, uuid string, childUri string) (openapiClient.Child, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().ChildrenAPI.PutNodeNexusChild(ctx, node, uuid, childUri)
+	child, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	if child == nil {
+		return openapiClient.Child{}, err, statusCode
+	}
+	return *child, err, statusCode
+}
+
+func (oacw OAClientWrapper) delNexusChild(node string, uuid string, childUri string) (error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().ChildrenAPI.DelNodeNexusChild(ctx, node, uuid, childUri)
+	resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return err, statusCode
+}
+
+func (oacw OAClientWrapper) putNexusShare(node string, uuid string) (string, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().NexusesAPI.PutNodeNexusShare(ctx, node, uuid, openapiClient.NEXUSSHAREPROTOCOL_NVMF)
+	body, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	// the generated client returns the undecoded body of string responses
+	var uri string
+	if jsonErr := json.Unmarshal([]byte(body), &uri); jsonErr != nil {
+		uri = strings.TrimSpace(body)
+	}
+	return uri, err, statusCode
+}
+
+func (oacw OAClientWrapper) delNexusShare(node string, uuid string) (error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().NexusesAPI.DelNodeNexusShare(ctx, node, uuid)
+	resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
 	return err, statusCode
 }
+
+func nexusToMayastorNexus(nexus openapiClient.Nexus) common.MayastorNexus {
+	children := []common.TargetChild{}
+	for _, child := range nexus.Children {
+		children = append(children, childToTargetChild(child))
+	}
+	return common.MayastorNexus{
+		Children:  children,
+		DeviceUri: nexus.DeviceUri,
+		Node:      nexus.Node,
+		Rebuilds:  nexus.Rebuilds,
+		Protocol:  string(nexus.Protocol),
+		Size:      nexus.Size,
+		State:     string(nexus.State),
+		Uuid:      nexus.Uuid,
+	}
+}
+
+func childToTargetChild(child openapiClient.Child) common.TargetChild {
+	return common.TargetChild{
+		Uri:             child.Uri,
+		State:           string(child.State),
+		RebuildProgress: child.RebuildProgress,
+	}
+}
+
+func (oacw OAClientWrapper) getNodeBlockDevices(node string, all bool) ([]openapiClient.BlockDevice, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().BlockDevicesAPI.GetNodeBlockDevices(ctx, node).All(all)
+	devices, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return devices, err, statusCode
+}
+
+func blockDeviceToBd(device openapiClient.BlockDevice) common.BlockDevice {
+	bd := common.BlockDevice{
+		Available:      device.Available,
+		ConnectionType: device.ConnectionType,
+		Devlinks:       device.Devlinks,
+		Devmajor:       device.Devmajor,
+		Devminor:       device.Devminor,
+		Devname:        device.Devname,
+		Devpath:        device.Devpath,
+		Devtype:        device.Devtype,
+		IsRotational:   device.IsRotational,
+		Model:          device.Model,
+		Size:           device.Size,
+	}
+	if fs, ok := device.GetFilesystemOk(); ok {
+		bd.Filesystem = common.BlockDeviceFilesystem(*fs)
+	}
+	if partition, ok := device.GetPartitionOk(); ok {
+		bd.Partition = common.BlockDevicePartition(*partition)
+	}
+	return bd
+}
+
+func (oacw OAClientWrapper) putNodeJsonGrpc(node string, method string, params map[string]interface{}) (map[string]interface{}, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().JsonGrpcAPI.PutNodeJsongrpc(ctx, node, method).Body(params)
+	response, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return response, err, statusCode
+}
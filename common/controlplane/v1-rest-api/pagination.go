@@ -0,0 +1,272 @@
+package v1_rest_api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common"
+
+	openapiClient "github.com/openebs/openebs-e2e/common/generated/openapi"
+)
+
+// restPageSize is the number of entries requested per page of a paginated list
+const restPageSize int32 = 500
+
+// fetchPage returns the entries of the page starting at token and the token
+// of the next page, the next token is nil after the last page
+type fetchPage[T any] func(token int32) ([]T, *int32, error)
+
+// Iterator iterates over the entries of a list of the REST API a page at a time,
+// lists which are not paginated by the REST API are returned as a single page.
+//
+//	it := cp.Volumes()
+//	for it.Next() {
+//		msv := it.Value()
+//	}
+//	err := it.Err()
+type Iterator[T any] struct {
+	fetch   fetchPage[T]
+	page    []T
+	ix      int
+	next    *int32
+	started bool
+	err     error
+}
+
+func newIterator[T any](fetch fetchPage[T]) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, ix: -1}
+}
+
+// Next advances to the next entry, fetching the next page if required,
+// it returns false after the last entry or on error
+func (it *Iterator[T]) Next() bool {
+	for {
+		if it.ix+1 < len(it.page) {
+			it.ix++
+			return true
+		}
+		if it.err != nil || (it.started && it.next == nil) {
+			return false
+		}
+		var token int32
+		if it.next != nil {
+			token = *it.next
+		}
+		it.page, it.next, it.err = it.fetch(token)
+		it.ix = -1
+		it.started = true
+	}
+}
+
+// Value returns the current entry
+func (it *Iterator[T]) Value() T {
+	return it.page[it.ix]
+}
+
+// Err returns the error which ended the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// ForEach calls fn for each remaining entry, stopping on the first error
+func (it *Iterator[T]) ForEach(fn func(T) error) error {
+	for it.Next() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Collect returns the remaining entries
+func (it *Iterator[T]) Collect() ([]T, error) {
+	var entries []T
+	err := it.ForEach(func(entry T) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+func (oacw OAClientWrapper) getVolumesPage(token int32) (openapiClient.Volumes, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().VolumesAPI.GetVolumes(ctx).StartingToken(token).MaxEntries(restPageSize)
+	volumes, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	if volumes == nil {
+		return openapiClient.Volumes{}, err, statusCode
+	}
+	return *volumes, err, statusCode
+}
+
+func (oacw OAClientWrapper) getSnapshotsPage(volUuid string, snapshotId string, token int32) (openapiClient.VolumeSnapshots, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().SnapshotsAPI.GetVolumesSnapshots(ctx).StartingToken(token).MaxEntries(restPageSize)
+	if volUuid != "" {
+		req = req.VolumeId(volUuid)
+	}
+	if snapshotId != "" {
+		req = req.SnapshotId(snapshotId)
+	}
+	snapshots, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	if snapshots == nil {
+		return openapiClient.VolumeSnapshots{}, err, statusCode
+	}
+	return *snapshots, err, statusCode
+}
+
+func (oacw OAClientWrapper) getAppNodesPage(token int32) (openapiClient.AppNodes, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().AppNodesAPI.GetAppNodes(ctx).StartingToken(token).MaxEntries(restPageSize)
+	appNodes, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	if appNodes == nil {
+		return openapiClient.AppNodes{}, err, statusCode
+	}
+	return *appNodes, err, statusCode
+}
+
+func (oacw OAClientWrapper) getReplicas() ([]openapiClient.Replica, error, int) {
+	var statusCode int
+	ctx, cancel := oacw.callContext()
+	defer cancel()
+
+	req := oacw.client().ReplicasAPI.GetReplicas(ctx)
+	replicas, resp, err := req.Execute()
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	err = restError(err, statusCode)
+	return replicas, err, statusCode
+}
+
+// rawVolumes iterates over the volumes as returned by the REST API
+func (oacw OAClientWrapper) rawVolumes() *Iterator[openapiClient.Volume] {
+	return newIterator(func(token int32) ([]openapiClient.Volume, *int32, error) {
+		vols, err, _ := oacw.getVolumesPage(token)
+		return vols.Entries, vols.NextToken, err
+	})
+}
+
+// snapshotToSchema converts a snapshot, the kubectl plugin outputs snapshots
+// as the REST API, so the conversion is made through the JSON representation
+func snapshotToSchema(snapshot openapiClient.VolumeSnapshot) (common.SnapshotSchema, error) {
+	var schema common.SnapshotSchema
+	data, err := json.Marshal(snapshot)
+	if err == nil {
+		err = json.Unmarshal(data, &schema)
+	}
+	if err != nil {
+		return schema, fmt.Errorf("failed to convert snapshot %s, error: %w", snapshot.Definition.Spec.Uuid, err)
+	}
+	return schema, nil
+}
+
+// Volumes iterates over all volumes
+func (cp CPv1RestApi) Volumes() *Iterator[common.MayastorVolume] {
+	return newIterator(func(token int32) ([]common.MayastorVolume, *int32, error) {
+		vols, err, _ := cp.oa.getVolumesPage(token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list volumes, starting token %d, %w", token, err)
+		}
+		msvs := make([]common.MayastorVolume, 0, len(vols.Entries))
+		for _, vol := range vols.Entries {
+			msvs = append(msvs, cp.oa.volToMsv(vol))
+		}
+		return msvs, vols.NextToken, nil
+	})
+}
+
+// Snapshots iterates over the snapshots of the volume, or of all volumes if volUuid is ""
+func (cp CPv1RestApi) Snapshots(volUuid string) *Iterator[common.SnapshotSchema] {
+	return cp.snapshots(volUuid, "")
+}
+
+func (cp CPv1RestApi) snapshots(volUuid string, snapshotId string) *Iterator[common.SnapshotSchema] {
+	return newIterator(func(token int32) ([]common.SnapshotSchema, *int32, error) {
+		snapshots, err, _ := cp.oa.getSnapshotsPage(volUuid, snapshotId, token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list snapshots, volume %q starting token %d, %w", volUuid, token, err)
+		}
+		schemas := make([]common.SnapshotSchema, 0, len(snapshots.Entries))
+		for _, snapshot := range snapshots.Entries {
+			schema, err := snapshotToSchema(snapshot)
+			if err != nil {
+				return nil, nil, err
+			}
+			schemas = append(schemas, schema)
+		}
+		return schemas, snapshots.NextToken, nil
+	})
+}
+
+// AppNodes iterates over the app nodes registered with the control plane
+func (cp CPv1RestApi) AppNodes() *Iterator[common.AppNode] {
+	return newIterator(func(token int32) ([]common.AppNode, *int32, error) {
+		appNodes, err, _ := cp.oa.getAppNodesPage(token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list app nodes, starting token %d, %w", token, err)
+		}
+		entries := make([]common.AppNode, 0, len(appNodes.Entries))
+		for _, appNode := range appNodes.Entries {
+			entries = append(entries, oaAppNodeToAppNode(appNode))
+		}
+		return entries, appNodes.NextToken, nil
+	})
+}
+
+// Replicas iterates over all replicas, the REST API does not paginate replicas
+func (cp CPv1RestApi) Replicas() *Iterator[common.MsvReplica] {
+	return newIterator(func(token int32) ([]common.MsvReplica, *int32, error) {
+		replicas, err, _ := cp.oa.getReplicas()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list replicas, %w", err)
+		}
+		msvReplicas := make([]common.MsvReplica, 0, len(replicas))
+		for _, replica := range replicas {
+			msvReplicas = append(msvReplicas, common.MsvReplica{
+				Uuid: replica.Uuid,
+				Uri:  replica.Uri,
+				Replica: common.Replica{
+					Node:  replica.Node,
+					Pool:  replica.Pool,
+					State: string(replica.State),
+				},
+			})
+		}
+		return msvReplicas, nil, nil
+	})
+}
+
+// Nexuses iterates over all nexuses, the REST API does not paginate nexuses
+func (cp CPv1RestApi) Nexuses() *Iterator[common.MayastorNexus] {
+	return newIterator(func(token int32) ([]common.MayastorNexus, *int32, error) {
+		msns, err := cp.ListNexuses("")
+		return msns, nil, err
+	})
+}
+
+// ForEachMsv calls fn for each volume, fetching the volumes a page at a time,
+// iteration stops on the first error
+func (cp CPv1RestApi) ForEachMsv(fn func(common.MayastorVolume) error) error {
+	return cp.Volumes().ForEach(fn)
+}
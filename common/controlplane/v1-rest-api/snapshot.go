@@ -7,23 +7,30 @@ import (
 )
 
 func (cp CPv1RestApi) GetSnapshots() ([]common.SnapshotSchema, error) {
-	// #TODO implement REST api for snapshot
-	panic(fmt.Errorf("not implemented REST api for getting snapshots"))
+	return cp.Snapshots("").Collect()
 }
 
 func (cp CPv1RestApi) GetSnapshot(snapshotId string) (common.SnapshotSchema, error) {
-	// #TODO implement REST api for snapshot
-	panic(fmt.Errorf("not implemented REST api for getting snapshots"))
+	return cp.getSnapshot("", snapshotId)
 }
 
 func (cp CPv1RestApi) GetVolumeSnapshot(volUuid string, snapshotId string) (common.SnapshotSchema, error) {
-	// #TODO implement REST api for snapshot
-	panic(fmt.Errorf("not implemented REST api for getting snapshots"))
+	return cp.getSnapshot(volUuid, snapshotId)
 }
 
 func (cp CPv1RestApi) GetVolumeSnapshots(volUuid string) ([]common.SnapshotSchema, error) {
-	// #TODO implement REST api for snapshot
-	panic(fmt.Errorf("not implemented REST api for getting snapshots"))
+	return cp.Snapshots(volUuid).Collect()
+}
+
+func (cp CPv1RestApi) getSnapshot(volUuid string, snapshotId string) (common.SnapshotSchema, error) {
+	it := cp.snapshots(volUuid, snapshotId)
+	if it.Next() {
+		return it.Value(), nil
+	}
+	if it.Err() != nil {
+		return common.SnapshotSchema{}, it.Err()
+	}
+	return common.SnapshotSchema{}, fmt.Errorf("snapshot %s not found", snapshotId)
 }
 
 func (cp CPv1RestApi) GetVolumeSnapshotTopology() ([]common.SnapshotSchema, error) {
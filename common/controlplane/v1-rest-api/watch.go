@@ -0,0 +1,56 @@
+package v1_rest_api
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/openebs/openebs-e2e/common/controlplane/watch"
+	"github.com/openebs/openebs-e2e/common/e2e_config"
+)
+
+var (
+	receiver     *watch.Receiver
+	receiverErr  error
+	receiverOnce sync.Once
+)
+
+// watchReceiver returns the receiver of the watch callbacks, it is started on first use
+func (cp CPv1RestApi) watchReceiver() (*watch.Receiver, error) {
+	receiverOnce.Do(func() {
+		host := e2e_config.GetConfig().WatchCallbackHost
+		if host == "" {
+			if host, receiverErr = watch.OutboundIP(cp.oa.restNode()); receiverErr != nil {
+				receiverErr = fmt.Errorf("failed to find the watch callback host, error: %v", receiverErr)
+				return
+			}
+		}
+		receiver, receiverErr = watch.NewReceiver(fmt.Sprintf(":%d", e2e_config.GetConfig().WatchCallbackPort), host)
+	})
+	return receiver, receiverErr
+}
+
+// WatchMsv registers a watch of the volume with the control plane, changes of the
+// volume spec are notified by the control plane, the volume state is resynced every
+// e2e_config WatchResyncInterval
+func (cp CPv1RestApi) WatchMsv(uuid string) (*watch.MsvWatch, error) {
+	rcv, err := cp.watchReceiver()
+	if err != nil {
+		return nil, err
+	}
+	w := watch.NewMsvWatch(uuid, cp, watch.ResyncInterval())
+	callback := rcv.Register(w)
+	if err, _ = cp.oa.putWatchVolume(uuid, callback); err != nil {
+		_ = w.Stop()
+		return nil, fmt.Errorf("failed to watch volume %s, error: %w", uuid, err)
+	}
+	w.OnStop(func() error {
+		err, status := cp.oa.delWatchVolume(uuid, callback)
+		// the watch is removed with the volume
+		if err != nil && status != 404 {
+			return fmt.Errorf("failed to delete watch of volume %s, error: %w", uuid, err)
+		}
+		return nil
+	})
+	w.Start()
+	return w, nil
+}
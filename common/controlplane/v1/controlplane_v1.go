@@ -1,14 +1,20 @@
 package v1
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type CPv1 struct {
 	nodeIPAddresses []string
+	// ctx bounds the runs of the kubectl plugin, the plugin is killed when ctx is done
+	ctx context.Context
 }
 
 func (cp CPv1) Version() string {
@@ -25,6 +31,9 @@ var regExs = []*regexp.Regexp{
 }
 
 func (cp CPv1) IsTimeoutError(err error) bool {
+	if errors.Is(err, cperrors.ErrTimeout) {
+		return true
+	}
 	str := fmt.Sprintf("%v", err)
 	for _, re := range regExs {
 		frags := re.FindSubmatch([]byte(str))
@@ -115,9 +124,17 @@ func MakeCP() (CPv1, error) {
 	logf.Log.Info("Control Plane v1 - Kubectl plugin")
 	return CPv1{
 		nodeIPAddresses: addrs,
+		ctx:             context.Background(),
 	}, nil
 }
 
+// WithContext returns the control plane with its runs of the kubectl plugin
+// bounded by ctx, eg: the SpecContext of a Ginkgo spec
+func (cp CPv1) WithContext(ctx context.Context) CPv1 {
+	cp.ctx = ctx
+	return cp
+}
+
 func (cp CPv1) NodeStateOnline() string {
 	return "Online"
 }
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/openebs/openebs-e2e/common"
@@ -50,12 +49,12 @@ type NodeCordonState struct {
 func (cp CPv1) CordonNode(nodeName string, cordonLabel string) error {
 	logf.Log.Info("Executing cordon node command", "node", nodeName, "cordon label", cordonLabel)
 	kubectlPlugin := GetPluginPath()
-	cmd := exec.Command(kubectlPlugin, "-n", common.NSMayastor(), "cordon", "node", nodeName, cordonLabel)
+	cmd, done := pluginCommand(cp.ctx, kubectlPlugin, "-n", common.NSMayastor(), "cordon", "node", nodeName, cordonLabel)
 	var out bytes.Buffer
 	cmd.Stdout = &out
-	err := cmd.Run()
+	err := done(cmd.Run())
 	if err != nil {
-		return fmt.Errorf("plugin failed to cordon node %s with cordon label %s , error %v", nodeName, cordonLabel, err)
+		return fmt.Errorf("plugin failed to cordon node %s with cordon label %s , error %w", nodeName, cordonLabel, err)
 	} else if strings.Contains(out.String(), ErrorResponse) {
 		return fmt.Errorf("REST api error,failed to cordon node %s with label %s, error %v", nodeName, cordonLabel, out.String())
 	}
@@ -65,12 +64,12 @@ func (cp CPv1) CordonNode(nodeName string, cordonLabel string) error {
 func (cp CPv1) GetCordonNodeLabels(nodeName string) ([]string, error) {
 	logf.Log.Info("Executing command to get cordon node labels", "node", nodeName)
 	pluginPath := GetPluginPath()
-	cmd := exec.Command(pluginPath, "-n", common.NSMayastor(), "get", "node", nodeName, "-ojson")
+	cmd, done := pluginCommand(cp.ctx, pluginPath, "-n", common.NSMayastor(), "get", "node", nodeName, "-ojson")
 	var out bytes.Buffer
 	cmd.Stdout = &out
-	err := cmd.Run()
+	err := done(cmd.Run())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cordon labels for node %s, error %v", nodeName, err)
+		return nil, fmt.Errorf("failed to get cordon labels for node %s, error %w", nodeName, err)
 	}
 	outputString := out.String()
 	var cordonLabelsInfo NodeCordonLabelsInfo
@@ -98,12 +97,12 @@ func (cp CPv1) GetCordonNodeLabels(nodeName string) ([]string, error) {
 func (cp CPv1) UnCordonNode(nodeName string, cordonLabel string) error {
 	logf.Log.Info("Executing uncordon node command", "node", nodeName, "cordon label", cordonLabel)
 	kubectlPlugin := GetPluginPath()
-	cmd := exec.Command(kubectlPlugin, "-n", common.NSMayastor(), "uncordon", "node", nodeName, cordonLabel)
+	cmd, done := pluginCommand(cp.ctx, kubectlPlugin, "-n", common.NSMayastor(), "uncordon", "node", nodeName, cordonLabel)
 	var out bytes.Buffer
 	cmd.Stdout = &out
-	err := cmd.Run()
+	err := done(cmd.Run())
 	if err != nil {
-		return fmt.Errorf("plugin failed to uncordon node %s with cordon label %s , error %v", nodeName, cordonLabel, err)
+		return fmt.Errorf("plugin failed to uncordon node %s with cordon label %s , error %w", nodeName, cordonLabel, err)
 	} else if strings.Contains(out.String(), ErrorResponse) {
 		return fmt.Errorf("REST api error,failed to uncordon node %s with label %s, error %v", nodeName, cordonLabel, out.String())
 	}
@@ -4,25 +4,27 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/openebs/openebs-e2e/common"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// DrainNode drain the given node with label
+// DrainNode drain the given node with label, the plugin waits for the drain
+// so its run is bounded by drainTimeOut plus the control plane call timeout
 func (cp CPv1) DrainNode(nodeName string, drainLabel string, drainTimeOut int) error {
 	logf.Log.Info("Executing drain node command", "node", nodeName, "drain label", drainLabel, "timeout", drainTimeOut)
 	kubectlPlugin := GetPluginPath()
+	timeout := time.Duration(drainTimeOut)*time.Second + callTimeout()
 	// #FIXME remove drain timeout drain command is fuctional
-	cmd := exec.Command(kubectlPlugin, "-n", common.NSMayastor(), "drain", "node", nodeName, drainLabel, "--drain-timeout", fmt.Sprintf("%ds", drainTimeOut))
+	cmd, done := pluginCommandWithTimeout(cp.ctx, timeout, kubectlPlugin, "-n", common.NSMayastor(), "drain", "node", nodeName, drainLabel, "--drain-timeout", fmt.Sprintf("%ds", drainTimeOut))
 	var out bytes.Buffer
 	cmd.Stdout = &out
-	err := cmd.Run()
+	err := done(cmd.Run())
 	if err != nil {
-		return fmt.Errorf("%s plugin failed to drain node %s with drain label %s , error %v", kubectlPlugin, nodeName, drainLabel, err)
+		return fmt.Errorf("%s plugin failed to drain node %s with drain label %s , error %w", kubectlPlugin, nodeName, drainLabel, err)
 	} else if strings.Contains(out.String(), ErrorResponse) {
 		return fmt.Errorf("REST api error, failed to drain node %s with label %s, error %v", nodeName, drainLabel, out.String())
 	}
@@ -33,12 +35,12 @@ func (cp CPv1) DrainNode(nodeName string, drainLabel string, drainTimeOut int) e
 func (cp CPv1) GetDrainNodeLabels(nodeName string) ([]string, []string, error) {
 	logf.Log.Info("Executing command to get drain node labels", "node", nodeName)
 	pluginPath := GetPluginPath()
-	cmd := exec.Command(pluginPath, "-n", common.NSMayastor(), "get", "node", nodeName, "-ojson")
+	cmd, done := pluginCommand(cp.ctx, pluginPath, "-n", common.NSMayastor(), "get", "node", nodeName, "-ojson")
 	var out bytes.Buffer
 	cmd.Stdout = &out
-	err := cmd.Run()
+	err := done(cmd.Run())
 	if err != nil {
-		return nil, nil, fmt.Errorf("%s failed to get cordon labels for node %s, error %v", pluginPath, nodeName, err)
+		return nil, nil, fmt.Errorf("%s failed to get cordon labels for node %s, error %w", pluginPath, nodeName, err)
 	}
 	outputString := out.String()
 	var cordonLabelsInfo NodeCordonLabelsInfo
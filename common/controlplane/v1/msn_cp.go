@@ -2,9 +2,9 @@ package v1
 
 // Utility functions for Mayastor control plane volume
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/openebs/openebs-e2e/common"
@@ -30,13 +30,14 @@ type msnState struct {
 	Node_nqn     string `json:"node_nqn"`
 }
 
-func GetMayastorCpNode(nodeName string) (*MayastorCpNode, error) {
+func GetMayastorCpNode(ctx context.Context, nodeName string) (*MayastorCpNode, error) {
 	pluginpath := GetPluginPath()
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "node", nodeName)
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "node", nodeName)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -48,18 +49,19 @@ func GetMayastorCpNode(nodeName string) (*MayastorCpNode, error) {
 		if !HasNotFoundRestJsonError(msg) {
 			logf.Log.Info("Failed to unmarshal (get node)", "string", msg, "node", nodeName)
 		}
-		return nil, fmt.Errorf("%s", msg)
+		return nil, pluginError(msg)
 	}
 	return &response, nil
 }
 
-func ListMayastorCpNodes() ([]MayastorCpNode, error) {
+func ListMayastorCpNodes(ctx context.Context) ([]MayastorCpNode, error) {
 	pluginpath := GetPluginPath()
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "nodes")
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "nodes")
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -69,13 +71,13 @@ func ListMayastorCpNodes() ([]MayastorCpNode, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		logf.Log.Info("Failed to unmarshal (get nodes)", "string", string(jsonInput))
-		return []MayastorCpNode{}, fmt.Errorf("%s", errMsg)
+		return []MayastorCpNode{}, pluginError(errMsg)
 	}
 	return response, nil
 }
 
-func GetMayastorNodeStatus(nodeName string) (string, error) {
-	msn, err := GetMayastorCpNode(nodeName)
+func GetMayastorNodeStatus(ctx context.Context, nodeName string) (string, error) {
+	msn, err := GetMayastorCpNode(ctx, nodeName)
 	if err == nil {
 		return msn.State.Status, nil
 	}
@@ -102,9 +104,9 @@ func cpNodeToMsn(cpMsn *MayastorCpNode) common.MayastorNode {
 // GetMSN Get pointer to a mayastor control plane volume
 // returns nil and no error if the msn is in pending state.
 func (cp CPv1) GetMSN(nodeName string) (*common.MayastorNode, error) {
-	cpMsn, err := GetMayastorCpNode(nodeName)
+	cpMsn, err := GetMayastorCpNode(cp.ctx, nodeName)
 	if err != nil {
-		return nil, fmt.Errorf("GetMSN: %v", err)
+		return nil, fmt.Errorf("GetMSN: %w", err)
 	}
 
 	if cpMsn == nil {
@@ -118,7 +120,7 @@ func (cp CPv1) GetMSN(nodeName string) (*common.MayastorNode, error) {
 
 func (cp CPv1) ListMsns() ([]common.MayastorNode, error) {
 	var msns []common.MayastorNode
-	list, err := ListMayastorCpNodes()
+	list, err := ListMayastorCpNodes(cp.ctx)
 	if err == nil {
 		for _, item := range list {
 			msns = append(msns, cpNodeToMsn(&item))
@@ -128,9 +130,9 @@ func (cp CPv1) ListMsns() ([]common.MayastorNode, error) {
 }
 
 func (cp CPv1) GetMsNodeStatus(nodeName string) (string, error) {
-	cpMsn, err := GetMayastorCpNode(nodeName)
+	cpMsn, err := GetMayastorCpNode(cp.ctx, nodeName)
 	if err != nil {
-		return "", fmt.Errorf("GetMsNodeStatus: %v", err)
+		return "", fmt.Errorf("GetMsNodeStatus: %w", err)
 	}
 	return cpMsn.State.Status, nil
 }
@@ -149,14 +151,79 @@ func (cp CPv1) UpdateNodeLabel(nodeName string, labelKey, labelValue string) err
 		args = append(args, fmt.Sprintf("%s-", labelKey))
 	}
 
-	cmd := exec.Command(pluginPath, args...)
+	cmd, done := pluginCommand(cp.ctx, pluginPath, args...)
 
 	// Print the command that will be executed
 	logf.Log.Info("Executing", "command", strings.Join(cmd.Args, " "))
 
-	if err := cmd.Run(); err != nil {
+	if err := done(cmd.Run()); err != nil {
 		// Print the error message if the command fails
-		return fmt.Errorf("plugin failed to update node label %s: %v", nodeName, err)
+		return fmt.Errorf("plugin failed to update node label %s: %w", nodeName, err)
 	}
 	return nil
 }
+
+// ListNodeBlockDevices returns the block devices of the node which are available
+// for use by pools, or all block devices of the node if all is true
+func (cp CPv1) ListNodeBlockDevices(nodeName string, all bool) ([]common.BlockDevice, error) {
+	pluginpath := GetPluginPath()
+
+	args := []string{"-n", common.NSMayastor(), "-ojson", "get", "block-devices", nodeName}
+	if all {
+		args = append(args, "--all")
+	}
+	cmd, done := pluginCommand(cp.ctx, pluginpath, args...)
+	jsonInput, err := cmd.CombinedOutput()
+	err = done(err)
+	err = CheckPluginError(jsonInput, err)
+	if err != nil {
+		return nil, err
+	}
+	var response []common.BlockDevice
+	err = json.Unmarshal(jsonInput, &response)
+	if err != nil {
+		logf.Log.Info("Failed to unmarshal (get block-devices)", "string", string(jsonInput))
+		return nil, pluginError(string(jsonInput))
+	}
+	return response, nil
+}
+
+// GetAppNode returns the app node registered with the control plane
+func (cp CPv1) GetAppNode(id string) (*common.AppNode, error) {
+	pluginpath := GetPluginPath()
+
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "app-node", id)
+	jsonInput, err := cmd.CombinedOutput()
+	err = done(err)
+	err = CheckPluginError(jsonInput, err)
+	if err != nil {
+		return nil, fmt.Errorf("GetAppNode: %w", err)
+	}
+	var response common.AppNode
+	err = json.Unmarshal(jsonInput, &response)
+	if err != nil {
+		logf.Log.Info("Failed to unmarshal (get app-node)", "string", string(jsonInput))
+		return nil, pluginError(string(jsonInput))
+	}
+	return &response, nil
+}
+
+// ListAppNodes returns the app nodes registered with the control plane
+func (cp CPv1) ListAppNodes() ([]common.AppNode, error) {
+	pluginpath := GetPluginPath()
+
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "app-nodes")
+	jsonInput, err := cmd.CombinedOutput()
+	err = done(err)
+	err = CheckPluginError(jsonInput, err)
+	if err != nil {
+		return nil, fmt.Errorf("ListAppNodes: %w", err)
+	}
+	var response []common.AppNode
+	err = json.Unmarshal(jsonInput, &response)
+	if err != nil {
+		logf.Log.Info("Failed to unmarshal (get app-nodes)", "string", string(jsonInput))
+		return nil, pluginError(string(jsonInput))
+	}
+	return response, nil
+}
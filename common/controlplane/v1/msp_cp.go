@@ -1,10 +1,10 @@
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/openebs/openebs-e2e/common"
 
@@ -40,13 +40,14 @@ func (cp CPv1) CreatePoolOnInstall() bool {
 	return no_pool_install != "true"
 }
 
-func GetMayastorCpPool(name string) (*MayastorCpPool, error) {
+func GetMayastorCpPool(ctx context.Context, name string) (*MayastorCpPool, error) {
 	pluginpath := GetPluginPath()
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "pool", name)
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "pool", name)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -59,18 +60,19 @@ func GetMayastorCpPool(name string) (*MayastorCpPool, error) {
 		if !HasNotFoundRestJsonError(msg) {
 			logf.Log.Info("Failed to unmarshal (get pool)", "string", msg)
 		}
-		return nil, fmt.Errorf("%s", msg)
+		return nil, pluginError(msg)
 	}
 	return &response, nil
 }
 
-func ListMayastorCpPools() ([]MayastorCpPool, error) {
+func ListMayastorCpPools(ctx context.Context) ([]MayastorCpPool, error) {
 	pluginpath := GetPluginPath()
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "pools")
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "pools")
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -80,7 +82,7 @@ func ListMayastorCpPools() ([]MayastorCpPool, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		logf.Log.Info("Failed to unmarshal (get pools)", "string", string(jsonInput))
-		return []MayastorCpPool{}, fmt.Errorf("%s", errMsg)
+		return []MayastorCpPool{}, pluginError(errMsg)
 	}
 	return response, nil
 }
@@ -110,9 +112,9 @@ func cpMspToMsp(cpMsp *MayastorCpPool) common.MayastorPool {
 
 // GetMsPool Get pointer to a mayastor control plane pool
 func (cp CPv1) GetMsPool(poolName string) (*common.MayastorPool, error) {
-	cpMsp, err := GetMayastorCpPool(poolName)
+	cpMsp, err := GetMayastorCpPool(cp.ctx, poolName)
 	if err != nil {
-		return nil, fmt.Errorf("GetMsPool: %v", err)
+		return nil, fmt.Errorf("GetMsPool: %w", err)
 	}
 
 	if cpMsp == nil {
@@ -126,7 +128,7 @@ func (cp CPv1) GetMsPool(poolName string) (*common.MayastorPool, error) {
 
 func (cp CPv1) ListMsPools() ([]common.MayastorPool, error) {
 	var msps []common.MayastorPool
-	list, err := ListMayastorCpPools()
+	list, err := ListMayastorCpPools(cp.ctx)
 	if err == nil {
 		for _, item := range list {
 			msps = append(msps, cpMspToMsp(&item))
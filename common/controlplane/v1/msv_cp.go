@@ -2,14 +2,16 @@ package v1
 
 // Utility functions for Mayastor control plane volume
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -22,13 +24,14 @@ func HasNotFoundRestJsonError(str string) bool {
 	return len(frags) == 2 && string(frags[1]) == "NotFound"
 }
 
-func getMayastorCpVolume(uuid string) (*common.MayastorVolume, error) {
+func getMayastorCpVolume(ctx context.Context, uuid string) (*common.MayastorVolume, error) {
 	pluginpath := GetPluginPath()
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume", uuid)
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume", uuid)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -40,18 +43,19 @@ func getMayastorCpVolume(uuid string) (*common.MayastorVolume, error) {
 		if !HasNotFoundRestJsonError(msg) {
 			logf.Log.Info("Failed to unmarshal (get volume)", "string", msg)
 		}
-		return nil, fmt.Errorf("%s", msg)
+		return nil, pluginError(msg)
 	}
 	return &response, nil
 }
 
-func listMayastorCpVolumes() ([]common.MayastorVolume, error) {
+func listMayastorCpVolumes(ctx context.Context) ([]common.MayastorVolume, error) {
 	pluginpath := GetPluginPath()
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volumes")
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volumes")
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -61,18 +65,19 @@ func listMayastorCpVolumes() ([]common.MayastorVolume, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		logf.Log.Info("Failed to unmarshal (get volumes)", "string", string(jsonInput))
-		return []common.MayastorVolume{}, fmt.Errorf("%s", errMsg)
+		return []common.MayastorVolume{}, pluginError(errMsg)
 	}
 	return response, nil
 }
 
-func scaleMayastorVolume(uuid string, replicaCount int) error {
+func scaleMayastorVolume(ctx context.Context, uuid string, replicaCount int) error {
 	pluginpath := GetPluginPath()
 
 	var err error
 	var jsonInput []byte
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "scale", "volume", uuid, strconv.Itoa(replicaCount))
+	cmd, done := pluginCommand(ctx, pluginpath, "-n", common.NSMayastor(), "scale", "volume", uuid, strconv.Itoa(replicaCount))
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return err
@@ -80,32 +85,32 @@ func scaleMayastorVolume(uuid string, replicaCount int) error {
 	return nil
 }
 
-func GetMayastorVolumeState(volName string) (string, error) {
-	msv, err := getMayastorCpVolume(volName)
+func GetMayastorVolumeState(ctx context.Context, volName string) (string, error) {
+	msv, err := getMayastorCpVolume(ctx, volName)
 	if err == nil {
 		return msv.State.Status, nil
 	}
 	return "", err
 }
 
-func GetMayastorVolumeChildren(volName string) ([]common.TargetChild, error) {
-	msv, err := getMayastorCpVolume(volName)
+func GetMayastorVolumeChildren(ctx context.Context, volName string) ([]common.TargetChild, error) {
+	msv, err := getMayastorCpVolume(ctx, volName)
 	if err != nil {
 		return nil, err
 	}
 	return msv.State.Target.Children, nil
 }
 
-func GetMayastorVolumeChildState(uuid string) (string, error) {
-	msv, err := getMayastorCpVolume(uuid)
+func GetMayastorVolumeChildState(ctx context.Context, uuid string) (string, error) {
+	msv, err := getMayastorCpVolume(ctx, uuid)
 	if err != nil {
 		return "", err
 	}
 	return msv.State.Target.State, nil
 }
 
-func GetMayastorVolumeTargetNode(uuid string) (string, error) {
-	msv, err := getMayastorCpVolume(uuid)
+func GetMayastorVolumeTargetNode(ctx context.Context, uuid string) (string, error) {
+	msv, err := getMayastorCpVolume(ctx, uuid)
 	if err != nil {
 		return "", err
 	}
@@ -113,25 +118,25 @@ func GetMayastorVolumeTargetNode(uuid string) (string, error) {
 }
 
 func (cp CPv1) GetMsvTargetUuid(uuid string) (string, error) {
-	msv, err := getMayastorCpVolume(uuid)
+	msv, err := getMayastorCpVolume(cp.ctx, uuid)
 	if err != nil {
 		return "", err
 	}
 	return msv.State.Target.Uuid, nil
 }
 
-func IsMayastorVolumePublished(uuid string) bool {
-	msv, err := getMayastorCpVolume(uuid)
+func IsMayastorVolumePublished(ctx context.Context, uuid string) bool {
+	msv, err := getMayastorCpVolume(ctx, uuid)
 	if err == nil {
 		return msv.Spec.Target.Node != ""
 	}
 	return false
 }
 
-func IsMayastorVolumeDeleted(uuid string) bool {
-	msv, err := getMayastorCpVolume(uuid)
+func IsMayastorVolumeDeleted(ctx context.Context, uuid string) bool {
+	msv, err := getMayastorCpVolume(ctx, uuid)
 	if err != nil {
-		if HasNotFoundRestJsonError(fmt.Sprintf("%v", err)) {
+		if errors.Is(err, cperrors.ErrNotFound) {
 			return true
 		}
 		logf.Log.Error(err, "IsMayastorVolumeDeleted msv is nil")
@@ -144,11 +149,11 @@ func IsMayastorVolumeDeleted(uuid string) bool {
 	return false
 }
 
-func CheckForMayastorVolumes() (bool, error) {
+func CheckForMayastorVolumes(ctx context.Context) (bool, error) {
 	logf.Log.Info("CheckForMayastorVolumes")
 	foundResources := false
 
-	msvs, err := listMayastorCpVolumes()
+	msvs, err := listMayastorCpVolumes(ctx)
 	if err == nil && msvs != nil && len(msvs) != 0 {
 		logf.Log.Info("CheckForVolumeResources: found MayastorVolumes",
 			"MayastorVolumes", msvs)
@@ -157,9 +162,9 @@ func CheckForMayastorVolumes() (bool, error) {
 	return foundResources, err
 }
 
-func CheckAllMayastorVolumesAreHealthy() error {
+func CheckAllMayastorVolumesAreHealthy(ctx context.Context) error {
 	allHealthy := true
-	msvs, err := listMayastorCpVolumes()
+	msvs, err := listMayastorCpVolumes(ctx)
 	if err == nil && msvs != nil && len(msvs) != 0 {
 		for _, msv := range msvs {
 			if msv.State.Status != common.MsvStatusStateOnline {
@@ -182,9 +187,9 @@ func CheckAllMayastorVolumesAreHealthy() error {
 // GetMSV Get pointer to a mayastor control plane volume
 // returns nil and no error if the msv is in pending state.
 func (cp CPv1) GetMSV(uuid string) (*common.MayastorVolume, error) {
-	cpMsv, err := getMayastorCpVolume(uuid)
+	cpMsv, err := getMayastorCpVolume(cp.ctx, uuid)
 	if err != nil {
-		return nil, fmt.Errorf("GetMSV: %v", err)
+		return nil, fmt.Errorf("GetMSV: %w", err)
 	}
 	if cpMsv.Spec.Uuid == "" {
 		logf.Log.Info("Msv not found", "uuid", uuid)
@@ -213,7 +218,7 @@ func (cp CPv1) GetMSV(uuid string) (*common.MayastorVolume, error) {
 // GetMsvNodes Retrieve the nexus node hosting the Mayastor Volume,
 // and the names of the replica nodes
 func (cp CPv1) GetMsvNodes(uuid string) (string, []string) {
-	msv, err := getMayastorCpVolume(uuid)
+	msv, err := getMayastorCpVolume(cp.ctx, uuid)
 	if err != nil {
 		logf.Log.Info("failed to get mayastor volume", "uuid", uuid)
 		return "", nil
@@ -233,7 +238,22 @@ func (cp CPv1) GetMsvNodes(uuid string) (string, []string) {
 }
 
 func (cp CPv1) ListMsvs() ([]common.MayastorVolume, error) {
-	return listMayastorCpVolumes()
+	return listMayastorCpVolumes(cp.ctx)
+}
+
+// ForEachMsv calls fn for each volume, iteration stops on the first error,
+// the kubectl plugin fetches all volumes
+func (cp CPv1) ForEachMsv(fn func(common.MayastorVolume) error) error {
+	msvs, err := listMayastorCpVolumes(cp.ctx)
+	if err != nil {
+		return err
+	}
+	for _, msv := range msvs {
+		if err = fn(msv); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (cp CPv1) ListRestoredMsvs() ([]common.MayastorVolume, error) {
@@ -241,8 +261,9 @@ func (cp CPv1) ListRestoredMsvs() ([]common.MayastorVolume, error) {
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volumes", "--source", "snapshot")
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volumes", "--source", "snapshot")
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -252,19 +273,19 @@ func (cp CPv1) ListRestoredMsvs() ([]common.MayastorVolume, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		logf.Log.Info("Failed to unmarshal (get volumes)", "string", string(jsonInput))
-		return []common.MayastorVolume{}, fmt.Errorf("%s", errMsg)
+		return []common.MayastorVolume{}, pluginError(errMsg)
 	}
 	return response, nil
 }
 
 func (cp CPv1) SetMsvReplicaCount(uuid string, replicaCount int) error {
-	err := scaleMayastorVolume(uuid, replicaCount)
+	err := scaleMayastorVolume(cp.ctx, uuid, replicaCount)
 	logf.Log.Info("ScaleMayastorVolume", "Num_replicas", replicaCount)
 	return err
 }
 
 func (cp CPv1) GetMsvState(uuid string) (string, error) {
-	return GetMayastorVolumeState(uuid)
+	return GetMayastorVolumeState(cp.ctx, uuid)
 }
 
 func (cp CPv1) GetMsvReplicas(volName string) ([]common.MsvReplica, error) {
@@ -303,27 +324,27 @@ func (cp CPv1) GetMsvReplicaTopology(volUuid string) (common.ReplicaTopology, er
 }
 
 func (cp CPv1) GetMsvNexusChildren(volName string) ([]common.TargetChild, error) {
-	return GetMayastorVolumeChildren(volName)
+	return GetMayastorVolumeChildren(cp.ctx, volName)
 }
 
 func (cp CPv1) GetMsvNexusState(uuid string) (string, error) {
-	return GetMayastorVolumeChildState(uuid)
+	return GetMayastorVolumeChildState(cp.ctx, uuid)
 }
 
 func (cp CPv1) IsMsvPublished(uuid string) bool {
-	return IsMayastorVolumePublished(uuid)
+	return IsMayastorVolumePublished(cp.ctx, uuid)
 }
 
 func (cp CPv1) IsMsvDeleted(uuid string) bool {
-	return IsMayastorVolumeDeleted(uuid)
+	return IsMayastorVolumeDeleted(cp.ctx, uuid)
 }
 
 func (cp CPv1) CheckForMsvs() (bool, error) {
-	return CheckForMayastorVolumes()
+	return CheckForMayastorVolumes(cp.ctx)
 }
 
 func (cp CPv1) CheckAllMsvsAreHealthy() error {
-	return CheckAllMayastorVolumesAreHealthy()
+	return CheckAllMayastorVolumesAreHealthy(cp.ctx)
 }
 
 func (cp CPv1) CanDeleteMsv() bool {
@@ -335,16 +356,16 @@ func (cp CPv1) DeleteMsv(volName string) error {
 }
 
 func (cp CPv1) GetMsvTargetNode(volName string) (string, error) {
-	return GetMayastorVolumeTargetNode(volName)
+	return GetMayastorVolumeTargetNode(cp.ctx, volName)
 }
 
 func (cp CPv1) GetMsvSize(uuid string) (int64, error) {
-	return getMsvSize(uuid)
+	return getMsvSize(cp.ctx, uuid)
 }
 
-func getMsvSize(volName string) (int64, error) {
+func getMsvSize(ctx context.Context, volName string) (int64, error) {
 	var size int64
-	msv, err := getMayastorCpVolume(volName)
+	msv, err := getMayastorCpVolume(ctx, volName)
 	if err == nil {
 		size = msv.State.Size
 	}
@@ -353,7 +374,7 @@ func getMsvSize(volName string) (int64, error) {
 
 func (cp CPv1) GetMsvDeviceUri(volName string) (string, error) {
 	var deviceUri string
-	msv, err := getMayastorCpVolume(volName)
+	msv, err := getMayastorCpVolume(cp.ctx, volName)
 	if err == nil {
 		deviceUri = msv.State.Target.DeviceUri
 	}
@@ -365,8 +386,9 @@ func (cp CPv1) SetVolumeMaxSnapshotCount(uuid string, maxSnapshotCount int32) er
 
 	var err error
 	var jsonInput []byte
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "set", "volume", uuid, "max-snapshots", strconv.Itoa(int(maxSnapshotCount)))
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "set", "volume", uuid, "max-snapshots", strconv.Itoa(int(maxSnapshotCount)))
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return err
@@ -376,7 +398,7 @@ func (cp CPv1) SetVolumeMaxSnapshotCount(uuid string, maxSnapshotCount int32) er
 
 func (cp CPv1) GetMsvMaxSnapshotCount(uuid string) (int32, error) {
 	var maxSnapshotCount int32
-	msv, err := getMayastorCpVolume(uuid)
+	msv, err := getMayastorCpVolume(cp.ctx, uuid)
 	if err == nil {
 		maxSnapshotCount = msv.Spec.MaxSnapshots
 	}
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
+)
+
+// errNotSupported returns the error of an operation the kubectl plugin does not support,
+// it is classified as cperrors.ErrUnsupported so that callers can skip the operation
+func errNotSupported(operation string, format string, args ...interface{}) error {
+	return cperrors.Wrap(fmt.Errorf("%s is not supported by the kubectl plugin, %s",
+		operation, fmt.Sprintf(format, args...)), cperrors.ErrUnsupported)
+}
+
+// ListNexuses returns the nexuses on the node, or on all nodes if node is "",
+// the kubectl plugin lists nexuses as the targets of volumes
+func (cp CPv1) ListNexuses(node string) ([]common.MayastorNexus, error) {
+	msvs, err := listMayastorCpVolumes(cp.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListNexuses: %w", err)
+	}
+	var msns []common.MayastorNexus
+	for _, msv := range msvs {
+		target := msv.State.Target
+		if target.Uuid != "" && (node == "" || target.Node == node) {
+			msns = append(msns, target)
+		}
+	}
+	return msns, nil
+}
+
+func (cp CPv1) GetNexus(uuid string) (*common.MayastorNexus, error) {
+	msns, err := cp.ListNexuses("")
+	if err != nil {
+		return nil, fmt.Errorf("GetNexus: %w", err)
+	}
+	for _, msn := range msns {
+		if msn.Uuid == uuid {
+			return &msn, nil
+		}
+	}
+	return nil, cperrors.Wrap(fmt.Errorf("GetNexus: nexus %s not found", uuid), cperrors.ErrNotFound)
+}
+
+func (cp CPv1) AddNexusChild(uuid string, childUri string) error {
+	return errNotSupported("adding a nexus child", "nexus %s child %s", uuid, childUri)
+}
+
+func (cp CPv1) RemoveNexusChild(uuid string, childUri string) error {
+	return errNotSupported("removing a nexus child", "nexus %s child %s", uuid, childUri)
+}
+
+func (cp CPv1) FaultNexusChild(uuid string, childUri string) error {
+	return errNotSupported("faulting a nexus child", "nexus %s child %s", uuid, childUri)
+}
+
+func (cp CPv1) ShareNexus(uuid string) (string, error) {
+	return "", errNotSupported("sharing a nexus", "nexus %s", uuid)
+}
+
+func (cp CPv1) UnshareNexus(uuid string) error {
+	return errNotSupported("unsharing a nexus", "nexus %s", uuid)
+}
@@ -1,11 +1,18 @@
 package v1
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/openebs/openebs-e2e/common/controlplane/cperrors"
 	"github.com/openebs/openebs-e2e/common/e2e_config"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func GetPluginPath() string {
@@ -53,10 +60,76 @@ func GetPluginVersion() (string, error) {
 	return string(awkOutput), nil
 }
 
+// defaultCallTimeout is the kubectl plugin run timeout if the configuration does not set a valid one
+const defaultCallTimeout = 120 * time.Second
+
+// defaultUpgradeTimeout is the kubectl plugin upgrade run timeout if the configuration does not set a valid one
+const defaultUpgradeTimeout = 1800 * time.Second
+
+// callTimeout returns the control plane call timeout of the configuration
+func callTimeout() time.Duration {
+	return e2e_config.ParseTimeout(e2e_config.GetConfig().ControlPlaneCallTimeout, defaultCallTimeout)
+}
+
+// upgradeTimeout returns the upgrade timeout of the configuration
+func upgradeTimeout() time.Duration {
+	return e2e_config.ParseTimeout(e2e_config.GetConfig().ControlPlaneUpgradeTimeout, defaultUpgradeTimeout)
+}
+
+// pluginCommand returns the command running the kubectl plugin, the plugin is killed
+// when ctx is done or the control plane call timeout of the configuration expires.
+// done must be called with the error of the command once it has completed, it releases
+// the timeout and if the plugin was killed returns an error describing the run,
+// the output of the plugin captured until it was killed is logged.
+func pluginCommand(ctx context.Context, name string, args ...string) (cmd *exec.Cmd, done func(error) error) {
+	return pluginCommandWithTimeout(ctx, callTimeout(), name, args...)
+}
+
+// pluginCommandWithTimeout is pluginCommand for runs of the plugin which are bounded
+// by timeout rather than the call timeout, eg: a drain which waits for the drain timeout
+func pluginCommandWithTimeout(ctx context.Context, timeout time.Duration, name string, args ...string) (cmd *exec.Cmd, done func(error) error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	cmd = exec.CommandContext(ctx, name, args...)
+	start := time.Now()
+	return cmd, func(err error) error {
+		defer cancel()
+		if err == nil || ctx.Err() == nil {
+			return err
+		}
+		err = fmt.Errorf("%s killed after %v, %w, %v", strings.Join(cmd.Args, " "), time.Since(start).Round(time.Millisecond), ctx.Err(), err)
+		logf.Log.Info("kubectl plugin killed", "error", err, "output", capturedOutput(cmd))
+		if errors.Is(err, context.DeadlineExceeded) {
+			return cperrors.Wrap(err, cperrors.ErrTimeout)
+		}
+		return err
+	}
+}
+
+// capturedOutput returns the output of the command captured in buffers, eg: by cmd.CombinedOutput
+func capturedOutput(cmd *exec.Cmd) string {
+	var output []string
+	if stdout, ok := cmd.Stdout.(*bytes.Buffer); ok && stdout.Len() != 0 {
+		output = append(output, stdout.String())
+	}
+	if stderr, ok := cmd.Stderr.(*bytes.Buffer); ok && stderr != cmd.Stdout && stderr.Len() != 0 {
+		output = append(output, stderr.String())
+	}
+	return strings.Join(output, "\n")
+}
+
 func CheckPluginError(jsonInput []byte, err error) error {
 	// json error output trumps, error input
 	if strings.Contains(string(jsonInput), ErrOutput) {
-		return fmt.Errorf("%s", string(jsonInput))
+		return pluginError(string(jsonInput))
 	}
 	return err
 }
+
+// pluginError returns the error output of the plugin as an error,
+// classified by the kind of the REST API error reported, see cperrors
+func pluginError(output string) error {
+	return cperrors.FromPluginOutput(fmt.Errorf("%s", output), output)
+}
@@ -3,7 +3,6 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
 
 	"github.com/openebs/openebs-e2e/common"
 )
@@ -13,8 +12,9 @@ func (cp CPv1) GetSnapshots() ([]common.SnapshotSchema, error) {
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots")
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots")
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -24,7 +24,7 @@ func (cp CPv1) GetSnapshots() ([]common.SnapshotSchema, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		// logf.Log.Info("Failed to unmarshal (get snapshots)", "string", string(jsonInput))
-		return []common.SnapshotSchema{}, fmt.Errorf("%s", errMsg)
+		return []common.SnapshotSchema{}, pluginError(errMsg)
 	}
 	return response, nil
 }
@@ -35,8 +35,9 @@ func (cp CPv1) GetSnapshot(snapshotId string) (common.SnapshotSchema, error) {
 	var jsonInput []byte
 	var err error
 	var response []common.SnapshotSchema
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots", "--snapshot", snapshotId)
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots", "--snapshot", snapshotId)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return common.SnapshotSchema{}, err
@@ -46,7 +47,7 @@ func (cp CPv1) GetSnapshot(snapshotId string) (common.SnapshotSchema, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		// logf.Log.Info("Failed to unmarshal (get snapshot by snapshot id)", "string", string(jsonInput))
-		return common.SnapshotSchema{}, fmt.Errorf("%s", errMsg)
+		return common.SnapshotSchema{}, pluginError(errMsg)
 	}
 	if len(response) == 0 {
 		return common.SnapshotSchema{}, fmt.Errorf("snapshot not found, snapshot: %s", snapshotId)
@@ -62,8 +63,9 @@ func (cp CPv1) GetVolumeSnapshot(volUuid string, snapshotId string) (common.Snap
 	var jsonInput []byte
 	var err error
 	var response []common.SnapshotSchema
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots", "--volume", volUuid, "--snapshot", snapshotId)
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots", "--volume", volUuid, "--snapshot", snapshotId)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return common.SnapshotSchema{}, err
@@ -72,7 +74,7 @@ func (cp CPv1) GetVolumeSnapshot(volUuid string, snapshotId string) (common.Snap
 	if err != nil {
 		errMsg := string(jsonInput)
 		// logf.Log.Info("Failed to unmarshal (get snapshot by snapshot id for volume)", "string", string(jsonInput))
-		return common.SnapshotSchema{}, fmt.Errorf("%s", errMsg)
+		return common.SnapshotSchema{}, pluginError(errMsg)
 	}
 	if len(response) == 0 {
 		return common.SnapshotSchema{}, fmt.Errorf("snapshot not found, snapshot: %s, volume: %s", snapshotId, volUuid)
@@ -87,8 +89,9 @@ func (cp CPv1) GetVolumeSnapshots(volUuid string) ([]common.SnapshotSchema, erro
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots", "--volume", volUuid)
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshots", "--volume", volUuid)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -98,7 +101,7 @@ func (cp CPv1) GetVolumeSnapshots(volUuid string) ([]common.SnapshotSchema, erro
 	if err != nil {
 		errMsg := string(jsonInput)
 		// logf.Log.Info("Failed to unmarshal (get snapshots for volume)", "string", string(jsonInput))
-		return []common.SnapshotSchema{}, fmt.Errorf("%s", errMsg)
+		return []common.SnapshotSchema{}, pluginError(errMsg)
 	}
 	return response, nil
 }
@@ -108,8 +111,9 @@ func (cp CPv1) GetVolumeSnapshotTopology() ([]common.SnapshotSchema, error) {
 
 	var jsonInput []byte
 	var err error
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshot-topology")
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshot-topology")
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return nil, err
@@ -119,7 +123,7 @@ func (cp CPv1) GetVolumeSnapshotTopology() ([]common.SnapshotSchema, error) {
 	if err != nil {
 		errMsg := string(jsonInput)
 		// logf.Log.Info("Failed to unmarshal (get snapshots)", "string", string(jsonInput))
-		return []common.SnapshotSchema{}, fmt.Errorf("%s", errMsg)
+		return []common.SnapshotSchema{}, pluginError(errMsg)
 	}
 	return response, nil
 }
@@ -130,8 +134,9 @@ func (cp CPv1) GetPerSnapshotVolumeSnapshotTopology(snapshotId string) (common.S
 	var jsonInput []byte
 	var err error
 	var response []common.SnapshotSchema
-	cmd := exec.Command(pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshot-topology", "--snapshot", snapshotId)
+	cmd, done := pluginCommand(cp.ctx, pluginpath, "-n", common.NSMayastor(), "-ojson", "get", "volume-snapshot-topology", "--snapshot", snapshotId)
 	jsonInput, err = cmd.CombinedOutput()
+	err = done(err)
 	err = CheckPluginError(jsonInput, err)
 	if err != nil {
 		return common.SnapshotSchema{}, err
@@ -141,7 +146,7 @@ func (cp CPv1) GetPerSnapshotVolumeSnapshotTopology(snapshotId string) (common.S
 	if err != nil {
 		errMsg := string(jsonInput)
 		// logf.Log.Info("Failed to unmarshal (get snapshot by snapshot id)", "string", string(jsonInput))
-		return common.SnapshotSchema{}, fmt.Errorf("%s", errMsg)
+		return common.SnapshotSchema{}, pluginError(errMsg)
 	}
 	if len(response) == 0 {
 		return common.SnapshotSchema{}, fmt.Errorf("snapshot not found, snapshot: %s", snapshotId)
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/openebs/openebs-e2e/common"
@@ -47,7 +46,7 @@ func (cp CPv1) Upgrade(isUpgradingToUnstableBranch, isPartialRebuildDisableNeede
 	}
 
 	// Create the command
-	cmd := exec.Command(kubectlPlugin, cmdArgs...)
+	cmd, done := pluginCommandWithTimeout(cp.ctx, upgradeTimeout(), kubectlPlugin, cmdArgs...)
 
 	// Print the command that will be executed
 	logf.Log.Info("Executing", "command", strings.Join(cmd.Args, " "))
@@ -57,10 +56,10 @@ func (cp CPv1) Upgrade(isUpgradingToUnstableBranch, isPartialRebuildDisableNeede
 	cmd.Stderr = &stderr
 
 	// Run the command
-	err := cmd.Run()
+	err := done(cmd.Run())
 	if err != nil {
 		logf.Log.Info(stderr.String())
-		return stderr.String(), fmt.Errorf("plugin failed to upgrade, err:%v", err)
+		return stderr.String(), fmt.Errorf("plugin failed to upgrade, err:%w", err)
 	}
 	return stderr.String(), nil
 }
@@ -93,15 +92,16 @@ func (cp CPv1) UpgradeWithSkipDataPlaneRestart(isUpgradingToUnstableBranch, isPa
 	}
 
 	// Create the command
-	cmd := exec.Command(kubectlPlugin, cmdArgs...)
+	cmd, done := pluginCommandWithTimeout(cp.ctx, upgradeTimeout(), kubectlPlugin, cmdArgs...)
 
 	// Print the command that will be executed
 	logf.Log.Info("Executing", "command", strings.Join(cmd.Args, " "))
 
 	_, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return fmt.Errorf("plugin failed to upgrade when skip data plane restart flag is passsed , error %v", err)
+		return fmt.Errorf("plugin failed to upgrade when skip data plane restart flag is passsed , error %w", err)
 	}
 	return nil
 }
@@ -132,15 +132,16 @@ func (cp CPv1) UpgradeWithSkipSingleReplicaValidation(isUpgradingToUnstableBranc
 	}
 
 	// Create the command
-	cmd := exec.Command(kubectlPlugin, cmdArgs...)
+	cmd, done := pluginCommandWithTimeout(cp.ctx, upgradeTimeout(), kubectlPlugin, cmdArgs...)
 
 	// Print the command that will be executed
 	logf.Log.Info("Executing", "command", strings.Join(cmd.Args, " "))
 
 	_, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return fmt.Errorf("plugin failed to upgrade when skip single replica volume flag is passsed , error %v", err)
+		return fmt.Errorf("plugin failed to upgrade when skip single replica volume flag is passsed , error %w", err)
 	}
 	return nil
 }
@@ -170,15 +171,16 @@ func (cp CPv1) UpgradeWithSkipReplicaRebuild(isUpgradingToUnstableBranch, isPart
 	}
 
 	// Create the command
-	cmd := exec.Command(kubectlPlugin, cmdArgs...)
+	cmd, done := pluginCommandWithTimeout(cp.ctx, upgradeTimeout(), kubectlPlugin, cmdArgs...)
 
 	// Print the command that will be executed
 	logf.Log.Info("Executing", "command", strings.Join(cmd.Args, " "))
 
 	_, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return fmt.Errorf("plugin failed to upgrade with --skip-rebuild-replica flag , error %v", err)
+		return fmt.Errorf("plugin failed to upgrade with --skip-rebuild-replica flag , error %w", err)
 	}
 	return nil
 }
@@ -208,15 +210,16 @@ func (cp CPv1) UpgradeWithSkipCordonNodeValidation(isUpgradingToUnstableBranch,
 	}
 
 	// Create the command
-	cmd := exec.Command(kubectlPlugin, cmdArgs...)
+	cmd, done := pluginCommandWithTimeout(cp.ctx, upgradeTimeout(), kubectlPlugin, cmdArgs...)
 
 	// Print the command that will be executed
 	logf.Log.Info("Executing", "command", strings.Join(cmd.Args, " "))
 
 	_, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return fmt.Errorf("plugin failed to upgrade with skip cordon node validation flag, error %v", err)
+		return fmt.Errorf("plugin failed to upgrade with skip cordon node validation flag, error %w", err)
 	}
 	return nil
 }
@@ -227,11 +230,12 @@ func (cp CPv1) UpgradeWithSkipCordonNodeValidation(isUpgradingToUnstableBranch,
 func (cp CPv1) GetUpgradeStatus() (string, error) {
 	kubectlPlugin := GetPluginPath()
 
-	cmd := exec.Command(kubectlPlugin, "-n", common.NSMayastor(), "get", "upgrade-status")
+	cmd, done := pluginCommand(cp.ctx, kubectlPlugin, "-n", common.NSMayastor(), "get", "upgrade-status")
 	upgradeStatusInfo, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return "", fmt.Errorf("plugin failed to get upgrade status, error %v", err)
+		return "", fmt.Errorf("plugin failed to get upgrade status, error %w", err)
 	}
 
 	out := strings.Split(string(upgradeStatusInfo), "\n")
@@ -251,11 +255,12 @@ func (cp CPv1) GetUpgradeStatus() (string, error) {
 func (cp CPv1) GetToUpgradeVersion() (string, error) {
 	kubectlPlugin := GetPluginPath()
 
-	cmd := exec.Command(kubectlPlugin, "-n", common.NSMayastor(), "get", "upgrade-status")
+	cmd, done := pluginCommand(cp.ctx, kubectlPlugin, "-n", common.NSMayastor(), "get", "upgrade-status")
 	toUpgradeVersionInfo, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return "", fmt.Errorf("plugin failed to get `to upgrade` version, error %v", err)
+		return "", fmt.Errorf("plugin failed to get `to upgrade` version, error %w", err)
 	}
 
 	out := strings.Split(string(toUpgradeVersionInfo), "\n")
@@ -272,12 +277,13 @@ func (cp CPv1) GetToUpgradeVersion() (string, error) {
 func (cp CPv1) DeleteUpgrade() error {
 	kubectlPlugin := GetPluginPath()
 
-	cmd := exec.Command(kubectlPlugin, "-n", common.NSMayastor(), "delete", "upgrade")
+	cmd, done := pluginCommand(cp.ctx, kubectlPlugin, "-n", common.NSMayastor(), "delete", "upgrade")
 
 	_, err := cmd.Output()
+	err = done(err)
 
 	if err != nil {
-		return fmt.Errorf("plugin failed to delete resources created by the upgrade process, error %v", err)
+		return fmt.Errorf("plugin failed to delete resources created by the upgrade process, error %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"github.com/openebs/openebs-e2e/common/controlplane/watch"
+)
+
+// WatchMsv watches the volume by reading it every e2e_config WatchResyncInterval,
+// the kubectl plugin cannot register watches with the control plane
+func (cp CPv1) WatchMsv(uuid string) (*watch.MsvWatch, error) {
+	w := watch.NewMsvWatch(uuid, cp, watch.ResyncInterval())
+	w.Start()
+	return w, nil
+}
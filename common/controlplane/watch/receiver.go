@@ -0,0 +1,100 @@
+package watch
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const callbackPath = "/watch/"
+
+// Receiver is an HTTP server in the test process serving the callback URLs
+// registered with the control plane, a call of a callback URL notifies its watch
+type Receiver struct {
+	listener net.Listener
+	server   *http.Server
+	baseUrl  string
+	mutex    sync.Mutex
+	watches  map[string]*MsvWatch
+	next     int
+}
+
+// NewReceiver starts a receiver listening on listenAddr, eg: ":0",
+// advertiseHost is the address the control plane uses to reach the receiver,
+// the port is that of the listener
+func NewReceiver(listenAddr string, advertiseHost string) (*Receiver, error) {
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s for watch callbacks, error: %v", listenAddr, err)
+	}
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	r := &Receiver{
+		listener: listener,
+		baseUrl:  "http://" + net.JoinHostPort(advertiseHost, port) + callbackPath,
+		watches:  make(map[string]*MsvWatch),
+	}
+	r.server = &http.Server{Handler: r}
+	go func() {
+		if err := r.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logf.Log.Info("watch receiver stopped", "error", err)
+		}
+	}()
+	logf.Log.Info("Watch receiver", "url", r.baseUrl)
+	return r, nil
+}
+
+// Register returns the callback URL of the watch, the registration is removed when the watch is stopped
+func (r *Receiver) Register(w *MsvWatch) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.next++
+	id := fmt.Sprintf("%s/%d", w.Uuid(), r.next)
+	r.watches[id] = w
+	w.OnStop(func() error {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		delete(r.watches, id)
+		return nil
+	})
+	return r.baseUrl + id
+}
+
+// ServeHTTP notifies the watch of the callback URL, the control plane
+// may call the URL with any method and the body is not used
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	id := strings.TrimPrefix(req.URL.Path, callbackPath)
+	r.mutex.Lock()
+	watch, ok := r.watches[id]
+	r.mutex.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	watch.Notify()
+	w.WriteHeader(http.StatusOK)
+}
+
+// Close stops the receiver
+func (r *Receiver) Close() error {
+	return r.server.Close()
+}
+
+// OutboundIP returns the IP address of the test host used to reach host,
+// no traffic is sent
+func OutboundIP(host string) (string, error) {
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	ip, _, err := net.SplitHostPort(conn.LocalAddr().String())
+	return ip, err
+}
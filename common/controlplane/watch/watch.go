@@ -0,0 +1,214 @@
+package watch
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/e2e_config"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultResync is the interval at which a watched volume is re-read without
+// a notification, the control plane only notifies changes of the volume spec.
+// The interval is configured by e2e_config WatchResyncInterval.
+const DefaultResync = time.Second
+
+// ResyncInterval returns the configured interval at which watched volumes are re-read
+func ResyncInterval() time.Duration {
+	return e2e_config.ParseTimeout(e2e_config.GetConfig().WatchResyncInterval, DefaultResync)
+}
+
+// Change is a change of the spec or state of a watched volume
+type Change struct {
+	Msv     common.MayastorVolume
+	Deleted bool
+	Time    time.Time
+}
+
+// MsvSource reads volumes, it is implemented by the control plane backends
+type MsvSource interface {
+	GetMSV(uuid string) (*common.MayastorVolume, error)
+	IsMsvDeleted(uuid string) bool
+}
+
+// MsvWatch delivers the changes of a volume on a channel. The volume is re-read
+// when the watch is notified and every resync interval, a change is delivered
+// when the volume differs from the last delivered one. The channel is closed
+// when the volume is deleted or the watch is stopped.
+// Changes of the volume state are not notified, they are sampled every resync
+// interval, so a transition which is undone between two samples is missed,
+// eg: Online, Degraded, Online within an interval is observed as Online.
+type MsvWatch struct {
+	uuid     string
+	source   MsvSource
+	resync   time.Duration
+	trigger  chan struct{}
+	changes  chan Change
+	stop     chan struct{}
+	done     chan struct{}
+	mutex    sync.Mutex
+	onStop   []func() error
+	started  bool
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// NewMsvWatch makes a watch of the volume, the watch starts delivering changes once Start is called
+func NewMsvWatch(uuid string, source MsvSource, resync time.Duration) *MsvWatch {
+	if resync <= 0 {
+		resync = DefaultResync
+	}
+	return &MsvWatch{
+		uuid:    uuid,
+		source:  source,
+		resync:  resync,
+		trigger: make(chan struct{}, 1),
+		changes: make(chan Change, 64),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Uuid returns the uuid of the watched volume
+func (w *MsvWatch) Uuid() string {
+	return w.uuid
+}
+
+// Changes returns the channel on which the changes of the volume are delivered
+func (w *MsvWatch) Changes() <-chan Change {
+	return w.changes
+}
+
+// OnStop registers a function called when the watch is stopped, eg: to delete the watch from the control plane
+func (w *MsvWatch) OnStop(fn func() error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.onStop = append(w.onStop, fn)
+}
+
+// Notify makes the watch re-read the volume, notifications received
+// while the volume is being read are coalesced
+func (w *MsvWatch) Notify() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start starts reading the volume and delivering its changes
+func (w *MsvWatch) Start() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.started {
+		w.started = true
+		go w.run()
+	}
+}
+
+// Stop stops the watch and calls the functions registered with OnStop,
+// it is safe to call Stop more than once
+func (w *MsvWatch) Stop() error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		w.mutex.Lock()
+		// a stopped watch cannot be started
+		started := w.started
+		w.started = true
+		w.mutex.Unlock()
+		if started {
+			<-w.done
+		} else {
+			close(w.changes)
+		}
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		for _, fn := range w.onStop {
+			if err := fn(); err != nil && w.stopErr == nil {
+				w.stopErr = err
+			}
+		}
+	})
+	return w.stopErr
+}
+
+func (w *MsvWatch) run() {
+	defer close(w.done)
+	defer close(w.changes)
+	ticker := time.NewTicker(w.resync)
+	defer ticker.Stop()
+	var last *common.MayastorVolume
+	for {
+		msv, err := w.source.GetMSV(w.uuid)
+		if err != nil && w.source.IsMsvDeleted(w.uuid) {
+			w.deliver(Change{Deleted: true, Time: time.Now()})
+			return
+		}
+		if err != nil {
+			logf.Log.Info("MsvWatch: failed to read volume", "uuid", w.uuid, "error", err)
+		} else if msv != nil && (last == nil || !reflect.DeepEqual(*last, *msv)) {
+			last = msv
+			if !w.deliver(Change{Msv: *msv, Time: time.Now()}) {
+				return
+			}
+		}
+		select {
+		case <-w.stop:
+			return
+		case <-w.trigger:
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver sends the change, returning false if the watch was stopped
+func (w *MsvWatch) deliver(change Change) bool {
+	select {
+	case w.changes <- change:
+		return true
+	case <-w.stop:
+		return false
+	}
+}
+
+// WaitForStateSequence reads the changes of the volume until its status has gone
+// through exactly the sequence of states, eg: Online, Degraded, Online.
+// Repeated statuses are collapsed, the statuses observed are returned.
+// An error is returned if a status out of sequence is observed,
+// the volume is deleted or the timeout expires.
+// Statuses held for less than the resync interval may not be observed.
+func (w *MsvWatch) WaitForStateSequence(timeout time.Duration, states ...string) ([]string, error) {
+	var observed []string
+	if len(states) == 0 {
+		return observed, nil
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case change, ok := <-w.changes:
+			if !ok {
+				return observed, fmt.Errorf("watch of volume %s stopped, observed states %v, expected %v", w.uuid, observed, states)
+			}
+			if change.Deleted {
+				return observed, fmt.Errorf("volume %s deleted, observed states %v, expected %v", w.uuid, observed, states)
+			}
+			status := change.Msv.State.Status
+			if len(observed) != 0 && observed[len(observed)-1] == status {
+				continue
+			}
+			observed = append(observed, status)
+			if status != states[len(observed)-1] {
+				return observed, fmt.Errorf("volume %s observed states %v, expected %v", w.uuid, observed, states)
+			}
+			if len(observed) == len(states) {
+				return observed, nil
+			}
+		case <-deadline.C:
+			return observed, fmt.Errorf("timed out waiting for volume %s states, observed %v, expected %v", w.uuid, observed, states)
+		}
+	}
+}
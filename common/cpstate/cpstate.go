@@ -0,0 +1,167 @@
+// Package cpstate captures the state of the control plane and the io-engines
+// as a single JSON document, and reports the differences between two captures,
+// eg: the state before and after a test case, for post-mortem analysis.
+package cpstate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane"
+	"github.com/openebs/openebs-e2e/common/mayastorclient"
+)
+
+// Replica is a replica as reported by the io-engine on a node
+type Replica struct {
+	Uuid  string `json:"uuid"`
+	Node  string `json:"node"`
+	Pool  string `json:"pool"`
+	Size  uint64 `json:"size"`
+	Thin  bool   `json:"thin"`
+	Share string `json:"share"`
+	Uri   string `json:"uri"`
+}
+
+// State is a capture of the state of the control plane and the io-engines,
+// a capture is best effort, the failure to retrieve a kind of object is
+// recorded in Errors and the remaining kinds are captured
+type State struct {
+	Label     string                  `json:"label"`
+	Time      time.Time               `json:"time"`
+	Nodes     []common.MayastorNode   `json:"nodes"`
+	Pools     []common.MayastorPool   `json:"pools"`
+	Volumes   []common.MayastorVolume `json:"volumes"`
+	Nexuses   []common.MayastorNexus  `json:"nexuses"`
+	Replicas  []Replica               `json:"replicas"`
+	Snapshots []common.SnapshotSchema `json:"snapshots"`
+	// Errors maps the kind of object to the error retrieving it
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func (s *State) recordError(kind string, err error) {
+	if err == nil {
+		return
+	}
+	if s.Errors == nil {
+		s.Errors = map[string]string{}
+	}
+	s.Errors[kind] = err.Error()
+}
+
+// Capture captures the state of the control plane and the io-engines, the
+// replicas are retrieved from the io-engines using the mayastorclient package,
+// which must have been initialised, the other objects from the control plane
+func Capture(ctx context.Context, label string) State {
+	cp := controlplane.WithContext(ctx)
+	state := State{
+		Label: label,
+		Time:  time.Now().UTC(),
+	}
+	var err error
+
+	state.Nodes, err = cp.ListMsns()
+	state.recordError(KindNode, err)
+	sort.Slice(state.Nodes, func(i, j int) bool { return state.Nodes[i].Name < state.Nodes[j].Name })
+
+	state.Pools, err = cp.ListMsPools()
+	state.recordError(KindPool, err)
+	sort.Slice(state.Pools, func(i, j int) bool { return state.Pools[i].Name < state.Pools[j].Name })
+
+	state.Volumes, err = cp.ListMsvs()
+	state.recordError(KindVolume, err)
+	sort.Slice(state.Volumes, func(i, j int) bool { return state.Volumes[i].Spec.Uuid < state.Volumes[j].Spec.Uuid })
+
+	state.Nexuses, err = cp.ListNexuses("")
+	state.recordError(KindNexus, err)
+	sort.Slice(state.Nexuses, func(i, j int) bool { return state.Nexuses[i].Uuid < state.Nexuses[j].Uuid })
+
+	state.Snapshots, err = cp.GetSnapshots()
+	state.recordError(KindSnapshot, err)
+	sort.Slice(state.Snapshots, func(i, j int) bool {
+		return state.Snapshots[i].Definition.Spec.UUID < state.Snapshots[j].Definition.Spec.UUID
+	})
+
+	state.Replicas, err = captureReplicas(ctx, state.Nodes)
+	state.recordError(KindReplica, err)
+
+	return state
+}
+
+// captureReplicas lists the replicas on the io-engine of each node
+func captureReplicas(ctx context.Context, nodes []common.MayastorNode) ([]Replica, error) {
+	grpc, err := mayastorclient.WithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var replicas []Replica
+	var errs common.ErrorAccumulator
+	for _, node := range nodes {
+		address := NodeAddress(node)
+		if address == "" {
+			continue
+		}
+		nodeReplicas, err := grpc.ListReplicas([]string{address})
+		if err != nil {
+			errs.Accumulate(fmt.Errorf("node %s: %w", node.Name, err))
+			continue
+		}
+		for _, replica := range nodeReplicas {
+			replicas = append(replicas, Replica{
+				Uuid:  replica.GetUuid(),
+				Node:  node.Name,
+				Pool:  replica.GetPool(),
+				Size:  replica.GetSize(),
+				Thin:  replica.GetThin(),
+				Share: replica.GetShareString(),
+				Uri:   replica.GetUri(),
+			})
+		}
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].Uuid < replicas[j].Uuid })
+	return replicas, errs.GetError()
+}
+
+// NodeAddress returns the address of the io-engine gRPC endpoint of the node,
+// the address used by the mayastorclient package
+func NodeAddress(node common.MayastorNode) string {
+	endpoint := node.State.GrpcEndpoint
+	if endpoint == "" {
+		endpoint = node.Spec.GrpcEndpoint
+	}
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return endpoint
+}
+
+// WriteFile writes the state to the file as JSON
+func (s State) WriteFile(path string) error {
+	return writeJSON(path, s)
+}
+
+// ReadFile reads a state written by WriteFile
+func ReadFile(path string) (State, error) {
+	var state State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to decode %s, %w", path, err)
+	}
+	return state, nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,210 @@
+package cpstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The kinds of object in a State
+const (
+	KindNode     = "node"
+	KindPool     = "pool"
+	KindVolume   = "volume"
+	KindNexus    = "nexus"
+	KindReplica  = "replica"
+	KindSnapshot = "snapshot"
+)
+
+var kinds = []string{KindNode, KindPool, KindVolume, KindNexus, KindReplica, KindSnapshot}
+
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Changed ChangeType = "changed"
+)
+
+// FieldChange is a change of a field of an object, Path is the JSON path of
+// the field, eg: state.target.children[1].state
+type FieldChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Change is an object added, removed or changed between two captures
+type Change struct {
+	Kind   string        `json:"kind"`
+	Id     string        `json:"id"`
+	Type   ChangeType    `json:"type"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// StateDiff is the difference between two captures
+type StateDiff struct {
+	Before  string   `json:"before"`
+	After   string   `json:"after"`
+	Changes []Change `json:"changes"`
+	// Skipped lists the kinds of object which were not compared because
+	// either capture failed to retrieve them
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Empty returns true if no changes were found
+func (d StateDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String returns the changes one per line, eg:
+//
+//	volume 1a2b...: changed state.status Online -> Degraded
+func (d StateDiff) String() string {
+	var sb strings.Builder
+	for _, change := range d.Changes {
+		if change.Type != Changed {
+			sb.WriteString(fmt.Sprintf("%s %s: %s\n", change.Kind, change.Id, change.Type))
+			continue
+		}
+		for _, field := range change.Fields {
+			sb.WriteString(fmt.Sprintf("%s %s: changed %s %v -> %v\n",
+				change.Kind, change.Id, field.Path, field.Before, field.After))
+		}
+	}
+	for _, kind := range d.Skipped {
+		sb.WriteString(fmt.Sprintf("%s: not compared, capture failed\n", kind))
+	}
+	return sb.String()
+}
+
+// WriteFile writes the diff to the file as JSON
+func (d StateDiff) WriteFile(path string) error {
+	return writeJSON(path, d)
+}
+
+// Diff returns the objects added, removed or changed between the captures
+func Diff(before State, after State) StateDiff {
+	diff := StateDiff{
+		Before: before.Label,
+		After:  after.Label,
+	}
+	for _, kind := range kinds {
+		_, beforeErr := before.Errors[kind]
+		_, afterErr := after.Errors[kind]
+		if beforeErr || afterErr {
+			diff.Skipped = append(diff.Skipped, kind)
+			continue
+		}
+		diff.Changes = append(diff.Changes, diffObjects(kind, before.objects(kind), after.objects(kind))...)
+	}
+	return diff
+}
+
+func diffObjects(kind string, before map[string]interface{}, after map[string]interface{}) []Change {
+	var changes []Change
+	for id, beforeObj := range before {
+		afterObj, ok := after[id]
+		if !ok {
+			changes = append(changes, Change{Kind: kind, Id: id, Type: Removed})
+			continue
+		}
+		if fields := diffFields(flatten(beforeObj), flatten(afterObj)); len(fields) != 0 {
+			changes = append(changes, Change{Kind: kind, Id: id, Type: Changed, Fields: fields})
+		}
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			changes = append(changes, Change{Kind: kind, Id: id, Type: Added})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Id < changes[j].Id })
+	return changes
+}
+
+func diffFields(before map[string]interface{}, after map[string]interface{}) []FieldChange {
+	var fields []FieldChange
+	for path, beforeVal := range before {
+		afterVal := after[path]
+		if !reflect.DeepEqual(beforeVal, afterVal) {
+			fields = append(fields, FieldChange{Path: path, Before: beforeVal, After: afterVal})
+		}
+	}
+	for path, afterVal := range after {
+		if _, ok := before[path]; !ok {
+			fields = append(fields, FieldChange{Path: path, After: afterVal})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+// objects returns the objects of the kind by id
+func (s State) objects(kind string) map[string]interface{} {
+	objs := map[string]interface{}{}
+	switch kind {
+	case KindNode:
+		for _, obj := range s.Nodes {
+			objs[obj.Name] = obj
+		}
+	case KindPool:
+		for _, obj := range s.Pools {
+			objs[obj.Name] = obj
+		}
+	case KindVolume:
+		for _, obj := range s.Volumes {
+			objs[obj.Spec.Uuid] = obj
+		}
+	case KindNexus:
+		for _, obj := range s.Nexuses {
+			objs[obj.Uuid] = obj
+		}
+	case KindReplica:
+		for _, obj := range s.Replicas {
+			objs[obj.Uuid] = obj
+		}
+	case KindSnapshot:
+		for _, obj := range s.Snapshots {
+			objs[obj.Definition.Spec.UUID] = obj
+		}
+	}
+	return objs
+}
+
+// flatten returns the leaf values of the JSON encoding of the object by path
+func flatten(obj interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		fields[""] = err.Error()
+		return fields
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		fields[""] = err.Error()
+		return fields
+	}
+	flattenValue("", value, fields)
+	return fields
+}
+
+func flattenValue(path string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if path == "" {
+				flattenValue(key, child, fields)
+			} else {
+				flattenValue(path+"."+key, child, fields)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	default:
+		fields[path] = v
+	}
+}
@@ -0,0 +1,126 @@
+package custom_resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane"
+	"github.com/openebs/openebs-e2e/common/e2e_config"
+
+	crtypes "github.com/openebs/openebs-e2e/common/custom_resources/types"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// loopMajor is the major device number of loop devices
+const loopMajor = 7
+
+// PoolDiskFilter selects the block devices of a node eligible as pool disks.
+// Eligible devices are available whole disks of at least MinSizeBytes,
+// without a partition table or filesystem.
+type PoolDiskFilter struct {
+	MinSizeBytes       int64
+	ExcludeLoopDevices bool
+	// MaxDisks limits the number of disks selected, 0 selects all eligible disks
+	MaxDisks int
+}
+
+// ConfiguredPoolDiskFilter returns the filter defined by the configuration
+func ConfiguredPoolDiskFilter() (PoolDiskFilter, error) {
+	selection := e2e_config.GetConfig().PoolDiskSelection
+	minSize, err := resource.ParseQuantity(selection.MinSize)
+	if err != nil {
+		return PoolDiskFilter{}, fmt.Errorf("invalid pool disk minimum size %s, error: %v", selection.MinSize, err)
+	}
+	return PoolDiskFilter{
+		MinSizeBytes:       minSize.Value(),
+		ExcludeLoopDevices: selection.ExcludeLoopDevices,
+		MaxDisks:           selection.MaxDisksPerNode,
+	}, nil
+}
+
+// poolDiskName returns the stable by-id name of the device if it has one
+func poolDiskName(device common.BlockDevice) string {
+	for _, link := range device.Devlinks {
+		if strings.HasPrefix(link, "/dev/disk/by-id/") {
+			return link
+		}
+	}
+	return device.Devname
+}
+
+// SelectPoolDisks returns the names of the eligible disks amongst the block devices of a node,
+// largest first. Devices should be listed with all set, so that disks with partitions are excluded.
+func SelectPoolDisks(devices []common.BlockDevice, filter PoolDiskFilter) []string {
+	partitioned := make(map[string]bool)
+	for _, device := range devices {
+		if device.Partition.Parent != "" {
+			partitioned[device.Partition.Parent] = true
+		}
+	}
+	var eligible []common.BlockDevice
+	for _, device := range devices {
+		switch {
+		case !device.Available || device.Devtype != "disk":
+		case device.Partition.Name != "" || partitioned[device.Devname]:
+		case device.Filesystem.Fstype != "" || device.Filesystem.Mountpoint != "":
+		case filter.ExcludeLoopDevices && (device.Devmajor == loopMajor || strings.HasPrefix(device.Devname, "/dev/loop")):
+		case device.Size*512 < filter.MinSizeBytes:
+		default:
+			eligible = append(eligible, device)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return eligible[i].Size > eligible[j].Size
+	})
+	var disks []string
+	for _, device := range eligible {
+		if filter.MaxDisks != 0 && len(disks) == filter.MaxDisks {
+			break
+		}
+		disks = append(disks, poolDiskName(device))
+	}
+	return disks
+}
+
+// SelectNodePoolDisks returns the eligible pool disks of the node reported by the control plane
+func SelectNodePoolDisks(nodeName string, filter PoolDiskFilter) ([]string, error) {
+	devices, err := controlplane.ListNodeBlockDevices(nodeName, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list block devices of node %s, error: %v", nodeName, err)
+	}
+	return SelectPoolDisks(devices, filter), nil
+}
+
+// CreateMsPoolsOnEligibleDisks creates a pool on each eligible disk of the nodes,
+// the pools are named like the configured pools, pool-<n>-on-<node>.
+// No check is made on the status of pools
+func CreateMsPoolsOnEligibleDisks(nodeNames []string, filter PoolDiskFilter) ([]crtypes.DiskPool, error) {
+	var pools []crtypes.DiskPool
+	var errs common.ErrorAccumulator
+	for _, nodeName := range nodeNames {
+		disks, err := SelectNodePoolDisks(nodeName, filter)
+		if err != nil {
+			errs.Accumulate(err)
+			continue
+		}
+		if len(disks) == 0 {
+			errs.Accumulate(fmt.Errorf("no eligible pool disks on node %s", nodeName))
+			continue
+		}
+		for ix, disk := range disks {
+			poolName := fmt.Sprintf("pool-%d-on-%s", ix+1, nodeName)
+			pool, err := CreateMsPool(poolName, nodeName, []string{disk})
+			if err != nil {
+				errs.Accumulate(fmt.Errorf("failed to create pool on %s, disk: %s, error: %v", nodeName, disk, err))
+				continue
+			}
+			log.Log.Info("Created", "pool", pool)
+			pools = append(pools, pool)
+		}
+	}
+	return pools, errs.GetError()
+}
@@ -2,15 +2,19 @@ package e2e_agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/openebs/openebs-e2e/common"
 	"github.com/openebs/openebs-e2e/common/e2e_config"
 	"github.com/openebs/openebs-e2e/common/k8s_portforward"
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -18,95 +22,19 @@ import (
 // RestPort is the port on which e2e-agent is listening
 const RestPort = 10012
 
-// NodeList is the list of nodes to be passed to e2e-agent
-type NodeList struct {
-	Nodes            []string `json:"nodes"`
-	NetworkInterface string   `json:"networkInterface"`
-}
-
-type CmdList struct {
-	Cmd string `json:"cmd"`
-}
-
-type Device struct {
-	Device     string `json:"device"`
-	Table      string `json:"table"`
-	DevicePath string `json:"devicePath"`
-	Uuid       string `json:"uuid"`
-	FsType     string `json:"fsType"`
-}
-
-type ControlledDevice struct {
-	Device string `json:"device"`
-	State  string `json:"state"`
-}
-
-type DiskPool struct {
-	Disk string `json:"disk"`
-}
-
-type Product struct {
-	Product string `json:"product"`
-	Pid     string `json:"pid"`
-}
-
-type Nvme struct {
-	TargetIp string `json:"targetIp"`
-	Nqn      string `json:"nqn"`
-	HostNqn  string `json:"hostNqn"`
-	HostId   string `json:"hostId"`
-}
-
-type Disk struct {
-	Device         string `json:"device"`
-	SeekParam      string `json:"seekParam"`
-	BlockSizeParam string `json:"blockSizeParam"`
-}
-
-type blkDiscard struct {
-	Device  string `json:"device"`
-	Options string `json:"options"`
-}
-
-type CmpPaths struct {
-	Path1 string `json:"path1"`
-	Path2 string `json:"path2"`
-}
-
-type Lvm struct {
-	Pv                          string `json:"pv"`                          // Physical volume
-	Vg                          string `json:"vg"`                          // Volume group
-	ThinPoolAutoExtendThreshold int    `json:"thinPoolAutoExtendThreshold"` // thin pool auto extend threshold
-	ThinPoolAutoExtendPercent   int    `json:"thinPoolAutoExtendPercent"`   // thin pool auto extend percent
-}
-
-type LoopDevice struct {
-	// Size in bytes
-	Size int64 `json:"size"`
-	// The backing image name
-	// eg: fake123
-	ImageName string `json:"imageName"`
-	// Image directory
-	// eg: /tmp
-	ImgDir string `json:"imgDir"`
-	// the disk name
-	// eg: /tmp/loop9002
-	DiskPath string `json:"diskPath"`
-	// mount point if any
-	MountPoint string `json:"mountPoint"`
-}
-type Zpool struct {
-	PoolDiskPath string `json:"poolDiskPath"`
-	PoolName     string `json:"poolName"`
-}
+// authToken is the bearer token sent with every request, if set
+var authToken string
 
-type Rdma struct {
-	DeviceName    string `json:"deviceName"`
-	InterfaceName string `json:"interfaceName"`
+// SetAuthToken sets the bearer token sent with every request to the e2e-agent,
+// see api.AuthSecretName
+func SetAuthToken(token string) {
+	authToken = token
 }
 
-type NetworkInterface struct {
-	NetworkInterface string `json:"networkInterface"`
+func addAuthHeader(req *http.Request) {
+	if authToken != "" {
+		req.Header.Add("Authorization", "Bearer "+authToken)
+	}
 }
 
 func sendRequest(reqType, url string, data interface{}) error {
@@ -123,6 +51,39 @@ func getAgentAddress(ipAddress string) string {
 	return addrPort
 }
 
+// StatusError is the error of a request to which the e2e-agent responded with a status other than 200
+type StatusError struct {
+	StatusCode int
+	Method     string
+	Url        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request returned code %d, %v, %s", e.StatusCode, e.Method, e.Url)
+}
+
+// statusCode returns the status of the response to the request which failed with err, or 0
+func statusCode(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
+
+// IsUnsupported returns true if err is the error of a route which the e2e-agent does not serve,
+// eg: the deployed image predates the route
+func IsUnsupported(err error) bool {
+	code := statusCode(err)
+	return code == http.StatusNotFound || code == http.StatusMethodNotAllowed
+}
+
+// IsUnauthorized returns true if err is the error of a request rejected for its bearer token,
+// see SetAuthToken
+func IsUnauthorized(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
 func sendRequestGetResponse(reqType, url string, data interface{}, verbose bool) (string, error) {
 	client := &http.Client{}
 	reqData := new(bytes.Buffer)
@@ -135,13 +96,14 @@ func sendRequestGetResponse(reqType, url string, data interface{}, verbose bool)
 	}
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
+	addAuthHeader(req)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("request returned code %d, %v, %s", resp.StatusCode, reqType, url)
+		return "", &StatusError{StatusCode: resp.StatusCode, Method: reqType, Url: url}
 	}
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -153,16 +115,55 @@ func sendRequestGetResponse(reqType, url string, data interface{}, verbose bool)
 	return string(bodyBytes), nil
 }
 
+// callAgentOutput sends a request to the e2e-agent route and returns the unwrapped
+// output of the response envelope, for routes whose output is the text of a command
+func callAgentOutput(serverAddr string, route string, data interface{}) (string, error) {
+	url := "http://" + getAgentAddress(serverAddr) + route
+	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s to e2e-agent %s, error: %w", route, serverAddr, err)
+	}
+	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap %s result, error: %s", route, err.Error())
+	}
+	if e2eagenterrcode != ErrNone {
+		return out, fmt.Errorf("%s failed, errcode %d, output: %s", route, e2eagenterrcode, out)
+	}
+	return out, nil
+}
+
+// callAgent sends a request to the e2e-agent route, unwraps the response envelope
+// and, if result is not nil, decodes the typed response into result
+func callAgent(serverAddr string, route string, data interface{}, result interface{}) error {
+	out, err := callAgentOutput(serverAddr, route, data)
+	if err != nil || result == nil {
+		return err
+	}
+	if err = json.Unmarshal([]byte(out), result); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response %s, error: %s", route, out, err.Error())
+	}
+	return nil
+}
+
+// GetCapabilities returns the version of the e2e-agent and the routes it supports
+func GetCapabilities(serverAddr string) (Capabilities, error) {
+	var caps Capabilities
+	logf.Log.Info("Executing capabilities", "addr", serverAddr)
+	err := callAgent(serverAddr, api.RouteCapabilities, nil, &caps)
+	return caps, err
+}
+
 // UngracefulReboot crashes and reboots the host machine
 func UngracefulReboot(serverAddr string) error {
 	logf.Log.Info("Executing ungraceReboot", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/ungracefulReboot"
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteUngracefulReboot
 	return sendRequest("POST", url, nil)
 }
 
 // IsAgentReachable checks if the agent pod is in reachable
 func IsAgentReachable(serverAddr string) error {
-	url := "http://" + getAgentAddress(serverAddr) + "/"
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteHome
 	return sendRequest("GET", url, nil)
 }
 
@@ -170,17 +171,23 @@ func IsAgentReachable(serverAddr string) error {
 // It is not yet supported
 func GracefulReboot(serverAddr string) error {
 	logf.Log.Info("Executing gracefulReboot", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/gracefulReboot"
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteGracefulReboot
 	return sendRequest("POST", url, nil)
 }
 
 // DropConnectionsFromNodes creates rules to drop connections from other k8s nodes
 func DropConnectionsFromNodes(serverAddr string, nodes []string) error {
+	return DropConnectionsFromNodesWithLease(serverAddr, nodes, 0)
+}
 
-	url := "http://" + getAgentAddress(serverAddr) + "/dropConnectionsFromNodes"
+// DropConnectionsFromNodesWithLease drops connections from other k8s nodes,
+// the e2e-agent accepts the connections again when the lease expires
+func DropConnectionsFromNodesWithLease(serverAddr string, nodes []string, lease time.Duration) error {
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteDropConnectionsFromNodes
 	data := NodeList{
 		Nodes:            nodes,
 		NetworkInterface: e2e_config.GetConfig().NetworkInterface,
+		LeaseSeconds:     leaseSeconds(lease),
 	}
 	logf.Log.Info("Executing dropConnectionsFromNodes", "addr", serverAddr, "data", data)
 	return sendRequest("POST", url, data)
@@ -189,7 +196,7 @@ func DropConnectionsFromNodes(serverAddr string, nodes []string) error {
 // AcceptConnectionsFromNodes removes the rules set by
 // DropConnectionsFromNodes so that other k8s nodes can reach this node again
 func AcceptConnectionsFromNodes(serverAddr string, nodes []string) error {
-	url := "http://" + getAgentAddress(serverAddr) + "/acceptConnectionsFromNodes"
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteAcceptConnectionsFromNodes
 	data := NodeList{
 		Nodes:            nodes,
 		NetworkInterface: e2e_config.GetConfig().NetworkInterface,
@@ -200,7 +207,7 @@ func AcceptConnectionsFromNodes(serverAddr string, nodes []string) error {
 
 // DiskPartition performs operation related to disk prtitioning
 func DiskPartition(serverAddr string, cmd string) error {
-	url := "http://" + getAgentAddress(serverAddr) + "/parted"
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteParted
 	data := CmdList{
 		Cmd: cmd,
 	}
@@ -210,10 +217,17 @@ func DiskPartition(serverAddr string, cmd string) error {
 
 // CreateFaultyDevice creates a device which returns an error on write IOs
 func CreateFaultyDevice(serverAddr, device, table string) error {
-	url := "http://" + getAgentAddress(serverAddr) + "/createFaultyDevice"
+	return CreateFaultyDeviceWithLease(serverAddr, device, table, 0)
+}
+
+// CreateFaultyDeviceWithLease creates a device which returns an error on write IOs,
+// the e2e-agent deletes the device when the lease expires
+func CreateFaultyDeviceWithLease(serverAddr, device, table string, lease time.Duration) error {
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteCreateFaultyDevice
 	data := Device{
-		Device: device,
-		Table:  table,
+		Device:       device,
+		Table:        table,
+		LeaseSeconds: leaseSeconds(lease),
 	}
 	logf.Log.Info("Executing createFaultyDevice", "addr", serverAddr, "data", data)
 	return sendRequest("POST", url, data)
@@ -221,7 +235,7 @@ func CreateFaultyDevice(serverAddr, device, table string) error {
 
 // DeleteFaultyDevice deletes a device which returns an error on write IOs
 func DeleteFaultyDevice(serverAddr, device string) error {
-	url := "http://" + getAgentAddress(serverAddr) + "/deleteFaultyDevice"
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteDeleteFaultyDevice
 	data := Device{
 		Device: device,
 	}
@@ -232,83 +246,65 @@ func DeleteFaultyDevice(serverAddr, device string) error {
 // ControlDevice sets the specified to the specified state
 // by writing to /sys/block/<device e.g. sdb>/device/state
 // The only accepted states are "running" and "offline"
-func ControlDevice(serverAddr string, device string, state string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/devicecontrol"
+func ControlDevice(serverAddr string, device string, state string) (DeviceState, error) {
+	return ControlDeviceWithLease(serverAddr, device, state, 0)
+}
+
+// ControlDeviceWithLease sets the device to the specified state,
+// the e2e-agent sets an offline device running again when the lease expires
+func ControlDeviceWithLease(serverAddr string, device string, state string, lease time.Duration) (DeviceState, error) {
+	var result DeviceState
 	data := ControlledDevice{
-		Device: device,
-		State:  state,
+		Device:       device,
+		State:        state,
+		LeaseSeconds: leaseSeconds(lease),
 	}
 	logf.Log.Info("Executing devicecontrol", "addr", serverAddr, "data", data)
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteDeviceControl, data, &result)
+	return result, err
 }
 
-// KillIoEngine use kill -9 against the mayastor
-func KillIoEngine(serverAddr string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/killioengine"
+// KillIoEngine use kill -9 against the mayastor, returning the pids killed
+func KillIoEngine(serverAddr string) (KilledProcess, error) {
+	var killed KilledProcess
 	logf.Log.Info("Executing killioengine", "addr", serverAddr)
-	return sendRequestGetResponse("POST", url, nil, true)
+	err := callAgent(serverAddr, api.RouteKillIoEngine, nil, &killed)
+	return killed, err
 }
 
 // KillCsiController use kill -9 against the mayastor csi controller process
 func KillCsiController(serverAddr string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/killCsiController"
 	logf.Log.Info("Executing killCsiController", "addr", serverAddr)
-	result, err := sendRequestGetResponse("POST", url, nil, true)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, errCode, err := UnwrapResult(result)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	return callAgentOutput(serverAddr, api.RouteKillCsiController, nil)
 }
 
 // KillCsiNode use kill -9 against the mayastor csi node process
 func KillCsiNode(serverAddr string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/killCsiNode"
 	logf.Log.Info("Executing killCsiNode", "addr", serverAddr)
-	result, err := sendRequestGetResponse("POST", url, nil, true)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, errCode, err := UnwrapResult(result)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	return callAgentOutput(serverAddr, api.RouteKillCsiNode, nil)
 }
 
-// Flushcache flush the container engine cache
+// FlushDiskWriteCache flushes the disk write cache of the node
 func FlushDiskWriteCache(serverAddr string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/flushDiskWriteCache"
 	logf.Log.Info("Executing flushcache", "addr", serverAddr)
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, true)
-	if err != nil {
-		return encodedresult, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		return encodedresult, fmt.Errorf("failed to unwrap result, error: %s", err.Error())
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to flush container engine cache, errcode %d", e2eagenterrcode)
-	}
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteFlushDiskWriteCache, nil)
 }
 
-// GetDeviceState sends the disk pool device status
-func GetDeviceState(serverAddr string, disk string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/getdevicestate"
+// GetDeviceState returns the state of the device of the disk pool disk,
+// read from /sys/block/<device e.g. sdb>/device/state
+func GetDeviceState(serverAddr string, disk string) (DeviceState, error) {
+	var state DeviceState
 	data := DiskPool{
 		Disk: disk,
 	}
 	logf.Log.Info("Executing getdevicestate", "addr", serverAddr, "data", data)
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteGetDeviceState, data, &state)
+	return state, err
 }
 
-// NvmeConnect to connect to the target
-func NvmeConnect(serverAddr string, targetIp string, nqn string, hostNqn string) (string, error) {
+// NvmeConnect to connect to the target, the Output of the connection is empty on success
+func NvmeConnect(serverAddr string, targetIp string, nqn string, hostNqn string) (NvmeConnection, error) {
+	var connection NvmeConnection
 	data := Nvme{
 		TargetIp: targetIp,
 		Nqn:      nqn,
@@ -316,43 +312,47 @@ func NvmeConnect(serverAddr string, targetIp string, nqn string, hostNqn string)
 		HostId:   "",
 	}
 	logf.Log.Info("Executing nvmeconnect", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/nvmeconnect"
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteNvmeConnect, data, &connection)
+	return connection, err
 }
 
-// NvmeDisconnect to disconnect to the nvme target
-func NvmeDisconnect(serverAddr string, nqn string) (string, error) {
+// NvmeDisconnect to disconnect to the nvme target, returning the number of controllers disconnected
+func NvmeDisconnect(serverAddr string, nqn string) (NvmeDisconnection, error) {
+	var disconnection NvmeDisconnection
 	data := Nvme{
 		Nqn: nqn,
 	}
 	logf.Log.Info("Executing nvmedisconnect", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/nvmedisconnect"
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteNvmeDisconnect, data, &disconnection)
+	return disconnection, err
 }
 
-// NvmeList list nvme target
-func NvmeList(serverAddr string) (string, error) {
+// NvmeList lists the nvme devices on the node
+func NvmeList(serverAddr string) (NvmeListResponse, error) {
+	var list NvmeListResponse
 	logf.Log.Info("Executing nvmelist", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/nvmelist"
-	return sendRequestGetResponse("POST", url, nil, false)
+	err := callAgent(serverAddr, api.RouteNvmeList, nil, &list)
+	return list, err
 }
 
-// NvmeListSubSys list nvme target
-func NvmeListSubSys(serverAddr string) (string, error) {
+// NvmeListSubSys lists the nvme subsystems and their paths on the node
+func NvmeListSubSys(serverAddr string) (NvmeListSubSysResponse, error) {
+	var subsystems NvmeListSubSysResponse
 	logf.Log.Info("Executing nvmlistsubsys", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/nvmelistsubsys"
-	return sendRequestGetResponse("POST", url, nil, false)
+	err := callAgent(serverAddr, api.RouteNvmeListSubSys, nil, &subsystems)
+	return subsystems, err
 }
 
-// checksum the device
-// the returned format is <checksum> <size> <device>
-func ChecksumDevice(serverAddr string, devicePath string) (string, error) {
+// ChecksumDevice returns the cksum checksum and size of the device,
+// use RunJob with JobChecksumDevice for devices whose checksum outlasts an HTTP request
+func ChecksumDevice(serverAddr string, devicePath string) (DeviceChecksum, error) {
+	var checksum DeviceChecksum
 	data := Device{
 		DevicePath: devicePath,
 	}
 	logf.Log.Info("Executing checksumdevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/checksumdevice"
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteChecksumDevice, data, &checksum)
+	return checksum, err
 }
 
 // fsCheck the device
@@ -362,16 +362,7 @@ func FsCheckDevice(serverAddr string, devicePath string, fsType common.FileSyste
 		FsType:     string(fsType),
 	}
 	logf.Log.Info("Executing fscheckdevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/fscheckdevice"
-	result, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, errCode, err := UnwrapResult(result)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	return callAgentOutput(serverAddr, api.RouteFsCheckDevice, data)
 }
 
 // Performs fscheck equivalent for XFS filesystem
@@ -380,17 +371,8 @@ func XFSCheckDevice(serverAddr string, devicePath string, fsType common.FileSyst
 		DevicePath: devicePath,
 		FsType:     string(fsType),
 	}
-	logf.Log.Info("Executing fscheckdevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/xfscheckdevice"
-	result, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, errCode, err := UnwrapResult(result)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	logf.Log.Info("Executing xfscheckdevice", "addr", serverAddr, "data", data)
+	return callAgentOutput(serverAddr, api.RouteXFSCheckDevice, data)
 }
 
 // fsFreeze the device
@@ -399,16 +381,7 @@ func FsFreezeDevice(serverAddr string, devicePath string, fsType common.FileSyst
 		DevicePath: devicePath,
 	}
 	logf.Log.Info("Executing fsfreezedevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/fsfreezedevice"
-	result, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, errCode, err := UnwrapResult(result)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	return callAgentOutput(serverAddr, api.RouteFsFreezeDevice, data)
 }
 
 // fsUnfreeze the device
@@ -417,49 +390,52 @@ func FsUnfreezeDevice(serverAddr string, devicePath string) (string, error) {
 		DevicePath: devicePath,
 	}
 	logf.Log.Info("Executing fsUnfreezedevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/fsunfreezedevice"
-	result, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, errCode, err := UnwrapResult(result)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	return callAgentOutput(serverAddr, api.RouteFsUnfreezeDevice, data)
 }
 
-// ListDevice list device
-func ListDevice(serverAddr string) (string, error) {
+// ListDevice returns the names of the entries of /dev on the node
+func ListDevice(serverAddr string) ([]string, error) {
+	var list DeviceList
 	logf.Log.Info("Executing listdevice", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/listdevice"
-	return sendRequestGetResponse("POST", url, nil, false)
+	err := callAgent(serverAddr, api.RouteListDevice, nil, &list)
+	return list.Devices, err
+}
+
+// Lsblk returns the block devices of the node
+func Lsblk(serverAddr string) ([]BlockDevice, error) {
+	var resp LsblkResponse
+	logf.Log.Info("Executing lsblk", "addr", serverAddr)
+	err := callAgent(serverAddr, api.RouteLsblk, nil, &resp)
+	return resp.BlockDevices, err
 }
 
 // ZeroingDisk replace every block of ‘sda’ with zeroes.
-func ZeroingDisk(serverAddr string, device string, seekParam string, blockSizeParam string) (string, error) {
+func ZeroingDisk(serverAddr string, device string, seekParam string, blockSizeParam string) (DiskWrite, error) {
+	var result DiskWrite
 	data := Disk{
 		Device:         device,
 		SeekParam:      seekParam,
 		BlockSizeParam: blockSizeParam,
 	}
 	logf.Log.Info("Executing zeroingdisk", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/zeroingdisk"
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteZeroingDisk, data, &result)
+	return result, err
 }
 
-// ListReservation list revervation report
-func ListReservation(serverAddr string, devicePath string) (string, error) {
+// ListReservation returns the reservation report of the nvme device
+func ListReservation(serverAddr string, devicePath string) (ReservationReport, error) {
+	var report ReservationReport
 	data := Device{
 		DevicePath: devicePath,
 	}
 	logf.Log.Info("Executing listreservation", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/listreservation"
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteListReservation, data, &report)
+	return report, err
 }
 
-// NvmeConnectWithHostId to connect to the target
-func NvmeConnectWithHostId(serverAddr string, targetIp string, nqn string, hostNqn string, hostId string) (string, error) {
+// NvmeConnectWithHostId to connect to the target, the Output of the connection is empty on success
+func NvmeConnectWithHostId(serverAddr string, targetIp string, nqn string, hostNqn string, hostId string) (NvmeConnection, error) {
+	var connection NvmeConnection
 	data := Nvme{
 		TargetIp: targetIp,
 		Nqn:      nqn,
@@ -467,80 +443,42 @@ func NvmeConnectWithHostId(serverAddr string, targetIp string, nqn string, hostN
 		HostId:   hostId,
 	}
 	logf.Log.Info("Executing nvmeconnectwithhostid", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/nvmeconnectwithhostid"
-	return sendRequestGetResponse("POST", url, data, false)
+	err := callAgent(serverAddr, api.RouteNvmeConnectWithHostId, data, &connection)
+	return connection, err
 }
 
-func BlkDiscard(serverAddr string, device string, options string) (string, error) {
-	data := blkDiscard{
+// BlkDiscard discards the blocks of the device, options are passed to blkdiscard, eg: "-v -z"
+func BlkDiscard(serverAddr string, device string, options string) (DiskWrite, error) {
+	var result DiskWrite
+	data := api.BlkDiscard{
 		Device:  device,
 		Options: options,
 	}
 	logf.Log.Info("Executing blkdiscard on node", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/blkdiscard"
-	res, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return res, err
-	}
-	return "", err
-}
-
-type EventSubscriptionRequest struct {
-	EventServerAddr string `json:"eventServerAddr"`
-	Subject         string `json:"subject"`
-}
-
-type EventPublishRequest struct {
-	EventServerAddr string `json:"eventServerAddr"`
-	Subject         string `json:"subject"`
-	Data            string `json:"data"`
-}
-
-type EventRequest struct {
-	Subject string `json:"subject"`
+	err := callAgent(serverAddr, api.RouteBlkDiscard, data, &result)
+	return result, err
 }
 
+// EventList returns the events of the subject received by the subscription of the e2e-agent
 func EventList(agentAddr string, subject string) (string, error) {
 	data := EventRequest{
 		Subject: subject,
 	}
 	logf.Log.Info("Executing EventList on node", "addr", agentAddr, "data", data)
-	url := "http://" + getAgentAddress(agentAddr) + "/event/list"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return encodedresult, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		return encodedresult, fmt.Errorf("failed to unwrap result, error: %s", err.Error())
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to list events, errcode %d", e2eagenterrcode)
-	}
-	return out, err
+	return callAgentOutput(agentAddr, api.RouteEventList, data)
 }
 
+// EventSubscribe subscribes the e2e-agent to the subject of the event server
 func EventSubscribe(agentAddr string, eventServerAddr string, subject string) (string, error) {
 	data := EventSubscriptionRequest{
 		EventServerAddr: eventServerAddr,
 		Subject:         subject,
 	}
 	logf.Log.Info("Executing EventSubscribe on node", "addr", agentAddr, "data", data)
-	url := "http://" + getAgentAddress(agentAddr) + "/event/subscribe"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to subscribe, errcode %d", e2eagenterrcode)
-	}
-	return out, err
+	return callAgentOutput(agentAddr, api.RouteEventSubscribe, data)
 }
 
+// EventPublish publishes the message to the subject of the event server
 func EventPublish(agentAddr string, eventServerAddr string, subject string, message string) (string, error) {
 	data := EventPublishRequest{
 		EventServerAddr: eventServerAddr,
@@ -548,121 +486,40 @@ func EventPublish(agentAddr string, eventServerAddr string, subject string, mess
 		Data:            message,
 	}
 	logf.Log.Info("Executing EventPublish on node", "addr", agentAddr, "data", data)
-	url := "http://" + getAgentAddress(agentAddr) + "/event/publish"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to publish, errcode %d", e2eagenterrcode)
-	}
-	return out, err
+	return callAgentOutput(agentAddr, api.RouteEventPublish, data)
 }
 
+// EventUnsubscribe removes the subscription of the e2e-agent to the subject
 func EventUnsubscribe(agentAddr string, subject string) (string, error) {
 	data := EventRequest{
 		Subject: subject,
 	}
 	logf.Log.Info("Executing EventUnsubscribe on node", "addr", agentAddr, "data", data)
-	url := "http://" + getAgentAddress(agentAddr) + "/event/unsubscribe"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to unsubscribe, errcode %d", e2eagenterrcode)
-	}
-	return out, err
+	return callAgentOutput(agentAddr, api.RouteEventUnsubscribe, data)
 }
 
+// EventUnsubscribeAll removes every subscription of the e2e-agent
 func EventUnsubscribeAll(agentAddr string) (string, error) {
 	logf.Log.Info("Executing EventUnsubscribeAll on node", "addr", agentAddr)
-	url := "http://" + getAgentAddress(agentAddr) + "/event/unsubscribeall"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to unsubscribe all, errcode %d", e2eagenterrcode)
-	}
-	return out, err
-}
-
-type Stats struct {
-	ServiceAddr string `json:"serviceAddr"`
+	return callAgentOutput(agentAddr, api.RouteEventUnsubscribeAll, nil)
 }
 
+// GetStats returns the metrics of the stats service in the Prometheus text
+// exposition format, see common/stats for the parsing
 func GetStats(agentAddr string, serviceAddr string) (string, error) {
 	logf.Log.Info("Executing GetStats", "e2eagent node", agentAddr, "service", serviceAddr)
 	data := Stats{
 		ServiceAddr: serviceAddr,
 	}
-
-	url := "http://" + getAgentAddress(agentAddr) + "/stats"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to get stats, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("GetStats succeeded", "output", out)
-	return out, err
+	return callAgentOutput(agentAddr, api.RouteStats, data)
 }
 
 // ZeroHugePages sets Huge Pages value to 0 on the selected node
-func ZeroHugePages(serverAddr string) (string, error) {
-	url := "http://" + getAgentAddress(serverAddr) + "/hugepagezero"
+func ZeroHugePages(serverAddr string) (HugePages, error) {
+	var pages HugePages
 	logf.Log.Info("Executing hugepagezero", "addr", serverAddr)
-	return sendRequestGetResponse("POST", url, nil, false)
-}
-
-type E2eAgentErrcode int
-
-const (
-	// general errors
-	ErrNone       E2eAgentErrcode = 0
-	ErrGeneral    E2eAgentErrcode = 1
-	ErrJsonDecode E2eAgentErrcode = 2
-	ErrJsonEncode E2eAgentErrcode = 3
-	ErrReadFail   E2eAgentErrcode = 4
-	ErrExecFailed E2eAgentErrcode = 5
-
-	// event errors
-	ErrConnectFail          E2eAgentErrcode = 101
-	ErrConnectedOther       E2eAgentErrcode = 102
-	ErrNotConnected         E2eAgentErrcode = 103
-	ErrSubscriptionNotFound E2eAgentErrcode = 104
-	ErrSubscribedAlready    E2eAgentErrcode = 105
-	ErrSubscribeFail        E2eAgentErrcode = 106
-	ErrStreamAddFail        E2eAgentErrcode = 107
-	ErrStreamCreateFail     E2eAgentErrcode = 108
-	ErrPublishFail          E2eAgentErrcode = 109
-)
-
-type E2eAgentError struct {
-	Output    string          `json:"output"`
-	Errorcode E2eAgentErrcode `json:"errorcode"`
+	err := callAgent(serverAddr, api.RouteHugePageZero, nil, &pages)
+	return pages, err
 }
 
 func UnwrapResult(res string) (string, E2eAgentErrcode, error) {
@@ -682,83 +539,31 @@ func Cmp(serverAddr string, path1 string, path2 string) (string, error) {
 		Path2: path2,
 	}
 	logf.Log.Info("Executing cmp", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/cmp"
-	result, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		return result, fmt.Errorf("failed to send command to e2e-agent, error: %s", err.Error())
-	}
-	b64out, errCode, err := UnwrapResult(result)
+	b64out, err := callAgentOutput(serverAddr, api.RouteCmp, data)
 	out, _ := base64.StdEncoding.DecodeString(b64out)
-	if err == nil && errCode == 0 {
-		return string(out), nil
-	}
-	return string(out), fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+	return string(out), err
 }
 
-// LvmListVg list lvm vg
-func LvmListVg(serverAddr string) (string, error) {
+// LvmListVg returns the lvm volume groups of the node
+func LvmListVg(serverAddr string) ([]LvmVg, error) {
+	var vgs []LvmVg
 	logf.Log.Info("Executing LvmListVg", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmlistvg"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to get lvm vg, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmListVg succeeded", "output", out)
-	return out, err
+	err := callAgent(serverAddr, api.RouteLvmListVg, nil, &vgs)
+	return vgs, err
 }
 
-// LvmListPv list lvm pv
-func LvmListPv(serverAddr string) (string, error) {
+// LvmListPv returns the lvm physical volumes of the node
+func LvmListPv(serverAddr string) ([]LvmPv, error) {
+	var pvs []LvmPv
 	logf.Log.Info("Executing LvmListPv", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmlistpv"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to get lvm pv, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmListPv succeeded", "output", out)
-	return out, err
+	err := callAgent(serverAddr, api.RouteLvmListPv, nil, &pvs)
+	return pvs, err
 }
 
 // LvmVersion get lvm version
 func LvmVersion(serverAddr string) (string, error) {
 	logf.Log.Info("Executing LvmVersion", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmversion"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to get lvm version, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmVersion succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmVersion, nil)
 }
 
 // LvmCreatePv create lvm pv
@@ -767,22 +572,7 @@ func LvmCreatePv(serverAddr string, pvDiskPath string) (string, error) {
 		Pv: pvDiskPath,
 	}
 	logf.Log.Info("Executing lvmcreatepv", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmcreatepv"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to create lvm pv, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmCreatePv succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmCreatePv, data)
 }
 
 // LvmCreateVg create lvm vg
@@ -792,22 +582,7 @@ func LvmCreateVg(serverAddr string, pvDiskPath string, vgName string) (string, e
 		Vg: vgName,
 	}
 	logf.Log.Info("Executing lvmcreatevg", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmcreatevg"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to create lvm pv, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmCreateVg succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmCreateVg, data)
 }
 
 // LvmRemovePv remove lvm pv
@@ -816,22 +591,7 @@ func LvmRemovePv(serverAddr string, pvDiskPath string) (string, error) {
 		Pv: pvDiskPath,
 	}
 	logf.Log.Info("Executing lvmremovepv", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmremovepv"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to remove lvm pv, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmRemovePv succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmRemovePv, data)
 }
 
 // LvmRemoveVg remove lvm vg
@@ -840,22 +600,7 @@ func LvmRemoveVg(serverAddr string, vgName string) (string, error) {
 		Vg: vgName,
 	}
 	logf.Log.Info("Executing lvmremovevg", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmremovevg"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to remove lvm vg, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmRemoveVg succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmRemoveVg, data)
 }
 
 // LvmThinPoolAutoExtendThreshold update lvm thin pool auto extend threshold value
@@ -864,22 +609,7 @@ func LvmThinPoolAutoExtendThreshold(serverAddr string, thinPoolAutoExtendThresho
 		ThinPoolAutoExtendThreshold: thinPoolAutoExtendThreshold,
 	}
 	logf.Log.Info("Executing lvmthinpoolautoextendthreshold", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmthinpoolautoextendthreshold"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to update lvm thin pool auto extend threshold, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmThinPoolAutoExtendThreshold succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmThinPoolAutoExtendThreshold, data)
 }
 
 // LvmThinPoolAutoExtendPercent update lvm thin pool auto extend percent value
@@ -888,22 +618,7 @@ func LvmThinPoolAutoExtendPercent(serverAddr string, thinPoolAutoExtendPercent i
 		ThinPoolAutoExtendPercent: thinPoolAutoExtendPercent,
 	}
 	logf.Log.Info("Executing lvmthinpoolautoextendpercent", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmthinpoolautoextendpercent"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to update lvm thin pool auto extend percent, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmThinPoolAutoExtendPercent succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmThinPoolAutoExtendPercent, data)
 }
 
 // CreateLoopDevice create loop device
@@ -913,26 +628,7 @@ func CreateLoopDevice(serverAddr string, size int64, imageDir string) (LoopDevic
 		Size:   size,
 	}
 	logf.Log.Info("Executing createloopdevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/createloopdevice"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return data, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return data, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return data, fmt.Errorf("failed to create loop device, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("CreateLoopDevice succeeded", "output", out)
-
-	if err = json.Unmarshal([]byte(out), &data); err != nil {
-		logf.Log.Info("Failed to unmarshal loop device", "output", out)
-		return data, err
-	}
+	err := callAgent(serverAddr, api.RouteCreateLoopDevice, data, &data)
 	logf.Log.Info("loop device", "data", data)
 	return data, err
 }
@@ -944,66 +640,21 @@ func DeleteLoopDevice(serverAddr string, diskPath string, imageName string) (str
 		ImageName: imageName,
 	}
 	logf.Log.Info("Executing deleteloopdevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/deleteloopdevice"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to delete loop device, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("DeleteLoopDevice succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteDeleteLoopDevice, data)
 }
 
-// ZfsListPool list zfs pool
-func ZfsListPool(serverAddr string) (string, error) {
+// ZfsListPool returns the zfs pools of the node
+func ZfsListPool(serverAddr string) ([]ZfsPool, error) {
+	var pools []ZfsPool
 	logf.Log.Info("Executing ZfsListPool", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/zfslistpool"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to list zfs pool, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("ZfsListPool succeeded", "output", out)
-	return out, err
+	err := callAgent(serverAddr, api.RouteZfsListPool, nil, &pools)
+	return pools, err
 }
 
 // ZfsVersion get zfs version
 func ZfsVersion(serverAddr string) (string, error) {
 	logf.Log.Info("Executing ZfsVersion", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/zfsversion"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to get zfs version, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("ZfsVersion succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteZfsVersion, nil)
 }
 
 // ZfsCreatePool create zfs pool
@@ -1013,22 +664,7 @@ func ZfsCreatePool(serverAddr string, poolDiskPath string, poolName string) (str
 		PoolName:     poolName,
 	}
 	logf.Log.Info("Executing ZfsCreatePool", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/zfscreatepool"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to create zfs pool, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("ZfsCreatePool succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteZfsCreatePool, data)
 }
 
 // ZfsDestroyPool destroy zfs pool
@@ -1037,22 +673,7 @@ func ZfsDestroyPool(serverAddr string, poolName string) (string, error) {
 		PoolName: poolName,
 	}
 	logf.Log.Info("Executing ZfsDestroyPool", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/zfsdestroypool"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to destroy zfs pool, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("ZfsDestroyPool succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteZfsDestroyPool, data)
 }
 
 // CreateHostPathDisk creates hostpath disk
@@ -1062,47 +683,17 @@ func CreateHostPathDisk(serverAddr string, diskPath string, mountPoint string) e
 		MountPoint: mountPoint,
 	}
 	logf.Log.Info("Executing createhostpathdisk", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/createhostpathdisk"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return err
-	}
-	if e2eagenterrcode != ErrNone {
-		return fmt.Errorf("failed to create hostapth disk, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("CreateHostPathDisk succeeded", "output", out)
-	return err
+	return callAgent(serverAddr, api.RouteCreateHostPathDisk, data, nil)
 }
 
-// RemoveHostPathDisk removes hostapth disk
+// RemoveHostPathDisk removes hostpath disk
 func RemoveHostPathDisk(serverAddr string, diskPath string, mountPoint string) error {
 	data := LoopDevice{
 		DiskPath:   diskPath,
 		MountPoint: mountPoint,
 	}
 	logf.Log.Info("Executing removehostpathdisk", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/removehostpathdisk"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return err
-	}
-	if e2eagenterrcode != ErrNone {
-		return fmt.Errorf("failed to remove hostapth disk, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("RemoveHostPathDisk succeeded", "output", out)
-	return err
+	return callAgent(serverAddr, api.RouteRemoveHostPathDisk, data, nil)
 }
 
 // LvmLvChangeMonitor monitor lvm lv
@@ -1111,22 +702,7 @@ func LvmLvChangeMonitor(serverAddr string, vgName string) (string, error) {
 		Vg: vgName,
 	}
 	logf.Log.Info("Executing lvchange", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmlvchangemonitor"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to monitor lvm lv, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmLvChangeMonitor succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteLvmLvChangeMonitor, data)
 }
 
 // LvmLvRemoveThinPool delete lvm thin pool lv
@@ -1134,45 +710,20 @@ func LvmLvRemoveThinPool(serverAddr string, vgName string) (string, error) {
 	data := Lvm{
 		Vg: vgName,
 	}
-	logf.Log.Info("Executing lvchange", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/lvmlvremovethinpool"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to remove lvm thin pool lv, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("LvmLvRemoveThinPool succeeded", "output", out)
-	return out, err
+	logf.Log.Info("Executing lvremove", "addr", serverAddr, "data", data)
+	return callAgentOutput(serverAddr, api.RouteLvmLvRemoveThinPool, data)
 }
 
 // ListRdmaDevice get pre-created and available RDMA device
-func ListRdmaDevice(serverAddr string) (string, error) {
+func ListRdmaDevice(serverAddr string) ([]RdmaLink, error) {
+	var links []RdmaLink
 	logf.Log.Info("Executing GetRdmaDevice", "addr", serverAddr)
-	url := "http://" + getAgentAddress(serverAddr) + "/listrdmadevice"
-	encodedresult, err := sendRequestGetResponse("POST", url, nil, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
+	err := callAgent(serverAddr, api.RouteListRdmaDevice, nil, &links)
 	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
+		return links, fmt.Errorf("failed to list available RDMA device, error: %v", err)
 	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to list available RDMA device, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("ListRdmaDevice succeeded", "output", out)
-	return out, err
+	logf.Log.Info("ListRdmaDevice succeeded", "output", links)
+	return links, err
 }
 
 // CreateRdmaDevice create rdma device
@@ -1182,46 +733,16 @@ func CreateRdmaDevice(serverAddr string, deviceName string, interfaceName string
 		InterfaceName: interfaceName,
 	}
 	logf.Log.Info("Executing CreateRdmaDevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/createrdmadevice"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to create rdma device, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("CreateRdmaDevice succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteCreateRdmaDevice, data)
 }
 
 // DeleteRdmaDevice destroy rdma device
-func DeleteRdmaDevice(serverAddr string, deeviceName string) (string, error) {
+func DeleteRdmaDevice(serverAddr string, deviceName string) (string, error) {
 	data := Rdma{
-		DeviceName: deeviceName,
+		DeviceName: deviceName,
 	}
 	logf.Log.Info("Executing DeleteRdmaDevice", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/deleterdmadevice"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to delete rdma device, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("DeleteRdmaDevice succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteDeleteRdmaDevice, data)
 }
 
 // EnableNetworkInterface enable network interface
@@ -1230,44 +751,239 @@ func EnableNetworkInterface(serverAddr string, interfaceName string) (string, er
 		NetworkInterface: interfaceName,
 	}
 	logf.Log.Info("Executing EnableNetworkInterface", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/enablenetworkinterface"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
-	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to enable network interface, errcode %d", e2eagenterrcode)
-	}
-	logf.Log.Info("EnableNetworkInterface succeeded", "output", out)
-	return out, err
+	return callAgentOutput(serverAddr, api.RouteEnableNetworkInterface, data)
 }
 
 // DisableNetworkInterface disable network interface
 func DisableNetworkInterface(serverAddr string, interfaceName string) (string, error) {
+	return DisableNetworkInterfaceWithLease(serverAddr, interfaceName, 0)
+}
+
+// DisableNetworkInterfaceWithLease disables the network interface,
+// the e2e-agent enables it again when the lease expires
+func DisableNetworkInterfaceWithLease(serverAddr string, interfaceName string, lease time.Duration) (string, error) {
 	data := NetworkInterface{
 		NetworkInterface: interfaceName,
+		LeaseSeconds:     leaseSeconds(lease),
 	}
 	logf.Log.Info("Executing DisableNetworkInterface", "addr", serverAddr, "data", data)
-	url := "http://" + getAgentAddress(serverAddr) + "/disablenetworkinterface"
-	encodedresult, err := sendRequestGetResponse("POST", url, data, false)
-	if err != nil {
-		logf.Log.Info("sendRequestGetResponse", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
+	return callAgentOutput(serverAddr, api.RouteDisableNetworkInterface, data)
+}
+
+// ApplyNetworkEmulation degrades the traffic sent from the node to emulation.Peers,
+// replacing any emulation previously applied on the node.
+// The emulation is one-way, the traffic the node receives from the peers is not degraded.
+// The network interface defaults to the configured network interface.
+// The e2e-agent removes the emulation when emulation.LeaseSeconds expires.
+func ApplyNetworkEmulation(serverAddr string, emulation NetworkEmulation) error {
+	if emulation.NetworkInterface == "" {
+		emulation.NetworkInterface = e2e_config.GetConfig().NetworkInterface
 	}
-	out, e2eagenterrcode, err := UnwrapResult(encodedresult)
-	if err != nil {
-		logf.Log.Info("unwrap failed", "encodedresult", encodedresult, "error", err.Error())
-		return encodedresult, err
+	logf.Log.Info("Executing ApplyNetworkEmulation", "addr", serverAddr, "data", emulation)
+	return callAgent(serverAddr, api.RouteApplyNetworkEmulation, emulation, nil)
+}
+
+// RemoveNetworkEmulation removes the network emulation applied on the configured network interface
+func RemoveNetworkEmulation(serverAddr string) error {
+	data := NetworkInterface{
+		NetworkInterface: e2e_config.GetConfig().NetworkInterface,
 	}
-	if e2eagenterrcode != ErrNone {
-		return out, fmt.Errorf("failed to disable network interface, errcode %d", e2eagenterrcode)
+	logf.Log.Info("Executing RemoveNetworkEmulation", "addr", serverAddr, "data", data)
+	return callAgent(serverAddr, api.RouteRemoveNetworkEmulation, data, nil)
+}
+
+// leaseSeconds converts a fault lease to whole seconds, rounding up
+// so that a short lease does not become no lease
+func leaseSeconds(lease time.Duration) int {
+	if lease <= 0 {
+		return 0
+	}
+	return int((lease + time.Second - 1) / time.Second)
+}
+
+// ListFaults returns the faults injected by the e2e-agent which have not been reverted
+func ListFaults(serverAddr string) ([]Fault, error) {
+	var faults []Fault
+	logf.Log.Info("Executing ListFaults", "addr", serverAddr)
+	err := callAgent(serverAddr, api.RouteListFaults, nil, &faults)
+	return faults, err
+}
+
+// ClearFaults reverts the faults injected by the e2e-agent and returns them
+func ClearFaults(serverAddr string) ([]Fault, error) {
+	var faults []Fault
+	logf.Log.Info("Executing ClearFaults", "addr", serverAddr)
+	err := callAgent(serverAddr, api.RouteClearFaults, nil, &faults)
+	return faults, err
+}
+
+// CreateDmFaultDevice wraps device.BackingDevice in a device mapper target
+// which injects faults and returns the path of the new device
+func CreateDmFaultDevice(serverAddr string, device DmFaultDevice) (string, error) {
+	var resp api.DmFaultDeviceResponse
+	logf.Log.Info("Executing CreateDmFaultDevice", "addr", serverAddr, "data", device)
+	err := callAgent(serverAddr, api.RouteCreateDmFaultDevice, device, &resp)
+	return resp.DevicePath, err
+}
+
+// ReloadDmFaultDevice replaces the target of a device created by CreateDmFaultDevice,
+// eg: to change the flakey intervals while IO is running
+func ReloadDmFaultDevice(serverAddr string, device DmFaultDevice) error {
+	logf.Log.Info("Executing ReloadDmFaultDevice", "addr", serverAddr, "data", device)
+	return callAgent(serverAddr, api.RouteReloadDmFaultDevice, device, nil)
+}
+
+// RemoveDmFaultDevice removes a device created by CreateDmFaultDevice
+func RemoveDmFaultDevice(serverAddr string, name string) error {
+	data := DmFaultDevice{
+		Name: name,
 	}
-	logf.Log.Info("DeleteRdmaDevice succeeded", "output", out)
-	return out, err
+	logf.Log.Info("Executing RemoveDmFaultDevice", "addr", serverAddr, "data", data)
+	return callAgent(serverAddr, api.RouteRemoveDmFaultDevice, data, nil)
+}
+
+// UpdateDmDust adds or removes bad blocks of a dust device created by CreateDmFaultDevice
+func UpdateDmDust(serverAddr string, update DmDustUpdate) error {
+	logf.Log.Info("Executing UpdateDmDust", "addr", serverAddr, "data", update)
+	return callAgent(serverAddr, api.RouteUpdateDmDust, update, nil)
+}
+
+// jobPollInterval is the interval at which WaitJob polls the status of a job
+const jobPollInterval = 2 * time.Second
+
+// StartJob starts a long running operation on the e2e-agent and returns the job id
+func StartJob(serverAddr string, req JobRequest) (string, error) {
+	var resp api.JobResponse
+	logf.Log.Info("Executing StartJob", "addr", serverAddr, "data", req)
+	err := callAgent(serverAddr, api.RouteStartJob, req, &resp)
+	return resp.Id, err
+}
+
+// GetJob returns the status of a job
+func GetJob(serverAddr string, id string) (Job, error) {
+	var job Job
+	err := callAgent(serverAddr, api.JobPath(api.RouteGetJob, id), nil, &job)
+	return job, err
+}
+
+// CancelJob kills a running job
+func CancelJob(serverAddr string, id string) error {
+	logf.Log.Info("Executing CancelJob", "addr", serverAddr, "id", id)
+	return callAgent(serverAddr, api.JobPath(api.RouteCancelJob, id), nil, nil)
+}
+
+// WaitJob waits for a job to finish and returns its status,
+// an error is returned unless the job succeeded.
+// The job is cancelled if ctx is done before the job finishes.
+func WaitJob(ctx context.Context, serverAddr string, id string) (Job, error) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := GetJob(serverAddr, id)
+		if err != nil {
+			return job, err
+		}
+		if job.Done() {
+			logf.Log.Info("job finished", "addr", serverAddr, "id", id, "state", job.State, "exitCode", job.ExitCode)
+			if job.State != api.JobSucceeded {
+				return job, fmt.Errorf("job %s %s %s, exit code %d, output: %s, stderr: %s",
+					id, job.Operation, job.State, job.ExitCode, job.Output, job.Stderr)
+			}
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			if err = CancelJob(serverAddr, id); err != nil {
+				logf.Log.Info("failed to cancel job", "addr", serverAddr, "id", id, "error", err)
+			}
+			return job, fmt.Errorf("job %s %s did not finish, progress: %s, error: %v",
+				id, job.Operation, job.Progress, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunJob starts a long running operation on the e2e-agent and waits for it to finish
+func RunJob(ctx context.Context, serverAddr string, req JobRequest) (Job, error) {
+	id, err := StartJob(serverAddr, req)
+	if err != nil {
+		return Job{}, err
+	}
+	return WaitJob(ctx, serverAddr, id)
+}
+
+// WriteDataset writes a deterministic dataset to a directory on the node,
+// eg: the mount point of a volume, and returns its manifest
+func WriteDataset(serverAddr string, path string, spec DatasetSpec) (DatasetManifest, error) {
+	var manifest DatasetManifest
+	data := api.DatasetRequest{
+		Path: path,
+		Spec: spec,
+	}
+	logf.Log.Info("Executing WriteDataset", "addr", serverAddr, "data", data)
+	err := callAgent(serverAddr, api.RouteWriteDataset, data, &manifest)
+	return manifest, err
+}
+
+// VerifyDataset verifies a dataset written by WriteDataset against its manifest,
+// the differences are listed by the report, see DatasetVerifyReport.Err
+func VerifyDataset(serverAddr string, path string, manifest DatasetManifest) (DatasetVerifyReport, error) {
+	var report DatasetVerifyReport
+	data := api.DatasetVerifyRequest{
+		Path:     path,
+		Manifest: manifest,
+	}
+	logf.Log.Info("Executing VerifyDataset", "addr", serverAddr, "path", path, "spec", manifest.Spec)
+	err := callAgent(serverAddr, api.RouteVerifyDataset, data, &report)
+	return report, err
+}
+
+// PauseProcess stops the processes of a container with SIGSTOP,
+// the agent continues them with SIGCONT once duration has elapsed
+func PauseProcess(serverAddr string, target ProcessTarget, duration time.Duration) (ProcessFaultResponse, error) {
+	var resp ProcessFaultResponse
+	data := api.ProcessPause{
+		ProcessTarget:   target,
+		DurationSeconds: leaseSeconds(duration),
+	}
+	logf.Log.Info("Executing PauseProcess", "addr", serverAddr, "data", data)
+	err := callAgent(serverAddr, api.RoutePauseProcess, data, &resp)
+	return resp, err
+}
+
+// ResumeProcess continues the processes stopped by PauseProcess before the duration has elapsed
+func ResumeProcess(serverAddr string, target ProcessTarget) error {
+	logf.Log.Info("Executing ResumeProcess", "addr", serverAddr, "data", target)
+	return callAgent(serverAddr, api.RouteResumeProcess, target, nil)
+}
+
+// ApplyCgroupPressure limits the CPU and memory of a container, requires cgroup v2.
+// The agent restores the previous limits once lease has elapsed, if not 0.
+func ApplyCgroupPressure(serverAddr string, pressure CgroupPressure, lease time.Duration) (ProcessFaultResponse, error) {
+	var resp ProcessFaultResponse
+	pressure.LeaseSeconds = leaseSeconds(lease)
+	logf.Log.Info("Executing ApplyCgroupPressure", "addr", serverAddr, "data", pressure)
+	err := callAgent(serverAddr, api.RouteApplyCgroupPressure, pressure, &resp)
+	return resp, err
+}
+
+// RemoveCgroupPressure restores the limits of a container changed by ApplyCgroupPressure
+func RemoveCgroupPressure(serverAddr string, containerId string) error {
+	data := ProcessTarget{
+		ContainerId: containerId,
+	}
+	logf.Log.Info("Executing RemoveCgroupPressure", "addr", serverAddr, "data", data)
+	return callAgent(serverAddr, api.RouteRemoveCgroupPressure, data, nil)
+}
+
+// OomKillContainer makes the kernel OOM kill the processes of a container,
+// requires cgroup v2. Returns the processes killed.
+func OomKillContainer(serverAddr string, containerId string) (ProcessFaultResponse, error) {
+	var resp ProcessFaultResponse
+	data := ProcessTarget{
+		ContainerId: containerId,
+	}
+	logf.Log.Info("Executing OomKillContainer", "addr", serverAddr, "data", data)
+	err := callAgent(serverAddr, api.RouteOomKillContainer, data, &resp)
+	return resp, err
 }
@@ -0,0 +1,165 @@
+package e2e_agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxCollectedLogLines bounds the memory used by a LogCollector,
+// the oldest lines are dropped beyond this
+const maxCollectedLogLines = 100000
+
+// openLogStream sends a follow log request, the stream has started once it returns
+func openLogStream(ctx context.Context, serverAddr string, req LogFollowRequest) (io.ReadCloser, error) {
+	url := "http://" + getAgentAddress(serverAddr) + api.RouteFollowLog
+	reqData := new(bytes.Buffer)
+	if err := json.NewEncoder(reqData).Encode(req); err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, reqData)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Add("Content-Type", "application/json")
+	addAuthHeader(httpReq)
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s to e2e-agent %s, error: %s", api.RouteFollowLog, serverAddr, err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		out, _, err := UnwrapResult(string(body))
+		if err != nil {
+			out = string(body)
+		}
+		return nil, fmt.Errorf("%s failed, code %d, output: %s", api.RouteFollowLog, resp.StatusCode, out)
+	}
+	return resp.Body, nil
+}
+
+// readLogStream calls fn for every line of the stream,
+// the end of the stream because ctx is done is not an error
+func readLogStream(ctx context.Context, stream io.ReadCloser, fn func(LogLine)) error {
+	defer stream.Close()
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line LogLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("failed to unmarshal log line %s, error: %v", scanner.Text(), err)
+		}
+		fn(line)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%s stream ended", api.RouteFollowLog)
+}
+
+// FollowLog calls fn for every line of the node log until ctx is done
+func FollowLog(ctx context.Context, serverAddr string, req LogFollowRequest, fn func(LogLine)) error {
+	logf.Log.Info("Executing FollowLog", "addr", serverAddr, "data", req)
+	stream, err := openLogStream(ctx, serverAddr, req)
+	if err != nil {
+		return err
+	}
+	return readLogStream(ctx, stream, fn)
+}
+
+// LogCollector collects the lines of a node log in the background
+type LogCollector struct {
+	mutex  sync.Mutex
+	lines  []LogLine
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// StartLogCollector starts collecting the lines of a node log,
+// the log is followed once it returns
+func StartLogCollector(serverAddr string, req LogFollowRequest) (*LogCollector, error) {
+	logf.Log.Info("Executing StartLogCollector", "addr", serverAddr, "data", req)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := openLogStream(ctx, serverAddr, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	collector := &LogCollector{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(collector.done)
+		err := readLogStream(ctx, stream, collector.add)
+		collector.mutex.Lock()
+		collector.err = err
+		collector.mutex.Unlock()
+	}()
+	return collector, nil
+}
+
+func (c *LogCollector) add(line LogLine) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lines = append(c.lines, line)
+	if len(c.lines) > maxCollectedLogLines {
+		c.lines = c.lines[len(c.lines)-maxCollectedLogLines:]
+	}
+}
+
+// Lines returns the lines collected so far
+func (c *LogCollector) Lines() []LogLine {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]LogLine(nil), c.lines...)
+}
+
+// Matching returns the lines collected so far which match re
+func (c *LogCollector) Matching(re *regexp.Regexp) []LogLine {
+	var matching []LogLine
+	for _, line := range c.Lines() {
+		if re.MatchString(line.Text) {
+			matching = append(matching, line)
+		}
+	}
+	return matching
+}
+
+// Stop stops collecting and returns the lines collected, the error is
+// set if the stream ended before Stop was called
+func (c *LogCollector) Stop() ([]LogLine, error) {
+	c.cancel()
+	<-c.done
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lines, c.err
+}
+
+// FormatLogLines returns the lines as text, one per line
+func FormatLogLines(lines []LogLine) string {
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+		sb.WriteString(" ")
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
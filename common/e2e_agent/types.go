@@ -0,0 +1,120 @@
+package e2e_agent
+
+import (
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+)
+
+// The request and response messages are shared with the e2e-agent,
+// these aliases keep the names used by existing callers.
+
+// NodeList is the list of nodes to be passed to e2e-agent
+type NodeList = api.NodeList
+type CmdList = api.CmdList
+type Device = api.Device
+type ControlledDevice = api.ControlledDevice
+type DiskPool = api.DiskPool
+type Product = api.Product
+type Nvme = api.Nvme
+type Disk = api.Disk
+type CmpPaths = api.CmpPaths
+type Lvm = api.Lvm
+type LoopDevice = api.LoopDevice
+type Zpool = api.Zpool
+type Rdma = api.Rdma
+type NetworkInterface = api.NetworkInterface
+type NetworkEmulation = api.NetworkEmulation
+type DmTarget = api.DmTarget
+type DmFaultDevice = api.DmFaultDevice
+type DmFlakeyParams = api.DmFlakeyParams
+type DmDelayParams = api.DmDelayParams
+type DmDustParams = api.DmDustParams
+type DmDustUpdate = api.DmDustUpdate
+type Stats = api.Stats
+type EventSubscriptionRequest = api.EventSubscriptionRequest
+type EventPublishRequest = api.EventPublishRequest
+type EventRequest = api.EventRequest
+
+type NvmeDevice = api.NvmeDevice
+type NvmeListResponse = api.NvmeListResponse
+type NvmePath = api.NvmePath
+type NvmeSubsystem = api.NvmeSubsystem
+type NvmeListSubSysResponse = api.NvmeListSubSysResponse
+type RegisteredController = api.RegisteredController
+type ReservationReport = api.ReservationReport
+type RdmaLink = api.RdmaLink
+type DeviceState = api.DeviceState
+type DeviceChecksum = api.DeviceChecksum
+type DeviceList = api.DeviceList
+type BlockDevice = api.BlockDevice
+type LsblkResponse = api.LsblkResponse
+type LvmVg = api.LvmVg
+type LvmPv = api.LvmPv
+type ZfsPool = api.ZfsPool
+type KilledProcess = api.KilledProcess
+type NvmeConnection = api.NvmeConnection
+type NvmeDisconnection = api.NvmeDisconnection
+type DiskWrite = api.DiskWrite
+type HugePages = api.HugePages
+type Capabilities = api.Capabilities
+type Fault = api.Fault
+type JobRequest = api.JobRequest
+type Job = api.Job
+type LogSource = api.LogSource
+type LogFollowRequest = api.LogFollowRequest
+type LogLine = api.LogLine
+type DatasetSpec = api.DatasetSpec
+type DatasetEntry = api.DatasetEntry
+type DatasetManifest = api.DatasetManifest
+type DatasetMismatch = api.DatasetMismatch
+type DatasetVerifyReport = api.DatasetVerifyReport
+type FaultKind = api.FaultKind
+type ProcessTarget = api.ProcessTarget
+type CgroupPressure = api.CgroupPressure
+type ProcessFaultResponse = api.ProcessFaultResponse
+
+type E2eAgentErrcode = api.E2eAgentErrcode
+type E2eAgentError = api.E2eAgentError
+
+const (
+	DmTargetError  = api.DmTargetError
+	DmTargetFlakey = api.DmTargetFlakey
+	DmTargetDelay  = api.DmTargetDelay
+	DmTargetDust   = api.DmTargetDust
+)
+
+const (
+	JobChecksumDevice = api.JobChecksumDevice
+	JobXFSCheckDevice = api.JobXFSCheckDevice
+	JobZeroingDisk    = api.JobZeroingDisk
+	JobBlkDiscard     = api.JobBlkDiscard
+)
+
+const (
+	LogKernel  = api.LogKernel
+	LogJournal = api.LogJournal
+)
+
+const (
+	// general errors
+	ErrNone                = api.ErrNone
+	ErrGeneral             = api.ErrGeneral
+	ErrJsonDecode          = api.ErrJsonDecode
+	ErrJsonEncode          = api.ErrJsonEncode
+	ErrReadFail            = api.ErrReadFail
+	ErrExecFailed          = api.ErrExecFailed
+	ErrFileNotExist        = api.ErrFileNotExist
+	ErrUnauthorized        = api.ErrUnauthorized
+	ErrUnprocessableEntity = api.ErrUnprocessableEntity
+
+	// event errors
+	ErrConnectFail          = api.ErrConnectFail
+	ErrConnectedOther       = api.ErrConnectedOther
+	ErrNotConnected         = api.ErrNotConnected
+	ErrSubscriptionNotFound = api.ErrSubscriptionNotFound
+	ErrSubscribedAlready    = api.ErrSubscribedAlready
+	ErrSubscribeFail        = api.ErrSubscribeFail
+	ErrStreamAddFail        = api.ErrStreamAddFail
+	ErrStreamCreateFail     = api.ErrStreamCreateFail
+	ErrPublishFail          = api.ErrPublishFail
+	ErrUnSubscribeFail      = api.ErrUnSubscribeFail
+)
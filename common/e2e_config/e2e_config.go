@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -58,6 +59,7 @@ type ProductSpec struct {
 	OpenEBSHelmReleaseName            string            `yaml:"openEBSHelmReleaseName"`
 	IOEnginePodLabelValue             string            `yaml:"ioEnginePodLabelValue" env-default:"io-engine"`
 	IOEnginePodName                   string            `yaml:"ioEnginePodName"`
+	IOEngineContainerName             string            `yaml:"ioEngineContainerName" env-default:"io-engine"`
 	JaegersCrdName                    string            `yaml:"jaegersCrdName" env-default:"jaegers.jaegertracing.io"`
 	KubectlPluginName                 string            `yaml:"kubectlPluginName" env-default:"kubectl-mayastor"`
 	KubectlPluginPort                 int               `yaml:"kubectlPluginPort" env-default:"30011"`
@@ -186,6 +188,24 @@ type E2EConfig struct {
 	SessionDir        string `yaml:"sessionDir" env:"e2e_session_dir"`
 	MayastorVersion   string `yaml:"mayastorVersion" env:"e2e_mayastor_version"`
 	KubectlPluginDir  string `yaml:"kubectlPluginDir" env:"e2e_kubectl_plugin_dir"`
+	// ControlPlane selects a fake control plane for developing tests without a cluster,
+	// "fake" uses the in-memory control plane, "fake-rest" uses the REST API against an in-process fake server
+	ControlPlane string `yaml:"controlPlane" env:"e2e_control_plane" env-default:""`
+	// WatchCallbackHost is the address of the test host used by the control plane to call
+	// the URLs of volume watches, by default it is the address used to reach the cluster nodes
+	WatchCallbackHost string `yaml:"watchCallbackHost" env:"e2e_watch_callback_host" env-default:""`
+	// WatchCallbackPort is the port on which the test host receives the volume watch callbacks, 0 selects any free port
+	WatchCallbackPort int `yaml:"watchCallbackPort" env:"e2e_watch_callback_port" env-default:"0"`
+	// WatchResyncInterval is the interval at which a watched volume is read, eg: "500ms",
+	// changes of the volume state are only observed when it is read,
+	// so transitions which are undone between two reads are missed
+	WatchResyncInterval string `yaml:"watchResyncInterval" env:"e2e_watch_resync_interval" env-default:"1s"`
+	// ControlPlaneCallTimeout bounds each control plane call, REST API request or kubectl plugin run
+	ControlPlaneCallTimeout string `yaml:"controlPlaneCallTimeout" env:"e2e_control_plane_call_timeout" env-default:"120s"`
+	// ControlPlaneUpgradeTimeout bounds each kubectl plugin upgrade run, in place of ControlPlaneCallTimeout
+	ControlPlaneUpgradeTimeout string `yaml:"controlPlaneUpgradeTimeout" env:"e2e_control_plane_upgrade_timeout" env-default:"1800s"`
+	// GrpcCallTimeout bounds each io-engine gRPC call which does not have a specific timeout
+	GrpcCallTimeout   string `yaml:"grpcCallTimeout" env:"e2e_grpc_call_timeout" env-default:"30s"`
 	MaasOauthApiToken string `yaml:"maasOauthApiToken" env:"e2e_maas_api_token"`
 	MaasEndpoint      string `yaml:"maasEndpoint" env:"e2e_maas_endpoint"`
 	ReplicatedEngine  bool   `yaml:"replicatedEngine" env:"replicatedEngine"`
@@ -218,9 +238,15 @@ type E2EConfig struct {
 	BeforeEachCheckAndRestart bool `yaml:"beforeEachCheckAndRestart" env-default:"false"`
 	// Fail  quickly after failure of a prior AfterEach, overrides BeforeEachCheckAndRestart
 	FailQuick bool `yaml:"failQuick" env-default:"false" env:"e2e_fail_quick"`
+	// Capture the state of the control plane before and after each test case, the captures
+	// and the differences between them are written to the test case logs path.
+	// Only the Mayastor control plane is captured, it requires MayastorVersion to be set.
+	CaptureControlPlaneState bool `yaml:"captureControlPlaneState" env-default:"false" env:"e2e_capture_control_plane_state"`
 
 	// Network interface , HZ: eth0 and GCP: ens4
 	NetworkInterface string `yaml:"networkInterface" env-default:"eth0" env:"e2e_default_network_interface"`
+	// Require a bearer token for e2e-agent requests, the token is provisioned by EnsureE2EAgent
+	E2EAgentAuth bool `yaml:"e2eAgentAuth" env-default:"false" env:"e2e_agent_auth"`
 
 	// Run configuration
 	ReportsDir string `yaml:"reportsDir" env:"e2e_reports_dir"`
@@ -231,6 +257,19 @@ type E2EConfig struct {
 
 	IOEngineNvmeTimeout int `yaml:"ioEngineNvmeTimeout" env-default:"0"`
 
+	// Selection of pool disks from the block devices reported by the control plane,
+	// used when pool devices are not configured for a node, see custom_resources.SelectPoolDisks
+	PoolDiskSelection struct {
+		// Enabled allows pools to be created on selected disks, otherwise creating a pool
+		// on a node without configured pool devices fails
+		Enabled bool `yaml:"enabled" env-default:"false" env:"e2e_pool_disk_selection"`
+		// MinSize is the minimum size of an eligible disk, eg: 10GiB
+		MinSize            string `yaml:"minSize" env-default:"1GiB" env:"e2e_pool_disk_min_size"`
+		ExcludeLoopDevices bool   `yaml:"excludeLoopDevices" env-default:"true" env:"e2e_pool_disk_exclude_loop"`
+		// MaxDisksPerNode limits the number of disks selected on a node, 0 selects all eligible disks
+		MaxDisksPerNode int `yaml:"maxDisksPerNode" env-default:"1" env:"e2e_pool_disk_max_per_node"`
+	} `yaml:"poolDiskSelection"`
+
 	// Individual Test parameters
 	PVCStress struct {
 		Replicas   int `yaml:"replicas" env-default:"2"`
@@ -665,6 +704,16 @@ func SetControlPlane(controlPlane string) bool {
 	return false
 }
 
+// ParseTimeout returns the duration of a timeout of the configuration, eg: "120s",
+// or fallback if the timeout is not set or is invalid
+func ParseTimeout(timeout string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
 // GetProductsSpecsMap return a maps of product specs keyed on product name
 func GetProductsSpecsMap() map[string]*ProductSpec {
 	_ = GetConfig()
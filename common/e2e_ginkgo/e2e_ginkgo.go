@@ -1,11 +1,15 @@
 package e2e_ginkgo
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/cpstate"
+	"github.com/openebs/openebs-e2e/common/e2e_agent"
 	"github.com/openebs/openebs-e2e/common/e2e_config"
 	"github.com/openebs/openebs-e2e/common/event"
 	"github.com/openebs/openebs-e2e/common/k8stest"
@@ -96,10 +100,13 @@ func BeforeEachCheck() error {
 			log.Log.Info("BeforeEachCheck: restart complete")
 		} else {
 			// resource check succeeded
+			captureStateBefore()
 			return resourceCheckError
 		}
 	}
 
+	captureStateBefore()
+
 	if resourceCheckError = k8stest.ResourceCheck(false); resourceCheckError != nil {
 		log.Log.Info("BeforeEachCheck failed", "error", resourceCheckError)
 		resourceCheckError = fmt.Errorf("%w; not running test case, k8s cluster is not \"clean\"!!! ", resourceCheckError)
@@ -234,12 +241,129 @@ func AfterEachCheck() error {
 		}
 	}
 
+	captureStateAfter()
+
+	// revert leaked faults first, they may prevent resources from being restored
+	faultErr := k8stest.CheckE2EAgentFaults()
+	if faultErr != nil {
+		log.Log.Info("AfterEachCheck", "error", faultErr)
+	}
+
 	// it is very likely that if the test case failed, resource check will fail,
 	// suppress generation of another support bundle since we've just generated
 	// the more useful support bundle.
-	return afterEachCheckResources(canGenSupportBundle)
+	if err := afterEachCheckResources(canGenSupportBundle); err != nil {
+		return err
+	}
+	return faultErr
 }
 
 func AfterEachK8sCheck() error {
 	return k8stest.ResourceK8sCheck()
 }
+
+// FollowNodeLogs collects the log of the nodes until the end of the current spec,
+// the log lines are attached to the spec report if the spec fails.
+func FollowNodeLogs(source e2e_agent.LogSource, nodes ...string) (k8stest.NodeLogCollectors, error) {
+	collectors, err := k8stest.StartNodeLogCollectors(nodes, e2e_agent.LogFollowRequest{Source: source})
+	if err != nil {
+		return nil, err
+	}
+	ginkgo.DeferCleanup(func() {
+		lines, err := collectors.Stop()
+		if err != nil {
+			log.Log.Info("FollowNodeLogs", "error", err)
+		}
+		if !ginkgo.CurrentSpecReport().Failed() {
+			return
+		}
+		for node, nodeLines := range lines {
+			ginkgo.AddReportEntry(fmt.Sprintf("%s log of node %s", source, node),
+				e2e_agent.FormatLogLines(nodeLines), ginkgo.ReportEntryVisibilityFailureOrVerbose)
+		}
+	})
+	return collectors, nil
+}
+
+// captureStateTimeout bounds the capture of the control plane state
+const captureStateTimeout = 2 * time.Minute
+
+// stateBefore is the control plane state captured by BeforeEachCheck
+var stateBefore *cpstate.State
+
+// captureStateEnabled returns true if the control plane state is to be captured,
+// the state of the Mayastor control plane, or of the fake control plane, can be captured
+func captureStateEnabled() bool {
+	cfg := e2e_config.GetConfig()
+	if !cfg.CaptureControlPlaneState {
+		return false
+	}
+	if cfg.ControlPlane != "" {
+		return true
+	}
+	return cfg.Product.ProductName == "mayastor" && cfg.MayastorVersion != ""
+}
+
+// captureState captures the control plane state, nil is returned if the capture is disabled
+// or fails, a failure to capture the state does not fail the test case
+func captureState(label string) (state *cpstate.State) {
+	if !captureStateEnabled() {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Log.Info("control plane state capture failed", "label", label, "panic", r)
+			state = nil
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), captureStateTimeout)
+	defer cancel()
+	captured := cpstate.Capture(ctx, label)
+	if len(captured.Errors) != 0 {
+		log.Log.Info("control plane state capture incomplete", "label", label, "errors", captured.Errors)
+	}
+	return &captured
+}
+
+// captureStateBefore captures the control plane state before the test case,
+// the capture is written to the test case logs path by captureStateAfter
+func captureStateBefore() {
+	stateBefore = captureState("before " + ginkgo.CurrentSpecReport().FullText())
+}
+
+// captureStateAfter captures the control plane state after the test case and
+// writes both captures and the differences between them to the test case logs path,
+// the differences are attached to the spec report if the spec failed
+func captureStateAfter() {
+	before := stateBefore
+	stateBefore = nil
+	if before == nil {
+		return
+	}
+	after := captureState("after " + ginkgo.CurrentSpecReport().FullText())
+	if after == nil {
+		return
+	}
+	diff := cpstate.Diff(*before, *after)
+	if !diff.Empty() && ginkgo.CurrentSpecReport().Failed() {
+		ginkgo.AddReportEntry("control plane state changes", diff.String(), ginkgo.ReportEntryVisibilityFailureOrVerbose)
+	}
+	logsPath, err := common.GetTestCaseLogsPath()
+	if err != nil {
+		log.Log.Info("failed to retrieve logs path", "error", err)
+		return
+	}
+	if err = os.MkdirAll(logsPath, 0755); err != nil {
+		log.Log.Info("Failed to create path", logsPath, err)
+		return
+	}
+	var errs common.ErrorAccumulator
+	errs.Accumulate(before.WriteFile(logsPath + "/cp-state-before.json"))
+	errs.Accumulate(after.WriteFile(logsPath + "/cp-state-after.json"))
+	errs.Accumulate(diff.WriteFile(logsPath + "/cp-state-diff.json"))
+	if err = errs.GetError(); err != nil {
+		log.Log.Info("failed to write control plane state", "error", err)
+		return
+	}
+	log.Log.Info("control plane state collected", "at", logsPath, "changes", len(diff.Changes))
+}
@@ -8,6 +8,7 @@ require (
 	github.com/onsi/ginkgo/v2 v2.11.0
 	github.com/onsi/gomega v1.27.10
 	github.com/openebs/openebs-e2e/apps v0.0.0
+	github.com/openebs/openebs-e2e/tools/e2e-agent/api v0.0.0
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/common v0.44.0
 	golang.org/x/exp v0.0.0-20220722155223-a9213eeb770e
@@ -75,3 +76,5 @@ require (
 )
 
 replace github.com/openebs/openebs-e2e/apps v0.0.0 => ../apps
+
+replace github.com/openebs/openebs-e2e/tools/e2e-agent/api v0.0.0 => ../tools/e2e-agent/api
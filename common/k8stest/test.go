@@ -51,6 +51,10 @@ func SetupK8sEnvBasic() error {
 			return fmt.Errorf("failed to set envvar e2e_product: %v", err)
 		}
 	}
+	// the e2e-agent may have been deployed with authentication before the test run
+	if err := LoadE2EAgentAuthToken(); err != nil {
+		logf.Log.Info("e2e-agent requests are not authenticated", "error", err)
+	}
 	// Check if gRPC calls are possible and store the result
 	// subsequent calls to mayastorClient.CanConnect retrieves
 	// the result.
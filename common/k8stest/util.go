@@ -894,9 +894,16 @@ func CreatePoolOnNodeAndWait(nodeName string, poolName string, timeoutsecs int)
 }
 
 // CreatePoolOnNode create pool on a specific node.
+// If no pool devices are configured for the node and e2e_config PoolDiskSelection
+// is enabled, an eligible disk reported by the control plane is selected,
+// see custom_resources.SelectPoolDisks.
 // No check is made on the status of pools
 func CreatePoolOnNode(nodeName string, poolName string) error {
 	disks, err := GetConfiguredNodePoolDevices(nodeName)
+	if err != nil && e2e_config.GetConfig().PoolDiskSelection.Enabled {
+		logf.Log.Info("no configured diskpool devices, selecting disks", "node", nodeName, "error", err)
+		disks, err = selectNodePoolDisks(nodeName)
+	}
 	if err == nil {
 		// NO check is made on the status of pools
 		var pool crtypes.DiskPool
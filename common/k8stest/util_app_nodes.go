@@ -0,0 +1,195 @@
+package k8stest
+
+// Utility functions for the app nodes registered with the control plane,
+// app nodes are the hosts of the NVMe initiators of volumes.
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane"
+	agent "github.com/openebs/openebs-e2e/common/e2e_agent"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// VolumeInitiator is an app node with an NVMe connection to a volume
+type VolumeInitiator struct {
+	AppNode   common.AppNode
+	Subsystem agent.NvmeSubsystem
+}
+
+// appNodeHost returns the address of the host of the app node
+func appNodeHost(appNode common.AppNode) string {
+	endpoint := appNode.State.Endpoint
+	if endpoint == "" {
+		endpoint = appNode.Spec.Endpoint
+	}
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return endpoint
+}
+
+// volumeNqn returns the NQN of the NVMe subsystem of a volume target from the device uri,
+// eg: nvmf://10.1.0.5:8420/nqn.2019-05.io.openebs:<uuid>
+func volumeNqn(deviceUri string) (string, error) {
+	nqnoffset := strings.Index(deviceUri, "nqn.")
+	if nqnoffset == -1 {
+		return "", fmt.Errorf("invalid nqn URI %v", deviceUri)
+	}
+	nqn := deviceUri[nqnoffset:]
+	if tailoffset := strings.Index(nqn, "?"); tailoffset != -1 {
+		nqn = nqn[:tailoffset]
+	}
+	return nqn, nil
+}
+
+// NvmePathTraddr returns the transport address of an NVMe path,
+// the address is of the form traddr=10.1.0.5,trsvcid=8420[,src_addr=...]
+func NvmePathTraddr(path agent.NvmePath) string {
+	for _, field := range strings.Split(path.Address, ",") {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, "traddr=") {
+			return strings.TrimPrefix(field, "traddr=")
+		}
+	}
+	return ""
+}
+
+// listAppNodeSubsystems returns the registered app nodes and the NVMe subsystems
+// connected on the host of each app node by app node id
+func listAppNodeSubsystems() ([]common.AppNode, map[string][]agent.NvmeSubsystem, error) {
+	appNodes, err := controlplane.ListAppNodes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list app nodes, error: %w", err)
+	}
+	subsystems := make(map[string][]agent.NvmeSubsystem)
+	for _, appNode := range appNodes {
+		host := appNodeHost(appNode)
+		subSysList, err := agent.NvmeListSubSys(host)
+		if err != nil {
+			return nil, nil, fmt.Errorf("nvme list-subsys failed on app node %s (%s), error: %w", appNode.Id, host, err)
+		}
+		subsystems[appNode.Id] = subSysList.Subsystems
+	}
+	return appNodes, subsystems, nil
+}
+
+// GetAppNodeSubsystems returns the NVMe subsystems connected on the host of
+// each registered app node by app node id, as listed by nvme list-subsys on the host
+func GetAppNodeSubsystems() (map[string][]agent.NvmeSubsystem, error) {
+	_, subsystems, err := listAppNodeSubsystems()
+	return subsystems, err
+}
+
+// GetVolumeInitiators returns the registered app nodes on which the NVMe subsystem of the volume is connected
+func GetVolumeInitiators(volUuid string) ([]VolumeInitiator, error) {
+	msv, err := GetMSV(volUuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get msv %s, error: %w", volUuid, err)
+	}
+	nqn, err := volumeNqn(msv.State.Target.DeviceUri)
+	if err != nil {
+		return nil, fmt.Errorf("volume %s is not published, error: %w", volUuid, err)
+	}
+	appNodes, subsystems, err := listAppNodeSubsystems()
+	if err != nil {
+		return nil, err
+	}
+	var initiators []VolumeInitiator
+	for _, appNode := range appNodes {
+		for _, subsys := range subsystems[appNode.Id] {
+			if subsys.NQN == nqn {
+				initiators = append(initiators, VolumeInitiator{AppNode: appNode, Subsystem: subsys})
+			}
+		}
+	}
+	return initiators, nil
+}
+
+// VerifyVolumeInitiators verifies that the volume is connected on exactly the app nodes,
+// and that on each app node the connection has a live path to the current target of the volume,
+// eg: that after a failover of the target the initiator has been re-pointed to the new target
+func VerifyVolumeInitiators(volUuid string, appNodeIds ...string) error {
+	initiators, err := GetVolumeInitiators(volUuid)
+	if err != nil {
+		return err
+	}
+	targetIp, err := GetNexusNodeIp(volUuid)
+	if err != nil {
+		return err
+	}
+
+	var connected []string
+	var errs common.ErrorAccumulator
+	for _, initiator := range initiators {
+		connected = append(connected, initiator.AppNode.Id)
+		live := false
+		for _, path := range initiator.Subsystem.Paths {
+			if path.State != "live" || NvmePathTraddr(path) != targetIp {
+				continue
+			}
+			if path.ANAState != "" && path.ANAState != "optimized" {
+				continue
+			}
+			live = true
+		}
+		if !live {
+			logf.Log.Info("VerifyVolumeInitiators", "appNode", initiator.AppNode.Id, "paths", initiator.Subsystem.Paths)
+			errs.Accumulate(fmt.Errorf("app node %s has no live path to target %s of volume %s",
+				initiator.AppNode.Id, targetIp, volUuid))
+		}
+	}
+
+	expected := append([]string{}, appNodeIds...)
+	sort.Strings(expected)
+	sort.Strings(connected)
+	if strings.Join(expected, ",") != strings.Join(connected, ",") {
+		errs.Accumulate(fmt.Errorf("volume %s is connected on app nodes %v, expected %v", volUuid, connected, expected))
+	}
+	return errs.GetError()
+}
+
+// GetStaleAppNodes returns the registered app nodes which are not online,
+// or whose host is not a node of the cluster
+func GetStaleAppNodes() ([]common.AppNode, error) {
+	appNodes, err := controlplane.ListAppNodes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list app nodes, error: %w", err)
+	}
+	nodeLocs, err := getNodeLocs()
+	if err != nil {
+		return nil, err
+	}
+	hosts := make(map[string]bool)
+	for _, nodeLoc := range nodeLocs {
+		hosts[nodeLoc.IPAddress] = true
+	}
+	var stale []common.AppNode
+	for _, appNode := range appNodes {
+		if appNode.State.Status != controlplane.NodeStateOnline() || !hosts[appNodeHost(appNode)] {
+			stale = append(stale, appNode)
+		}
+	}
+	return stale, nil
+}
+
+// VerifyNoStaleAppNodes verifies that the registered app nodes are online cluster nodes,
+// eg: that the app node of a removed node has been deregistered
+func VerifyNoStaleAppNodes() error {
+	stale, err := GetStaleAppNodes()
+	if err != nil {
+		return err
+	}
+	if len(stale) != 0 {
+		var ids []string
+		for _, appNode := range stale {
+			ids = append(ids, fmt.Sprintf("%s(%s %s)", appNode.Id, appNodeHost(appNode), appNode.State.Status))
+		}
+		return fmt.Errorf("stale app nodes: %s", strings.Join(ids, ", "))
+	}
+	return nil
+}
@@ -192,41 +192,49 @@ func DeleteAllPvs() (int, error) {
 func DeleteAllMsvs() (int, error) {
 	// If after deleting PVCs and PVs Mayastor volumes are leftover
 	// try cleaning them up explicitly
-	var msvs []common.MayastorVolume
+	var uuids []string
 	var err error
 	if !controlplane.CanDeleteMsv() {
 		return 0, nil
 	}
-	msvs, err = ListMsvs()
+	// collect the volumes before deleting them, deleting whilst
+	// iterating over the pages of volumes would skip volumes
+	err = controlplane.ForEachMsv(func(msv common.MayastorVolume) error {
+		uuids = append(uuids, msv.Spec.Uuid)
+		return nil
+	})
 	if err != nil {
 		// This function may be called by AfterSuite by uninstall test so listing MSVs may fail correctly
 		logf.Log.Info("DeleteAllMsvs: list MSVs failed.", "Error", err)
 		return 0, err
 	}
-	if err == nil && msvs != nil && len(msvs) != 0 {
-		for _, msv := range msvs {
-			logf.Log.Info("DeleteAllMsvs: deleting MayastorVolume", "MayastorVolume", msv.Spec.Uuid)
-			if delErr := DeleteMsv(msv.Spec.Uuid); delErr != nil {
-				logf.Log.Info("DeleteAllMsvs: failed deleting MayastorVolume", "MayastorVolume", msv.Spec.Uuid, "error", delErr)
-			}
+	for _, uuid := range uuids {
+		logf.Log.Info("DeleteAllMsvs: deleting MayastorVolume", "MayastorVolume", uuid)
+		// the volume may have been deleted since it was listed
+		if delErr := controlplane.IgnoreNotFound(DeleteMsv(uuid)); delErr != nil {
+			logf.Log.Info("DeleteAllMsvs: failed deleting MayastorVolume", "MayastorVolume", uuid, "error", delErr)
 		}
 	}
 
 	// Wait 2 minutes for resources to be deleted
+	var found bool
 	for attempts := 0; attempts < 120; attempts++ {
-		msvs, err = ListMsvs()
-		if err == nil && len(msvs) == 0 {
+		found, err = controlplane.CheckForMsvs()
+		if err == nil && !found {
 			break
 		}
 		time.Sleep(1 * time.Second)
 	}
 
-	if msvs != nil {
-		logf.Log.Info("DeleteAllMsvs:", "number of MayastorVolumes", len(msvs), "error", err)
-		return len(msvs), err
+	numMsvs := 0
+	if found {
+		err = controlplane.ForEachMsv(func(msv common.MayastorVolume) error {
+			numMsvs++
+			return nil
+		})
 	}
-	logf.Log.Info("DeleteAllMsvs:", "error", err)
-	return 0, err
+	logf.Log.Info("DeleteAllMsvs:", "number of MayastorVolumes", numMsvs, "error", err)
+	return numMsvs, err
 }
 
 /*
@@ -0,0 +1,65 @@
+package k8stest
+
+import (
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common/e2e_agent"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// kubeletPodsDir is the directory of the kubelet holding the volumes of the pods
+const kubeletPodsDir = "/var/lib/kubelet/pods"
+
+// podVolumeHostPath returns the IP address of the node hosting the pod
+// and the host path of the mount point of the pvc volume of the pod
+func podVolumeHostPath(podName string, nameSpace string, pvcName string) (string, string, error) {
+	pod, err := GetPod(podName, nameSpace)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get pod %s, error: %v", podName, err)
+	}
+	pvc, err := GetPVC(pvcName, nameSpace)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get pvc %s, error: %v", pvcName, err)
+	}
+	if pvc.Spec.VolumeName == "" {
+		return "", "", fmt.Errorf("pvc %s is not bound", pvcName)
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvcName {
+			path := fmt.Sprintf("%s/%s/volumes/kubernetes.io~csi/%s/mount", kubeletPodsDir, pod.UID, pvc.Spec.VolumeName)
+			return pod.Status.HostIP, path, nil
+		}
+	}
+	return "", "", fmt.Errorf("pod %s does not mount pvc %s", podName, pvcName)
+}
+
+// WriteDatasetToPodVolume writes a deterministic dataset to the filesystem volume
+// of the pvc mounted by the pod, and returns its manifest
+func WriteDatasetToPodVolume(podName string, nameSpace string, pvcName string, spec e2e_agent.DatasetSpec) (e2e_agent.DatasetManifest, error) {
+	nodeIp, path, err := podVolumeHostPath(podName, nameSpace, pvcName)
+	if err != nil {
+		return e2e_agent.DatasetManifest{}, err
+	}
+	manifest, err := e2e_agent.WriteDataset(nodeIp, path, spec)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to write dataset to pvc %s of pod %s, error: %v", pvcName, podName, err)
+	}
+	logf.Log.Info("Wrote dataset", "pod", podName, "pvc", pvcName, "entries", len(manifest.Entries))
+	return manifest, nil
+}
+
+// VerifyDatasetOnPodVolume verifies the dataset on the filesystem volume of the pvc
+// mounted by the pod against its manifest, returning an error if they differ
+func VerifyDatasetOnPodVolume(podName string, nameSpace string, pvcName string, manifest e2e_agent.DatasetManifest) (e2e_agent.DatasetVerifyReport, error) {
+	nodeIp, path, err := podVolumeHostPath(podName, nameSpace, pvcName)
+	if err != nil {
+		return e2e_agent.DatasetVerifyReport{}, err
+	}
+	report, err := e2e_agent.VerifyDataset(nodeIp, path, manifest)
+	if err != nil {
+		return report, fmt.Errorf("failed to verify dataset on pvc %s of pod %s, error: %v", pvcName, podName, err)
+	}
+	logf.Log.Info("Verified dataset", "pod", podName, "pvc", pvcName, "verified", report.Verified, "mismatches", report.Mismatches)
+	return report, report.Err()
+}
@@ -2,14 +2,94 @@ package k8stest
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/e2e_agent"
+	"github.com/openebs/openebs-e2e/common/e2e_config"
 	"github.com/openebs/openebs-e2e/common/locations"
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// e2eAgentCoreRoutes are the e2e-agent routes used by the test library for every
+// test suite, eg: e2e_ginkgo.AfterEachCheck clears the faults on all nodes,
+// and those used by the library to access replicas and devices, eg: ChecksumReplica
+var e2eAgentCoreRoutes = []string{
+	api.RouteCapabilities,
+	api.RouteListFaults,
+	api.RouteClearFaults,
+	api.RouteNvmeConnect,
+	api.RouteNvmeConnectWithHostId,
+	api.RouteNvmeDisconnect,
+	api.RouteNvmeList,
+	api.RouteNvmeListSubSys,
+	api.RouteListReservation,
+	api.RouteListDevice,
+	api.RouteLsblk,
+	api.RouteStartJob,
+	api.RouteGetJob,
+	api.RouteCancelJob,
+}
+
+// e2eAgentRequirements maps the e2e-agent routes required by the test suite to their schema version,
+// by default only the core routes are required, see SetE2EAgentRequirements.
+var e2eAgentRequirements = routeRequirements()
+
+// routeRequirements returns the core routes and the given routes with the schema versions
+// known to the test library
+func routeRequirements(routes ...string) map[string]int {
+	requirements := make(map[string]int)
+	for _, route := range append(append([]string{}, e2eAgentCoreRoutes...), routes...) {
+		requirements[route] = api.RouteSchemaVersions[route]
+	}
+	return requirements
+}
+
+const e2eAgentLabel = "app=e2e-rest-agent"
+
+// e2eAgentPodUIDs returns the UIDs of the e2e-agent pods, including terminating pods
+func e2eAgentPodUIDs() (map[string]bool, error) {
+	pods, err := gTestEnv.KubeInt.CoreV1().Pods(common.NSE2EAgent).List(
+		context.TODO(),
+		metaV1.ListOptions{LabelSelector: e2eAgentLabel},
+	)
+	if err != nil {
+		return nil, err
+	}
+	uids := make(map[string]bool)
+	for _, pod := range pods.Items {
+		uids[string(pod.UID)] = true
+	}
+	return uids, nil
+}
+
+// e2eAgentPodsReplaced returns true if none of the e2e-agent pods is one of the deleted pods,
+// the availability of the daemonSet counts the deleted pods until they have terminated
+func e2eAgentPodsReplaced(deleted map[string]bool) bool {
+	if len(deleted) == 0 {
+		return true
+	}
+	uids, err := e2eAgentPodUIDs()
+	if err != nil {
+		return false
+	}
+	for uid := range uids {
+		if deleted[uid] {
+			return false
+		}
+	}
+	return true
+}
+
 func e2eReadyPodCount() int {
 	daemonSet, err := gTestEnv.KubeInt.AppsV1().DaemonSets(common.NSE2EAgent).Get(
 		context.TODO(),
@@ -35,11 +115,26 @@ func EnsureE2EAgent() (bool, error) {
 		return false, err
 	}
 
+	created, err := ensureE2EAgentAuth()
+	if err != nil {
+		return false, err
+	}
+
 	nodes, _ := GetIOEngineNodes()
 	instances := len(nodes)
 
-	if e2eReadyPodCount() == instances {
-		return true, nil
+	var deleted map[string]bool
+	if created && e2eReadyPodCount() > 0 {
+		// the token is passed to the e2e-agent when its pods start
+		logf.Log.Info("Restarting e2e-agent to enable authentication")
+		if deleted, err = e2eAgentPodUIDs(); err != nil {
+			return false, err
+		}
+		if err = DeletePodsByLabel(e2eAgentLabel, common.NSE2EAgent); err != nil {
+			return false, err
+		}
+	} else if e2eReadyPodCount() == instances {
+		return true, CheckE2EAgentCapabilities()
 	}
 
 	err = KubeCtlApplyYaml("e2e-agent.yaml", locations.GetE2EAgentPath())
@@ -49,9 +144,132 @@ func EnsureE2EAgent() (bool, error) {
 	}
 	for ix := 0; ix < count && !ready; ix++ {
 		time.Sleep(time.Duration(sleepTime) * time.Second)
-		ready = e2eReadyPodCount() == instances
+		ready = e2eReadyPodCount() == instances && e2eAgentPodsReplaced(deleted)
+	}
+	if ready {
+		err = CheckE2EAgentCapabilities()
+	}
+	return ready, err
+}
+
+// ensureE2EAgentAuth creates the e2e-agent bearer token secret if authentication
+// is required by the configuration, and passes the token, if any, to the e2e-agent client.
+// Returns true if the secret was created.
+func ensureE2EAgentAuth() (bool, error) {
+	created := false
+	secrets := gTestEnv.KubeInt.CoreV1().Secrets(common.NSE2EAgent)
+	secret, err := secrets.Get(context.TODO(), api.AuthSecretName, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		if !e2e_config.GetConfig().E2EAgentAuth {
+			e2e_agent.SetAuthToken("")
+			return false, nil
+		}
+		token := make([]byte, 32)
+		if _, err = rand.Read(token); err != nil {
+			return false, err
+		}
+		secret, err = secrets.Create(context.TODO(), &coreV1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      api.AuthSecretName,
+				Namespace: common.NSE2EAgent,
+			},
+			Data: map[string][]byte{
+				api.AuthSecretKey: []byte(hex.EncodeToString(token)),
+			},
+		}, metaV1.CreateOptions{})
+		created = err == nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to provision e2e-agent secret %s, error: %v", api.AuthSecretName, err)
+	}
+	e2e_agent.SetAuthToken(string(secret.Data[api.AuthSecretKey]))
+	return created, nil
+}
+
+// LoadE2EAgentAuthToken passes the bearer token of the e2e-agent secret, if it exists,
+// to the e2e-agent client. It is called when the test environment is set up, so that
+// suites run against a cluster with the e2e-agent already deployed authenticate
+// without provisioning the e2e-agent, see EnsureE2EAgent.
+func LoadE2EAgentAuthToken() error {
+	secret, err := gTestEnv.KubeInt.CoreV1().Secrets(common.NSE2EAgent).Get(context.TODO(), api.AuthSecretName, metaV1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		e2e_agent.SetAuthToken("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get e2e-agent secret %s, error: %v", api.AuthSecretName, err)
+	}
+	e2e_agent.SetAuthToken(string(secret.Data[api.AuthSecretKey]))
+	return nil
+}
+
+// SetE2EAgentRequirements declares the e2e-agent routes used by the test suite, these and the
+// core routes are verified by EnsureE2EAgent with the schema versions known to the test library.
+func SetE2EAgentRequirements(routes ...string) {
+	e2eAgentRequirements = routeRequirements(routes...)
+}
+
+// CheckE2EAgentCapabilities verifies that the e2e-agent on every node
+// supports the routes required by the test suite
+func CheckE2EAgentCapabilities() error {
+	nodes, err := GetIOEngineNodes()
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		caps, err := e2e_agent.GetCapabilities(node.IPAddress)
+		if err != nil {
+			return fmt.Errorf("failed to get e2e-agent capabilities on node %s, the e2e-agent image may predate capability negotiation, error: %v",
+				node.NodeName, err)
+		}
+		if e2e_config.GetConfig().E2EAgentAuth && !caps.AuthRequired {
+			return fmt.Errorf("e2e-agent on node %s does not require authentication, restart the e2e-agent pods",
+				node.NodeName)
+		}
+		unsatisfied := caps.Unsatisfied(e2eAgentRequirements)
+		if len(unsatisfied) != 0 {
+			return fmt.Errorf("e2e-agent version %s on node %s does not satisfy the test suite requirements, update the e2e-agent image: %s",
+				caps.Version, node.NodeName, strings.Join(unsatisfied, "; "))
+		}
+		logf.Log.Info("e2e-agent capabilities satisfied", "node", node.NodeName, "version", caps.Version)
+	}
+	return nil
+}
+
+// CheckE2EAgentFaults reverts the faults injected through the e2e-agent
+// which have not been reverted by the test, and fails if there were any.
+// The check is skipped on nodes whose e2e-agent is not reachable, does not
+// serve the fault routes or does not accept the bearer token.
+func CheckE2EAgentFaults() error {
+	if e2eReadyPodCount() <= 0 {
+		return nil
+	}
+	nodeLocs, err := getNodeLocs()
+	if err != nil {
+		return err
+	}
+	var leaks []string
+	for _, nodeLoc := range nodeLocs {
+		faults, err := e2e_agent.ClearFaults(nodeLoc.IPAddress)
+		if err != nil {
+			if e2e_agent.IsUnsupported(err) || e2e_agent.IsUnauthorized(err) {
+				logf.Log.Info("e2e-agent fault check skipped", "node", nodeLoc.NodeName, "error", err)
+				continue
+			}
+			if e2e_agent.IsAgentReachable(nodeLoc.IPAddress) != nil {
+				logf.Log.Info("e2e-agent not reachable, skipping fault check", "node", nodeLoc.NodeName)
+				continue
+			}
+			return fmt.Errorf("failed to clear e2e-agent faults on node %s, error: %v", nodeLoc.NodeName, err)
+		}
+		for _, fault := range faults {
+			leaks = append(leaks, fmt.Sprintf("%s on node %s", fault.Id, nodeLoc.NodeName))
+		}
+	}
+	if len(leaks) != 0 {
+		return fmt.Errorf("faults injected by the test were not reverted: %s", strings.Join(leaks, ", "))
 	}
-	return ready, nil
+	return nil
 }
 
 func UninstallE2eAgent() error {
@@ -353,21 +353,17 @@ func GetIOEngineHostNameLabel() (string, error) {
 	return "", nil
 }
 
-// ZeroNodeHugePages sets huge pages to 0 on current node
-func ZeroNodeHugePages(nodeName string) (string, error) {
+// ZeroNodeHugePages sets huge pages to 0 on current node, returning the number of huge pages set
+func ZeroNodeHugePages(nodeName string) (int, error) {
 	ip, err := GetNodeIPAddress(nodeName)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
-	output, err := e2e_agent.ZeroHugePages(*ip)
+	pages, err := e2e_agent.ZeroHugePages(*ip)
 	if err != nil {
-		return "", err
-	}
-	msg, errCode, err := e2e_agent.UnwrapResult(output)
-	if err != nil || errCode != 0 {
-		return "", fmt.Errorf("errCode=%v ; err=%v", errCode, err)
+		return 0, err
 	}
-	return msg, nil
+	return pages.NrHugePages, nil
 }
 
 // ListAllNonMsnNodes list all nodes without io-engine label and without master node
@@ -529,3 +525,42 @@ func DisableNetworkInterfaceOnNode(node string) error {
 	logf.Log.Info("Disable network interface", "node", node, "output", out, "interface", iface)
 	return nil
 }
+
+// ApplyNetworkEmulationOnNode degrades the traffic sent from node to the peer nodes,
+// the peers of emulation are replaced by the IP addresses of peerNodes.
+// The emulation is one-way, to degrade the traffic in both directions
+// apply it on the peer nodes too, with node as their peer.
+func ApplyNetworkEmulationOnNode(node string, peerNodes []string, emulation e2e_agent.NetworkEmulation) error {
+	nodeIp, err := GetNodeIPAddress(node)
+	if err != nil {
+		return fmt.Errorf("failed to get node %s ip, error: %v", node, err)
+	}
+	emulation.Peers = nil
+	for _, peer := range peerNodes {
+		peerIp, err := GetNodeIPAddress(peer)
+		if err != nil {
+			return fmt.Errorf("failed to get node %s ip, error: %v", peer, err)
+		}
+		emulation.Peers = append(emulation.Peers, *peerIp)
+	}
+	err = e2e_agent.ApplyNetworkEmulation(*nodeIp, emulation)
+	if err != nil {
+		return fmt.Errorf("failed to apply network emulation on node %s, error: %v", node, err)
+	}
+	logf.Log.Info("Applied network emulation", "node", node, "peers", peerNodes, "emulation", emulation)
+	return nil
+}
+
+// RemoveNetworkEmulationOnNode removes the network emulation applied on node
+func RemoveNetworkEmulationOnNode(node string) error {
+	nodeIp, err := GetNodeIPAddress(node)
+	if err != nil {
+		return fmt.Errorf("failed to get node %s ip, error: %v", node, err)
+	}
+	err = e2e_agent.RemoveNetworkEmulation(*nodeIp)
+	if err != nil {
+		return fmt.Errorf("failed to remove network emulation on node %s, error: %v", node, err)
+	}
+	logf.Log.Info("Removed network emulation", "node", node)
+	return nil
+}
@@ -0,0 +1,63 @@
+package k8stest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/common/e2e_agent"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// NodeLogCollectors collect a log on several nodes, keyed by node name
+type NodeLogCollectors map[string]*e2e_agent.LogCollector
+
+// StartNodeLogCollectors starts collecting the log on the nodes,
+// the log is followed on all nodes once it returns
+func StartNodeLogCollectors(nodes []string, req e2e_agent.LogFollowRequest) (NodeLogCollectors, error) {
+	collectors := make(NodeLogCollectors)
+	for _, node := range nodes {
+		nodeIp, err := GetNodeIPAddress(node)
+		if err != nil {
+			_, _ = collectors.Stop()
+			return nil, fmt.Errorf("failed to get node %s ip, error: %v", node, err)
+		}
+		collector, err := e2e_agent.StartLogCollector(*nodeIp, req)
+		if err != nil {
+			_, _ = collectors.Stop()
+			return nil, fmt.Errorf("failed to follow %s log on node %s, error: %v", req.Source, node, err)
+		}
+		collectors[node] = collector
+	}
+	logf.Log.Info("Started log collectors", "nodes", nodes, "request", req)
+	return collectors, nil
+}
+
+// Matching returns the lines collected so far which match re, keyed by node name
+func (c NodeLogCollectors) Matching(re *regexp.Regexp) map[string][]e2e_agent.LogLine {
+	matching := make(map[string][]e2e_agent.LogLine)
+	for node, collector := range c {
+		if lines := collector.Matching(re); len(lines) != 0 {
+			matching[node] = lines
+		}
+	}
+	return matching
+}
+
+// Stop stops collecting and returns the lines collected, keyed by node name
+func (c NodeLogCollectors) Stop() (map[string][]e2e_agent.LogLine, error) {
+	var errs []string
+	lines := make(map[string][]e2e_agent.LogLine)
+	for node, collector := range c {
+		var err error
+		lines[node], err = collector.Stop()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("node %s: %v", node, err))
+		}
+	}
+	if len(errs) != 0 {
+		return lines, fmt.Errorf("log collection failed, %s", strings.Join(errs, ", "))
+	}
+	return lines, nil
+}
@@ -48,6 +48,21 @@ func GetConfiguredNodePoolDevices(nodeName string) ([]string, error) {
 	return devices, err
 }
 
+// selectNodePoolDisks returns an eligible pool disk of the node, selected
+// from the block devices reported by the control plane using the configured filter
+func selectNodePoolDisks(nodeName string) ([]string, error) {
+	filter, err := custom_resources.ConfiguredPoolDiskFilter()
+	if err != nil {
+		return nil, err
+	}
+	filter.MaxDisks = 1
+	disks, err := custom_resources.SelectNodePoolDisks(nodeName, filter)
+	if err == nil && len(disks) == 0 {
+		err = fmt.Errorf("no eligible pool disks on node %s", nodeName)
+	}
+	return disks, err
+}
+
 // CreateConfiguredPools (re)create pools as defined by the configuration.
 // No check is made on the status of pools
 func CreateConfiguredPools() error {
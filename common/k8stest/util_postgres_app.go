@@ -337,11 +337,11 @@ func SetupPostgresEnvironment() ([]coreV1.Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		msg, err := ZeroNodeHugePages(node.Name)
+		pages, err := ZeroNodeHugePages(node.Name)
 		if err != nil {
 			return nil, err
 		}
-		logf.Log.Info("message from e2e-agent", "msg", msg)
+		logf.Log.Info("zeroed huge pages", "node", node.Name, "nr_hugepages", pages)
 		unlabeledNodes = append(unlabeledNodes, node)
 	}
 	return unlabeledNodes, nil
@@ -0,0 +1,143 @@
+package k8stest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/e2e_agent"
+	"github.com/openebs/openebs-e2e/common/e2e_config"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// EngineContainer is a per node container of the product targeted by process faults
+type EngineContainer string
+
+const (
+	IOEngineContainer EngineContainer = "io-engine"
+	CsiNodeContainer  EngineContainer = "csi-node"
+	LvmNodeContainer  EngineContainer = "lvm-node"
+	ZfsNodeContainer  EngineContainer = "zfs-node"
+)
+
+// engineContainerSpec returns the namespace, the pod label selector and the
+// container name of the engine container from the product configuration
+func engineContainerSpec(container EngineContainer) (string, string, string, error) {
+	product := e2e_config.GetConfig().Product
+	switch container {
+	case IOEngineContainer:
+		return common.NSMayastor(), product.PodLabelKey + "=" + product.IOEnginePodLabelValue, product.IOEngineContainerName, nil
+	case CsiNodeContainer:
+		return common.NSMayastor(), "app=" + product.CsiNodeServiceAppLabel, product.CsiNodeContainerName, nil
+	case LvmNodeContainer:
+		return common.NSOpenEBS(), product.LocalEngineComponentPodLabelKey + "=" + product.LvmEngineComponentDsPodLabelValue, product.LvmEnginePluginContainerName, nil
+	case ZfsNodeContainer:
+		return common.NSOpenEBS(), product.LocalEngineComponentPodLabelKey + "=" + product.ZfsEngineComponentDsPodLabelValue, product.ZfsEnginePluginContainerName, nil
+	}
+	return "", "", "", fmt.Errorf("unknown engine container %s", container)
+}
+
+// engineContainerOnNode returns the IP address of the node and the id
+// of the running engine container on the node
+func engineContainerOnNode(container EngineContainer, nodeName string) (string, string, error) {
+	namespace, selector, containerName, err := engineContainerSpec(container)
+	if err != nil {
+		return "", "", err
+	}
+	nodeIp, err := GetNodeIPAddress(nodeName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get node %s ip, error: %v", nodeName, err)
+	}
+	pods, err := gTestEnv.KubeInt.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{
+		LabelSelector: selector,
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list pods with label %s on node %s, error: %v", selector, nodeName, err)
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == containerName && status.State.Running != nil && status.ContainerID != "" {
+				return *nodeIp, status.ContainerID, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("no running container %s with label %s on node %s", containerName, selector, nodeName)
+}
+
+// PauseEngineContainer stops the processes of the engine container on the node
+// with SIGSTOP, the e2e-agent continues them once duration has elapsed
+func PauseEngineContainer(container EngineContainer, nodeName string, duration time.Duration) error {
+	nodeIp, containerId, err := engineContainerOnNode(container, nodeName)
+	if err != nil {
+		return err
+	}
+	resp, err := e2e_agent.PauseProcess(nodeIp, e2e_agent.ProcessTarget{ContainerId: containerId}, duration)
+	if err != nil {
+		return fmt.Errorf("failed to pause %s on node %s, error: %v", container, nodeName, err)
+	}
+	logf.Log.Info("Paused", "container", container, "node", nodeName, "pids", resp.Pids, "duration", duration)
+	return nil
+}
+
+// ResumeEngineContainer continues the processes of the engine container on the node
+// stopped by PauseEngineContainer before the duration has elapsed
+func ResumeEngineContainer(container EngineContainer, nodeName string) error {
+	nodeIp, containerId, err := engineContainerOnNode(container, nodeName)
+	if err != nil {
+		return err
+	}
+	if err = e2e_agent.ResumeProcess(nodeIp, e2e_agent.ProcessTarget{ContainerId: containerId}); err != nil {
+		return fmt.Errorf("failed to resume %s on node %s, error: %v", container, nodeName, err)
+	}
+	return nil
+}
+
+// ApplyEngineCgroupPressure limits the engine container on the node to cpuPercent
+// of one CPU and throttles its memory above memoryHighBytes, a limit of 0 is left unchanged.
+// The previous limits are restored by RemoveEngineCgroupPressure or when the lease expires.
+func ApplyEngineCgroupPressure(container EngineContainer, nodeName string, cpuPercent int, memoryHighBytes int64, lease time.Duration) error {
+	nodeIp, containerId, err := engineContainerOnNode(container, nodeName)
+	if err != nil {
+		return err
+	}
+	pressure := e2e_agent.CgroupPressure{
+		ProcessTarget:   e2e_agent.ProcessTarget{ContainerId: containerId},
+		CpuPercent:      cpuPercent,
+		MemoryHighBytes: memoryHighBytes,
+	}
+	if _, err = e2e_agent.ApplyCgroupPressure(nodeIp, pressure, lease); err != nil {
+		return fmt.Errorf("failed to apply cgroup pressure to %s on node %s, error: %v", container, nodeName, err)
+	}
+	return nil
+}
+
+// RemoveEngineCgroupPressure restores the limits of the engine container on the node
+func RemoveEngineCgroupPressure(container EngineContainer, nodeName string) error {
+	nodeIp, containerId, err := engineContainerOnNode(container, nodeName)
+	if err != nil {
+		return err
+	}
+	if err = e2e_agent.RemoveCgroupPressure(nodeIp, containerId); err != nil {
+		return fmt.Errorf("failed to remove cgroup pressure from %s on node %s, error: %v", container, nodeName, err)
+	}
+	return nil
+}
+
+// OomKillEngineContainer makes the kernel OOM kill the processes of the engine
+// container on the node, kubernetes restarts the container
+func OomKillEngineContainer(container EngineContainer, nodeName string) error {
+	nodeIp, containerId, err := engineContainerOnNode(container, nodeName)
+	if err != nil {
+		return err
+	}
+	resp, err := e2e_agent.OomKillContainer(nodeIp, containerId)
+	if err != nil {
+		return fmt.Errorf("failed to oom kill %s on node %s, error: %v", container, nodeName, err)
+	}
+	logf.Log.Info("OOM killed", "container", container, "node", nodeName, "pids", resp.Pids)
+	return nil
+}
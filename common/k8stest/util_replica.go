@@ -1,7 +1,7 @@
 package k8stest
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,6 +22,9 @@ import (
 // var productName = e2e_config.GetConfig().Product.ProductName
 var nvmeControllerModel = e2e_config.GetConfig().Product.NvmeControllerModel
 
+// checksumReplicaTimeout bounds the time taken to checksum a replica device
+const checksumReplicaTimeout = 30 * time.Minute
+
 // use the e2e-agent running on each non-nexus node:
 //
 //	for each non-nexus replica node
@@ -101,73 +104,6 @@ func CompareReplicas(nexusIP string, replica_1_IP string, replica_2_IP string, v
 	return nil
 }
 
-// for json deserialization of output from nvme list-subsys
-type nvmeListSubsystemEntry struct {
-	Name  string
-	NQN   string
-	Paths []map[string]string
-}
-
-func (n *nvmeListSubsystemEntry) String() string {
-	return fmt.Sprintf("Name:%s, NQN:%s, Paths:%v", n.Name, n.NQN, n.Paths)
-}
-
-// for json deserialization of output from nvme list
-type nvmeListDeviceEntry struct {
-	DevicePath   string
-	SerialNumber string
-	ModelNumber  string
-}
-
-func (n *nvmeListDeviceEntry) String() string {
-	return fmt.Sprintf("DevicePath:%s, ModelNumber:%s SerialNumber:%s", n.DevicePath, n.ModelNumber, n.SerialNumber)
-}
-
-// for json deserialization of output from nvme resv report
-type regctlextEntry struct {
-	Hostid string
-}
-
-func (r *regctlextEntry) String() string {
-	return fmt.Sprintf("hostid: %s", r.Hostid)
-}
-
-// for json deserialization of output from nvme resv report
-type reservationEntry struct {
-	Regctlext []regctlextEntry
-}
-
-func (r *reservationEntry) String() string {
-	s := "regctlext: ["
-	for _, re := range r.Regctlext {
-		s += re.String() + ", "
-	}
-	return s + "]"
-}
-
-// trimForJson simplistic removal of extraneous characters surrounding JSON formatted data,
-// good enough for now.
-func trimForJson(jstr string) string {
-	var start, end int
-	var endChar uint8
-	for start = 0; start < len(jstr); start++ {
-		if jstr[start] == '{' {
-			endChar = '}'
-			break
-		}
-		if jstr[start] == '[' {
-			endChar = ']'
-			break
-		}
-	}
-	for end = len(jstr) - 1; end > 0; end-- {
-		if jstr[end] == endChar {
-			break
-		}
-	}
-	return jstr[start : end+1]
-}
-
 func getNvmeDevice(initiatorIP string, maxRetries int, targetNqn string) (bool, string, string) {
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
@@ -184,45 +120,32 @@ func getNvmeDevice(initiatorIP string, maxRetries int, targetNqn string) (bool,
 	for retryCount < 1 && !found {
 		retryCount++
 
-		output, err := agent.NvmeList(initiatorIP)
-		if output == "" || err != nil {
-			logf.Log.Info("nvme list failed", "output", output, "err", err)
-			continue
-		}
-		output = trimForJson(output)
-		list := make(map[string][]nvmeListDeviceEntry)
-		if err = json.Unmarshal([]byte(output), &list); err != nil {
-			logf.Log.Info("Failed to unmarshal target", "output", output)
+		list, err := agent.NvmeList(initiatorIP)
+		if err != nil {
+			logf.Log.Info("nvme list failed", "err", err)
 			continue
 		}
 		logf.Log.Info("nvme", "list", list)
 
 		nqnPathNames := make(map[string][]string)
-		output, err = agent.NvmeListSubSys(initiatorIP)
-		if output == "" || err != nil {
-			logf.Log.Info("nvme list-subsys failed", "output", output, "err", err)
+		subSysList, err := agent.NvmeListSubSys(initiatorIP)
+		if err != nil {
+			logf.Log.Info("nvme list-subsys failed", "err", err)
 			continue
-		} else {
-			subSysList := make(map[string][]nvmeListSubsystemEntry)
-			output = trimForJson(output)
-			if err = json.Unmarshal([]byte(output), &subSysList); err != nil {
-				logf.Log.Info("Failed to unmarshal target", "error", err)
-			} else {
-				logf.Log.Info("nvme", "list-subsys", subSysList)
-				for _, subsys := range subSysList[`Subsystems`] {
-					var pathNames []string
-					for _, path := range subsys.Paths {
-						pathNames = append(pathNames, path[`Name`])
-					}
-					nqnPathNames[subsys.NQN] = pathNames
-				}
+		}
+		logf.Log.Info("nvme", "list-subsys", subSysList)
+		for _, subsys := range subSysList.Subsystems {
+			var pathNames []string
+			for _, path := range subsys.Paths {
+				pathNames = append(pathNames, path.Name)
 			}
+			nqnPathNames[subsys.NQN] = pathNames
 		}
 
 		for nqnKey, pathNames := range nqnPathNames {
 			if nqnKey == targetNqn {
 				for _, pathName := range pathNames {
-					for _, deviceData := range list["Devices"] {
+					for _, deviceData := range list.Devices {
 						devicePath = deviceData.DevicePath
 						// For mayastor the ModelNumber would be Mayastor NVMe controller
 						// check if the device listed is mayastor nvme controlled device
@@ -234,34 +157,12 @@ func getNvmeDevice(initiatorIP string, maxRetries int, targetNqn string) (bool,
 							if strings.HasPrefix(deviceOnly, pathName) {
 								logf.Log.Info("Found device", "devicePath", devicePath, "pathName", pathName, "nqn", nqnKey)
 								// get reservation report
-								reservations, err := agent.ListReservation(initiatorIP, devicePath)
-								if reservations == "" || err != nil {
-									logf.Log.Info("nvme list reservation failed", "reservation", reservations, "error", err)
+								reservationList, err := agent.ListReservation(initiatorIP, devicePath)
+								if err != nil {
+									logf.Log.Info("nvme list reservation failed", "error", err)
 									continue
 								}
-								// sample nvme reservation output in json format
-								/*
-									{
-										"gen" : 1,
-										"rtype" : 2,
-										"regctl" : 1,
-										"ptpls" : 1,
-										"regctlext" : [
-											{
-											"cntlid" : 61135,
-											"rcsts" : 1,
-											"rkey" : 13212321943525734355,
-											"hostid" : "a12aae512c0e4b918c362d9fe529a6f0"
-											}
-										]
-									}
-								*/
-								var reservationList reservationEntry
-								if err = json.Unmarshal([]byte(reservations), &reservationList); err != nil {
-									logf.Log.Info("Failed to unmarshal target", "reservations", reservations)
-									continue
-								}
-								logf.Log.Info("reservation", "regctlext", reservationList)
+								logf.Log.Info("reservation", "regctlext", reservationList.Regctlext)
 								reservation := reservationList.Regctlext
 								if len(reservation) > 0 {
 									hostId = reservation[0].Hostid
@@ -285,14 +186,13 @@ func getNvmeDevice(initiatorIP string, maxRetries int, targetNqn string) (bool,
 func ChecksumReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexusNodeNqn string) (string, error) {
 	var err error
 	logf.Log.Info("ChecksumReplica", "nexusIP", initiatorIP, "nodeIP", targetIP, "nqn", nqn)
-	resp, err := agent.NvmeConnect(initiatorIP, targetIP, nqn, nexusNodeNqn)
-	resp = strings.TrimSpace(resp)
+	connection, err := agent.NvmeConnect(initiatorIP, targetIP, nqn, nexusNodeNqn)
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
 		return "", err
 	}
-	if resp != "" { // connect should be silent
-		return "", fmt.Errorf("nvme connect returned with %s", resp)
+	if connection.Output != "" { // connect should be silent
+		return "", fmt.Errorf("nvme connect returned with %s", connection.Output)
 	}
 
 	// NOTE:
@@ -316,24 +216,23 @@ func ChecksumReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexusNod
 		// example: 28940560b05b47b299cc4098943eb1e5 -> 28940560-b05b-47b2-99cc-4098943eb1e5
 		formattedHostId := fmt.Sprintf("%s-%s-%s-%s-%s", hostId[0:8], hostId[8:12], hostId[12:16], hostId[16:20], hostId[20:])
 		// disconnect nvme
-		resp, err = agent.NvmeDisconnect(initiatorIP, nqn)
+		disconnection, err := agent.NvmeDisconnect(initiatorIP, nqn)
 		if err != nil {
 			logf.Log.Info("Running agent failed", "error", err)
 			return "", err
 		}
-		logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", resp)
+		logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", disconnection)
 
 		// nvme connect with Host uuid
-		resp, err = agent.NvmeConnectWithHostId(initiatorIP, targetIP, nqn, nexusNodeNqn, formattedHostId)
-		resp = strings.TrimSpace(resp)
+		connection, err = agent.NvmeConnectWithHostId(initiatorIP, targetIP, nqn, nexusNodeNqn, formattedHostId)
 		if err != nil {
 			logf.Log.Info("Running agent failed", "error", err)
 			return "", err
 		}
-		if resp != "" { // connect should be silent
-			return "", fmt.Errorf("nvme connect with host id returned with %s", resp)
+		if connection.Output != "" { // connect should be silent
+			return "", fmt.Errorf("nvme connect with host id returned with %s", connection.Output)
 		}
-		logf.Log.Info("Executed NvmeConnectWithHostId", "nqn", nqn, "nexus node nqn", nexusNodeNqn, "hostid", formattedHostId, "got", resp)
+		logf.Log.Info("Executed NvmeConnectWithHostId", "nqn", nqn, "nexus node nqn", nexusNodeNqn, "hostid", formattedHostId, "got", connection.Output)
 
 		logf.Log.Info("Check device path after running nvme connect with host id to replica")
 		// fetch device path again because it might change between above multiple nvme disconnect and connect
@@ -351,13 +250,19 @@ func ChecksumReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexusNod
 	// checksum the device
 	// the returned format is <checksum> <size> <device>
 	// e.g. "924018992 61849088 /dev/nvme0n1p2"
-	cksumText, err := agent.ChecksumDevice(initiatorIP, devicePath)
+	// checksums of large devices outlast an HTTP request, so run it as a job
+	ctx, cancel := context.WithTimeout(context.Background(), checksumReplicaTimeout)
+	defer cancel()
+	job, err := agent.RunJob(ctx, initiatorIP, agent.JobRequest{
+		Operation: agent.JobChecksumDevice,
+		Device:    &agent.Device{DevicePath: devicePath},
+	})
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
 		_, _ = agent.NvmeDisconnect(initiatorIP, nqn)
 		return "", err
 	}
-	cksumText = strings.TrimSpace(cksumText)
+	cksumText := strings.TrimSpace(job.Output)
 	// double check the response contains the device name
 	if !strings.Contains(cksumText, deviceOnly) {
 		_, _ = agent.NvmeDisconnect(initiatorIP, nqn)
@@ -371,24 +276,33 @@ func ChecksumReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexusNod
 		cksumDevice = fields[2]
 	}
 	logf.Log.Info("Executed ChecksumDevice", "devicePath", devicePath, "got", cksumText, "device", cksumDevice)
-	resp, err = agent.NvmeDisconnect(initiatorIP, nqn)
+	disconnection, err := agent.NvmeDisconnect(initiatorIP, nqn)
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
 		return "", err
 	}
-	logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", resp)
+	logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", disconnection)
 
 	// check that the device no longer exists
-	resp, err = agent.ListDevice(initiatorIP)
+	if err = checkDeviceRemoved(initiatorIP, deviceOnly); err != nil {
+		return "", err
+	}
+	return cksumText, nil
+}
+
+// checkDeviceRemoved returns an error if the device is still listed in /dev on the node
+func checkDeviceRemoved(nodeIP string, device string) error {
+	devices, err := agent.ListDevice(nodeIP)
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
-		return "", err
+		return err
 	}
-	//	logf.Log.Info("agent.ListDevice", "device only", deviceOnly, "resp", resp)
-	if strings.Contains(resp, deviceOnly) {
-		return "", fmt.Errorf("Device %s still exists", deviceOnly)
+	for _, dev := range devices {
+		if dev == device {
+			return fmt.Errorf("Device %s still exists", device)
+		}
 	}
-	return cksumText, nil
+	return nil
 }
 
 // FsConsistentReplica verifies the filesystem consistency of the nvme target
@@ -398,14 +312,13 @@ func ChecksumReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexusNod
 func FsConsistentReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexusNodeNqn string, fsType common.FileSystemType) (string, error) {
 	var err error
 	logf.Log.Info("FsConsistentReplica", "nexusIP", initiatorIP, "nodeIP", targetIP, "nqn", nqn)
-	resp, err := agent.NvmeConnect(initiatorIP, targetIP, nqn, nexusNodeNqn)
-	resp = strings.TrimSpace(resp)
+	connection, err := agent.NvmeConnect(initiatorIP, targetIP, nqn, nexusNodeNqn)
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
 		return "", err
 	}
-	if resp != "" { // connect should be silent
-		return "", fmt.Errorf("nvme connect returned with %s", resp)
+	if connection.Output != "" { // connect should be silent
+		return "", fmt.Errorf("nvme connect returned with %s", connection.Output)
 	}
 
 	// NOTE:
@@ -429,22 +342,21 @@ func FsConsistentReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexu
 		// example: 28940560b05b47b299cc4098943eb1e5 -> 28940560-b05b-47b2-99cc-4098943eb1e5
 		formattedHostId := fmt.Sprintf("%s-%s-%s-%s-%s", hostId[0:8], hostId[8:12], hostId[12:16], hostId[16:20], hostId[20:])
 		// disconnect nvme
-		resp, err = agent.NvmeDisconnect(initiatorIP, nqn)
+		disconnection, err := agent.NvmeDisconnect(initiatorIP, nqn)
 		if err != nil {
 			logf.Log.Info("Running agent failed", "error", err)
 			return "", err
 		}
-		logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", resp)
+		logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", disconnection)
 
 		// nvme connect with Host uuid
-		resp, err = agent.NvmeConnectWithHostId(initiatorIP, targetIP, nqn, nexusNodeNqn, formattedHostId)
-		resp = strings.TrimSpace(resp)
+		connection, err = agent.NvmeConnectWithHostId(initiatorIP, targetIP, nqn, nexusNodeNqn, formattedHostId)
 		if err != nil {
 			logf.Log.Info("Running agent failed", "error", err)
 			return "", err
 		}
-		if resp != "" { // connect should be silent
-			return "", fmt.Errorf("nvme connect with host id returned with %s", resp)
+		if connection.Output != "" { // connect should be silent
+			return "", fmt.Errorf("nvme connect with host id returned with %s", connection.Output)
 		}
 		logf.Log.Info("Executed NvmeConnectWithHostId successfully", "nqn", nqn, "nexus node nqn", nexusNodeNqn, "hostid", formattedHostId)
 
@@ -472,23 +384,17 @@ func FsConsistentReplica(initiatorIP, targetIP, nqn string, maxRetries int, nexu
 	fsckText = strings.TrimSpace(fsckText)
 	logf.Log.Info("Executed fsckDevice", "devicePath", devicePath, "got", fsckText)
 
-	resp, err = agent.NvmeDisconnect(initiatorIP, nqn)
+	disconnection, err := agent.NvmeDisconnect(initiatorIP, nqn)
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
 		return "", err
 	}
-	logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", resp)
+	logf.Log.Info("Executed NvmeDisconnect", "nqn", nqn, "got", disconnection)
 
 	// check that the device no longer exists
-	resp, err = agent.ListDevice(initiatorIP)
-	if err != nil {
-		logf.Log.Info("Running agent failed", "error", err)
+	if err = checkDeviceRemoved(initiatorIP, deviceOnly); err != nil {
 		return "", err
 	}
-	//	logf.Log.Info("agent.ListDevice", "device only", deviceOnly, "resp", resp)
-	if strings.Contains(resp, deviceOnly) {
-		return "", fmt.Errorf("Device %s still exists", deviceOnly)
-	}
 	return fsckText, nil
 }
 
@@ -504,20 +410,14 @@ func FsFreezeReplica(nodeName string) (string, error) {
 		return "", err
 	}
 	logf.Log.Info("Current", "nodeAddress", *nodeAddress)
-	output, err := agent.NvmeList(*nodeAddress)
-	if output == "" || err != nil {
-		logf.Log.Info("nvme list failed", "output", output, "err", err)
-		return "", err
-	}
-	output = trimForJson(output)
-	list := make(map[string][]nvmeListDeviceEntry)
-	if err = json.Unmarshal([]byte(output), &list); err != nil {
-		logf.Log.Info("Failed to unmarshal target", "output", output)
+	list, err := agent.NvmeList(*nodeAddress)
+	if err != nil {
+		logf.Log.Info("nvme list failed", "err", err)
 		return "", err
 	}
 	logf.Log.Info("nvme", "list", list)
 	var nvmeDevice string
-	for _, deviceData := range list["Devices"] {
+	for _, deviceData := range list.Devices {
 		// For mayastor the ModelNumber would be Mayastor NVMe controller
 		// check if the device listed is mayastor nvme controlled device
 		if deviceData.ModelNumber != nvmeControllerModel {
@@ -549,20 +449,14 @@ func FsUnfreezeReplica(nodeName string) (string, error) {
 		return "", err
 	}
 	logf.Log.Info("Current", "nodeAddress", *nodeAddress)
-	output, err := agent.NvmeList(*nodeAddress)
-	if output == "" || err != nil {
-		logf.Log.Info("nvme list failed", "output", output, "err", err)
-		return "", err
-	}
-	output = trimForJson(output)
-	list := make(map[string][]nvmeListDeviceEntry)
-	if err = json.Unmarshal([]byte(output), &list); err != nil {
-		logf.Log.Info("Failed to unmarshal target", "output", output)
+	list, err := agent.NvmeList(*nodeAddress)
+	if err != nil {
+		logf.Log.Info("nvme list failed", "err", err)
 		return "", err
 	}
 	logf.Log.Info("nvme", "list", list)
 	var nvmeDevice string
-	for _, deviceData := range list["Devices"] {
+	for _, deviceData := range list.Devices {
 		// For mayastor the ModelNumber would be Mayastor NVMe controller
 		// check if the device listed is mayastor nvme controlled device
 		if deviceData.ModelNumber != nvmeControllerModel {
@@ -896,14 +790,13 @@ func ConnectReplica(spec ReplicaMountSpec, maxRetries int) (*ReplicaMount, error
 		return nil, fmt.Errorf("invalid nqn URI %v", status.sharedURI)
 	}
 	status.nqn = nqnlong[:tailoffset]
-	resp, err := agent.NvmeConnect(spec.initiatorIP, spec.targetIP, status.nqn, spec.hostNqn)
-	resp = strings.TrimSpace(resp)
+	connection, err := agent.NvmeConnect(spec.initiatorIP, spec.targetIP, status.nqn, spec.hostNqn)
 	if err != nil {
 		logf.Log.Info("Running agent failed", "error", err)
 		return nil, err
 	}
-	if resp != "" { // connect should be silent
-		return nil, fmt.Errorf("nvme connect returned with %s", resp)
+	if connection.Output != "" { // connect should be silent
+		return nil, fmt.Errorf("nvme connect returned with %s", connection.Output)
 	}
 
 	// NOTE:
@@ -926,24 +819,23 @@ func ConnectReplica(spec ReplicaMountSpec, maxRetries int) (*ReplicaMount, error
 		// example: 28940560b05b47b299cc4098943eb1e5 -> 28940560-b05b-47b2-99cc-4098943eb1e5
 		formattedHostId := fmt.Sprintf("%s-%s-%s-%s-%s", hostId[0:8], hostId[8:12], hostId[12:16], hostId[16:20], hostId[20:])
 		// disconnect nvme
-		resp, err = agent.NvmeDisconnect(spec.initiatorIP, status.nqn)
+		disconnection, err := agent.NvmeDisconnect(spec.initiatorIP, status.nqn)
 		if err != nil {
 			logf.Log.Info("Running agent failed", "error", err)
 			return nil, err
 		}
-		logf.Log.Info("Executed NvmeDisconnect", "nqn", status.nqn, "got", resp)
+		logf.Log.Info("Executed NvmeDisconnect", "nqn", status.nqn, "got", disconnection)
 
 		// nvme connect with Host uuid
-		resp, err = agent.NvmeConnectWithHostId(spec.initiatorIP, spec.targetIP, status.nqn, spec.hostNqn, formattedHostId)
-		resp = strings.TrimSpace(resp)
+		connection, err = agent.NvmeConnectWithHostId(spec.initiatorIP, spec.targetIP, status.nqn, spec.hostNqn, formattedHostId)
 		if err != nil {
 			logf.Log.Info("Running agent failed", "error", err)
 			return nil, err
 		}
-		if resp != "" { // connect should be silent
-			return nil, fmt.Errorf("nvme connect with host id returned with %s", resp)
+		if connection.Output != "" { // connect should be silent
+			return nil, fmt.Errorf("nvme connect with host id returned with %s", connection.Output)
 		}
-		logf.Log.Info("Executed NvmeConnectWithHostId", "nqn", status.nqn, "host nqn", spec.hostNqn, "hostid", formattedHostId, "got", resp)
+		logf.Log.Info("Executed NvmeConnectWithHostId", "nqn", status.nqn, "host nqn", spec.hostNqn, "hostid", formattedHostId, "got", connection.Output)
 
 		logf.Log.Info("Check device path after running nvme connect with host id to replica")
 		// fetch device path again because it might change between above multiple nvme disconnect and connect
@@ -0,0 +1,176 @@
+package nexus_ha
+
+// Helpers for the NVMe ANA (asymmetric namespace access) state of volume targets,
+// the state is read from the nexus through gRPC and from the initiators through the e2e agent.
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/k8stest"
+	"github.com/openebs/openebs-e2e/common/mayastorclient"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// InitiatorAnaPath is an NVMe path of an initiator of a volume
+type InitiatorAnaPath struct {
+	AppNode  string
+	Endpoint string
+	Device   string
+	Traddr   string
+	State    string
+	AnaState mayastorclient.NvmeAnaState
+}
+
+// VolumeMultipath is the multipath view of a volume, the ANA state of the target
+// and the paths of every initiator connected to the volume
+type VolumeMultipath struct {
+	VolumeUuid     string
+	NexusUuid      string
+	TargetNode     string
+	TargetIp       string
+	TargetAnaState mayastorclient.NvmeAnaState
+	Paths          []InitiatorAnaPath
+}
+
+// TargetPaths returns the initiator paths to the current target of the volume
+func (mp VolumeMultipath) TargetPaths() []InitiatorAnaPath {
+	var paths []InitiatorAnaPath
+	for _, path := range mp.Paths {
+		if path.Traddr == mp.TargetIp {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Mismatches returns the initiator paths to the target whose ANA state differs from that of the target
+func (mp VolumeMultipath) Mismatches() []InitiatorAnaPath {
+	var paths []InitiatorAnaPath
+	for _, path := range mp.TargetPaths() {
+		if path.AnaState != mp.TargetAnaState {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func (mp VolumeMultipath) String() string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "volume %s target %s on %s (%s) ANA state %s",
+		mp.VolumeUuid, mp.NexusUuid, mp.TargetNode, mp.TargetIp, mp.TargetAnaState)
+	for _, path := range mp.Paths {
+		_, _ = fmt.Fprintf(&sb, "\n  %s (%s) %s -> %s %s %s",
+			path.AppNode, path.Endpoint, path.Device, path.Traddr, path.State, path.AnaState)
+	}
+	return sb.String()
+}
+
+// GetVolumeMultipath returns the multipath view of the volume
+func GetVolumeMultipath(volUuid string) (VolumeMultipath, error) {
+	mp := VolumeMultipath{VolumeUuid: volUuid}
+	msv, err := k8stest.GetMSV(volUuid)
+	if err != nil {
+		return mp, fmt.Errorf("failed to get msv %s, error: %w", volUuid, err)
+	}
+	mp.NexusUuid = msv.State.Target.Uuid
+	mp.TargetNode = msv.State.Target.Node
+	if mp.NexusUuid == "" || mp.TargetNode == "" {
+		return mp, fmt.Errorf("volume %s has no target", volUuid)
+	}
+	nodeIp, err := k8stest.GetNodeIPAddress(mp.TargetNode)
+	if err != nil {
+		return mp, fmt.Errorf("failed to get node %s ip address, error: %w", mp.TargetNode, err)
+	}
+	mp.TargetIp = *nodeIp
+
+	mp.TargetAnaState, err = mayastorclient.GetNvmeAnaState(mp.NexusUuid, mp.TargetIp)
+	if err != nil {
+		return mp, fmt.Errorf("failed to get ANA state of nexus %s on %s, error: %w", mp.NexusUuid, mp.TargetIp, err)
+	}
+
+	initiators, err := k8stest.GetVolumeInitiators(volUuid)
+	if err != nil {
+		return mp, err
+	}
+	for _, initiator := range initiators {
+		for _, path := range initiator.Subsystem.Paths {
+			mp.Paths = append(mp.Paths, InitiatorAnaPath{
+				AppNode:  initiator.AppNode.Id,
+				Endpoint: initiator.AppNode.State.Endpoint,
+				Device:   path.Name,
+				Traddr:   k8stest.NvmePathTraddr(path),
+				State:    path.State,
+				AnaState: mayastorclient.NvmeAnaState(path.ANAState),
+			})
+		}
+	}
+	return mp, nil
+}
+
+// SetVolumeAnaState sets the ANA state of the target of the volume, the nexus
+// remains, eg: an inaccessible target makes the initiators switch paths
+func SetVolumeAnaState(volUuid string, anaState mayastorclient.NvmeAnaState) error {
+	nexusUuid, err := k8stest.GetNexusUuid(volUuid)
+	if err != nil {
+		return fmt.Errorf("failed to get nexus of volume %s, error: %w", volUuid, err)
+	}
+	nexusIp, err := k8stest.GetNexusNodeIp(volUuid)
+	if err != nil {
+		return err
+	}
+	return mayastorclient.SetNvmeAnaState(nexusUuid, anaState, nexusIp)
+}
+
+// WaitForInitiatorAnaState waits until the ANA state of the target of the volume and of
+// every initiator path to the target is anaState, the last multipath view is returned
+func WaitForInitiatorAnaState(volUuid string, anaState mayastorclient.NvmeAnaState, timeoutSecs int) (VolumeMultipath, error) {
+	const sleepTime = 3
+	var mp VolumeMultipath
+	var err error
+	for ix := 0; ix < (timeoutSecs+sleepTime-1)/sleepTime; ix++ {
+		mp, err = GetVolumeMultipath(volUuid)
+		if err == nil {
+			err = verifyAnaState(mp, anaState)
+			if err == nil {
+				return mp, nil
+			}
+		}
+		time.Sleep(sleepTime * time.Second)
+	}
+	logf.Log.Info("WaitForInitiatorAnaState", "multipath", mp.String())
+	return mp, err
+}
+
+// verifyAnaState verifies that the target and the initiator paths to the target are in the ANA state
+func verifyAnaState(mp VolumeMultipath, anaState mayastorclient.NvmeAnaState) error {
+	var errs common.ErrorAccumulator
+	if mp.TargetAnaState != anaState {
+		errs.Accumulate(fmt.Errorf("target %s of volume %s ANA state is %s, expected %s",
+			mp.NexusUuid, mp.VolumeUuid, mp.TargetAnaState, anaState))
+	}
+	paths := mp.TargetPaths()
+	if len(paths) == 0 {
+		errs.Accumulate(fmt.Errorf("volume %s has no initiator paths to target %s", mp.VolumeUuid, mp.TargetIp))
+	}
+	for _, path := range paths {
+		if path.AnaState != anaState {
+			errs.Accumulate(fmt.Errorf("path %s of app node %s ANA state is %s, expected %s",
+				path.Device, path.AppNode, path.AnaState, anaState))
+		}
+	}
+	return errs.GetError()
+}
+
+// TransitionVolumeAnaState sets the ANA state of the target of the volume and waits
+// for every initiator to observe the transition, eg: optimized -> inaccessible
+func TransitionVolumeAnaState(volUuid string, anaState mayastorclient.NvmeAnaState, timeoutSecs int) (VolumeMultipath, error) {
+	logf.Log.Info("TransitionVolumeAnaState", "volume", volUuid, "anaState", anaState)
+	if err := SetVolumeAnaState(volUuid, anaState); err != nil {
+		return VolumeMultipath{VolumeUuid: volUuid}, err
+	}
+	return WaitForInitiatorAnaState(volUuid, anaState, timeoutSecs)
+}
@@ -0,0 +1,188 @@
+package partial_rebuild
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	mayastorclient "github.com/openebs/openebs-e2e/common/mayastorclient"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// rebuild states reported by the io-engine
+const (
+	RebuildStateRunning   = "running"
+	RebuildStatePaused    = "paused"
+	RebuildStateStopped   = "stopped"
+	RebuildStateFailed    = "failed"
+	RebuildStateCompleted = "completed"
+)
+
+// RebuildSample is the progress of the rebuild of a nexus child at a point in time
+type RebuildSample struct {
+	Time              time.Time
+	ChildUri          string
+	State             string
+	BlocksTotal       uint64
+	BlocksRecovered   uint64
+	BlocksTransferred uint64
+	BlocksRemaining   uint64
+	BlockSize         uint64
+	Progress          uint64
+}
+
+// RebuildSampler samples the progress of the rebuild of nexus children at an interval,
+// recording a time series of the blocks transferred for each child, eg:
+//
+//	sampler := partial_rebuild.StartRebuildSampler(nexusUuid, nexusNodeIp, time.Second, childUri)
+//	...
+//	samples := sampler.Stop()[childUri]
+//	Expect(partial_rebuild.BlocksTransferredWhilePaused(samples)).To(BeZero())
+//	logf.Log.Info("rebuild", "blocks/s", partial_rebuild.RebuildThroughput(samples))
+type RebuildSampler struct {
+	nexusUuid   string
+	nexusNodeIp string
+	childUris   []string
+	interval    time.Duration
+	mutex       sync.Mutex
+	samples     map[string][]RebuildSample
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// StartRebuildSampler starts sampling the rebuild of the children of the nexus
+// on the io-engine at nexusNodeIp, samples are only recorded while a child is being rebuilt
+func StartRebuildSampler(nexusUuid string, nexusNodeIp string, interval time.Duration, childUris ...string) *RebuildSampler {
+	s := &RebuildSampler{
+		nexusUuid:   nexusUuid,
+		nexusNodeIp: nexusNodeIp,
+		childUris:   childUris,
+		interval:    interval,
+		samples:     make(map[string][]RebuildSample),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *RebuildSampler) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		for _, childUri := range s.childUris {
+			s.sample(childUri)
+		}
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample records the progress of the rebuild of the child, there is nothing
+// to record if the rebuild has not started or has ended
+func (s *RebuildSampler) sample(childUri string) {
+	state, err := mayastorclient.GetRebuildState(s.nexusUuid, childUri, s.nexusNodeIp)
+	if err != nil {
+		logf.Log.Info("RebuildSampler: no rebuild state", "nexus", s.nexusUuid, "child", childUri, "error", err)
+		return
+	}
+	stats, err := mayastorclient.GetRebuildStats(s.nexusUuid, childUri, s.nexusNodeIp)
+	if err != nil {
+		logf.Log.Info("RebuildSampler: no rebuild stats", "nexus", s.nexusUuid, "child", childUri, "error", err)
+		return
+	}
+	sample := RebuildSample{
+		Time:              time.Now(),
+		ChildUri:          childUri,
+		State:             state,
+		BlocksTotal:       stats.GetBlocksTotal(),
+		BlocksRecovered:   stats.GetBlocksRecovered(),
+		BlocksTransferred: stats.GetBlocksTransferred(),
+		BlocksRemaining:   stats.GetBlocksRemaining(),
+		BlockSize:         stats.GetBlockSize(),
+		Progress:          stats.GetProgress(),
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.samples[childUri] = append(s.samples[childUri], sample)
+}
+
+// Samples returns the samples recorded so far for the child
+func (s *RebuildSampler) Samples(childUri string) []RebuildSample {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]RebuildSample{}, s.samples[childUri]...)
+}
+
+// Stop stops sampling and returns the samples recorded for each child by child uri
+func (s *RebuildSampler) Stop() map[string][]RebuildSample {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	samples := make(map[string][]RebuildSample)
+	for childUri, childSamples := range s.samples {
+		samples[childUri] = append([]RebuildSample{}, childSamples...)
+	}
+	return samples
+}
+
+// BlocksTransferredWhilePaused returns the number of blocks transferred between
+// consecutive samples in which the rebuild was paused, which should be 0
+func BlocksTransferredWhilePaused(samples []RebuildSample) uint64 {
+	var transferred uint64
+	for ix := 1; ix < len(samples); ix++ {
+		prev, cur := samples[ix-1], samples[ix]
+		if prev.State == RebuildStatePaused && cur.State == RebuildStatePaused &&
+			cur.BlocksTransferred > prev.BlocksTransferred {
+			transferred += cur.BlocksTransferred - prev.BlocksTransferred
+		}
+	}
+	return transferred
+}
+
+// VerifyRebuildProgress verifies that the blocks transferred never decrease,
+// and do not increase while the rebuild is paused
+func VerifyRebuildProgress(samples []RebuildSample) error {
+	for ix := 1; ix < len(samples); ix++ {
+		prev, cur := samples[ix-1], samples[ix]
+		if cur.BlocksTransferred < prev.BlocksTransferred {
+			return fmt.Errorf("blocks transferred of rebuild of %s decreased from %d to %d at %v",
+				cur.ChildUri, prev.BlocksTransferred, cur.BlocksTransferred, cur.Time)
+		}
+	}
+	if transferred := BlocksTransferredWhilePaused(samples); transferred != 0 {
+		return fmt.Errorf("%d blocks transferred while the rebuild was paused", transferred)
+	}
+	return nil
+}
+
+// RebuildThroughput returns the mean number of blocks transferred per second
+// while the rebuild was running, intervals in which the rebuild was paused are excluded
+func RebuildThroughput(samples []RebuildSample) float64 {
+	var transferred uint64
+	var elapsed time.Duration
+	for ix := 1; ix < len(samples); ix++ {
+		prev, cur := samples[ix-1], samples[ix]
+		if prev.State != RebuildStateRunning {
+			continue
+		}
+		if cur.BlocksTransferred > prev.BlocksTransferred {
+			transferred += cur.BlocksTransferred - prev.BlocksTransferred
+		}
+		elapsed += cur.Time.Sub(prev.Time)
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(transferred) / elapsed.Seconds()
+}
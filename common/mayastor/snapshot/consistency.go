@@ -0,0 +1,206 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane"
+	"github.com/openebs/openebs-e2e/common/k8stest"
+	"github.com/openebs/openebs-e2e/common/mayastorclient"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ReplicaSnapshotInconsistency is the kind of disagreement between the control plane and an io-engine
+type ReplicaSnapshotInconsistency string
+
+const (
+	// ReplicaSnapshotMissing is a replica snapshot of the control plane which is not held by the io-engine
+	ReplicaSnapshotMissing ReplicaSnapshotInconsistency = "missing"
+	// ReplicaSnapshotOrphaned is a snapshot held by an io-engine which is unknown to the control plane
+	ReplicaSnapshotOrphaned ReplicaSnapshotInconsistency = "orphaned"
+	// ReplicaSnapshotMismatched is a replica snapshot whose source replica or pool differs
+	ReplicaSnapshotMismatched ReplicaSnapshotInconsistency = "mismatched"
+)
+
+// ReplicaSnapshotFinding is a replica snapshot on which the control plane and an io-engine disagree
+type ReplicaSnapshotFinding struct {
+	Kind                ReplicaSnapshotInconsistency
+	SnapshotUuid        string // volume snapshot, empty for orphaned replica snapshots
+	ReplicaSnapshotUuid string
+	SourceUuid          string
+	Pool                string
+	Node                string
+	Details             string
+}
+
+func (f ReplicaSnapshotFinding) String() string {
+	s := fmt.Sprintf("%s replica snapshot %s of replica %s in pool %s on node %s",
+		f.Kind, f.ReplicaSnapshotUuid, f.SourceUuid, f.Pool, f.Node)
+	if f.SnapshotUuid != "" {
+		s += fmt.Sprintf(", volume snapshot %s", f.SnapshotUuid)
+	}
+	if f.Details != "" {
+		s += ", " + f.Details
+	}
+	return s
+}
+
+// SnapshotConsistencyReport is the result of comparing the replica snapshots of the
+// control plane with the snapshots held by the io-engines
+type SnapshotConsistencyReport struct {
+	Findings []ReplicaSnapshotFinding
+	// Unchecked are the nodes whose snapshots could not be listed, the replica
+	// snapshots in the pools of these nodes are not checked
+	Unchecked []string
+}
+
+// Err returns an error describing the findings, or nil if there are none
+func (r SnapshotConsistencyReport) Err() error {
+	if len(r.Findings) == 0 {
+		return nil
+	}
+	var findings []string
+	for _, finding := range r.Findings {
+		findings = append(findings, finding.String())
+	}
+	return fmt.Errorf("replica snapshots are inconsistent: %s", strings.Join(findings, "; "))
+}
+
+// ioEngineSnapshot is a snapshot held by the io-engine of a node
+type ioEngineSnapshot struct {
+	snapshot mayastorclient.Snapshot
+	node     string
+}
+
+// CheckSnapshotConsistency compares the replica snapshots of the volume snapshots of the control plane
+// with the snapshots held by each io-engine, flagging replica snapshots which are missing from the
+// io-engine, orphaned on the io-engine or whose source replica or pool differ, eg: after a failure
+// during the creation or deletion of a snapshot. Replica snapshots which are recorded only by a
+// snapshot transaction of the control plane are known and are not flagged as orphaned, discarded
+// snapshots which are retained by the io-engine for their clones are not flagged either.
+func CheckSnapshotConsistency() (SnapshotConsistencyReport, error) {
+	var report SnapshotConsistencyReport
+	snapshots, err := controlplane.GetSnapshots()
+	if err != nil {
+		return report, fmt.Errorf("failed to get snapshots, error: %w", err)
+	}
+	pools, err := controlplane.ListMsPools()
+	if err != nil {
+		return report, fmt.Errorf("failed to list pools, error: %w", err)
+	}
+
+	poolNodes := make(map[string]string)
+	nodePools := make(map[string][]string)
+	for _, pool := range pools {
+		poolNodes[pool.Name] = pool.Spec.Node
+		nodePools[pool.Spec.Node] = append(nodePools[pool.Spec.Node], pool.Name)
+	}
+	var nodes []string
+	for node := range nodePools {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	// the snapshots held by the io-engines by uuid
+	held := make(map[string]ioEngineSnapshot)
+	unchecked := make(map[string]bool)
+	var errs common.ErrorAccumulator
+	for _, node := range nodes {
+		nodeIp, err := k8stest.GetNodeIPAddress(node)
+		if err == nil {
+			var nodeSnapshots []mayastorclient.Snapshot
+			nodeSnapshots, err = mayastorclient.ListSnapshots(*nodeIp)
+			for _, snapshot := range nodeSnapshots {
+				held[snapshot.GetUuid()] = ioEngineSnapshot{snapshot: snapshot, node: node}
+			}
+		}
+		if err != nil {
+			errs.Accumulate(fmt.Errorf("failed to list snapshots on node %s, error: %w", node, err))
+			unchecked[node] = true
+			report.Unchecked = append(report.Unchecked, node)
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		for _, txns := range snapshot.Definition.Metadata.Transactions {
+			for _, txn := range txns {
+				known[txn.UUID] = true
+			}
+		}
+		for _, replicaSnapshot := range snapshot.State.ReplicaSnapshots {
+			online := replicaSnapshot.Online
+			if online.UUID == "" {
+				// offline replica snapshots are those the control plane cannot reach
+				known[replicaSnapshot.Offline.UUID] = true
+				continue
+			}
+			known[online.UUID] = true
+			node := poolNodes[online.PoolID]
+			if unchecked[node] {
+				continue
+			}
+			finding := ReplicaSnapshotFinding{
+				SnapshotUuid:        snapshot.Definition.Spec.UUID,
+				ReplicaSnapshotUuid: online.UUID,
+				SourceUuid:          online.SourceID,
+				Pool:                online.PoolID,
+				Node:                node,
+			}
+			ioSnapshot, ok := held[online.UUID]
+			if !ok {
+				finding.Kind = ReplicaSnapshotMissing
+				report.Findings = append(report.Findings, finding)
+				continue
+			}
+			var details []string
+			if ioSnapshot.snapshot.GetSourceUuid() != online.SourceID {
+				details = append(details, fmt.Sprintf("io-engine source replica %s", ioSnapshot.snapshot.GetSourceUuid()))
+			}
+			if ioSnapshot.snapshot.GetPoolName() != online.PoolID {
+				details = append(details, fmt.Sprintf("io-engine pool %s on node %s", ioSnapshot.snapshot.GetPoolName(), ioSnapshot.node))
+			}
+			if len(details) != 0 {
+				finding.Kind = ReplicaSnapshotMismatched
+				finding.Details = strings.Join(details, ", ")
+				report.Findings = append(report.Findings, finding)
+			}
+		}
+	}
+
+	var orphans []string
+	for uuid := range held {
+		if !known[uuid] && !held[uuid].snapshot.IsDiscarded() {
+			orphans = append(orphans, uuid)
+		}
+	}
+	sort.Strings(orphans)
+	for _, uuid := range orphans {
+		ioSnapshot := held[uuid]
+		report.Findings = append(report.Findings, ReplicaSnapshotFinding{
+			Kind:                ReplicaSnapshotOrphaned,
+			ReplicaSnapshotUuid: uuid,
+			SourceUuid:          ioSnapshot.snapshot.GetSourceUuid(),
+			Pool:                ioSnapshot.snapshot.GetPoolName(),
+			Node:                ioSnapshot.node,
+			Details:             fmt.Sprintf("volume %s, txn %s", ioSnapshot.snapshot.GetEntityId(), ioSnapshot.snapshot.GetTxnId()),
+		})
+	}
+	if len(report.Findings) != 0 {
+		logf.Log.Info("CheckSnapshotConsistency", "findings", report.Findings)
+	}
+	return report, errs.GetError()
+}
+
+// VerifySnapshotConsistency verifies that the replica snapshots of the control plane
+// and the snapshots held by the io-engines are consistent, see CheckSnapshotConsistency
+func VerifySnapshotConsistency() error {
+	report, err := CheckSnapshotConsistency()
+	if err != nil {
+		return err
+	}
+	return report.Err()
+}
@@ -0,0 +1,221 @@
+package mayastorclient
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// FaultDomain is where the io-engine injects the fault
+type FaultDomain string
+
+const (
+	// FaultDomainNexusChild injects faults into the I/O of a nexus to a child
+	FaultDomainNexusChild FaultDomain = "child"
+	// FaultDomainBlockDevice injects faults into the I/O of a block device, eg: a replica
+	FaultDomainBlockDevice FaultDomain = "block"
+	// FaultDomainBdevIo injects faults into the I/O of a bdev
+	FaultDomainBdevIo FaultDomain = "bdev_io"
+)
+
+// FaultIoOperation is the type of the I/O which is faulted
+type FaultIoOperation string
+
+const (
+	FaultIoRead      FaultIoOperation = "read"
+	FaultIoWrite     FaultIoOperation = "write"
+	FaultIoReadWrite FaultIoOperation = "read_write"
+)
+
+// FaultIoStage is the stage of the I/O at which the fault is injected
+type FaultIoStage string
+
+const (
+	FaultIoStageSubmission FaultIoStage = "submission"
+	FaultIoStageCompletion FaultIoStage = "completion"
+)
+
+// FaultMethod is how the I/O is faulted
+type FaultMethod string
+
+const (
+	// FaultMethodStatus fails the I/O with an error status
+	FaultMethodStatus FaultMethod = "status"
+	// FaultMethodData corrupts the data of the I/O
+	FaultMethodData FaultMethod = "data"
+)
+
+// FaultInjectionBuilder builds the uri of an io-engine fault injection, eg:
+//
+//	uri, err := mayastorclient.NewFaultInjectionBuilder(childDevice).
+//		WithDomain(mayastorclient.FaultDomainNexusChild).
+//		WithIoOperation(mayastorclient.FaultIoWrite).
+//		WithBlockRange(0, 1024).
+//		WithRetries(3).
+//		Build()
+//
+// is inject://<childDevice>?domain=child&num_blk=1024&offset=0&op=write&retries=3
+type FaultInjectionBuilder struct {
+	device string
+	params url.Values
+	errs   []error
+}
+
+// NewFaultInjectionBuilder returns a builder of a fault injection uri for the device,
+// the name of the device as known to the io-engine, eg: the name of a nexus child bdev
+func NewFaultInjectionBuilder(device string) *FaultInjectionBuilder {
+	b := &FaultInjectionBuilder{device: device, params: url.Values{}}
+	if device == "" {
+		b.errs = append(b.errs, fmt.Errorf("fault injection: missing device name"))
+	}
+	return b
+}
+
+// WithDomain sets where the fault is injected
+func (b *FaultInjectionBuilder) WithDomain(domain FaultDomain) *FaultInjectionBuilder {
+	b.params.Set("domain", string(domain))
+	return b
+}
+
+// WithIoOperation sets the type of I/O which is faulted
+func (b *FaultInjectionBuilder) WithIoOperation(op FaultIoOperation) *FaultInjectionBuilder {
+	b.params.Set("op", string(op))
+	return b
+}
+
+// WithStage sets the stage of the I/O at which the fault is injected
+func (b *FaultInjectionBuilder) WithStage(stage FaultIoStage) *FaultInjectionBuilder {
+	b.params.Set("stage", string(stage))
+	return b
+}
+
+// WithMethod sets how the I/O is faulted
+func (b *FaultInjectionBuilder) WithMethod(method FaultMethod) *FaultInjectionBuilder {
+	b.params.Set("method", string(method))
+	return b
+}
+
+// WithBlockRange restricts the fault to I/O to count blocks starting at block offset
+func (b *FaultInjectionBuilder) WithBlockRange(offset uint64, count uint64) *FaultInjectionBuilder {
+	if count == 0 {
+		b.errs = append(b.errs, fmt.Errorf("fault injection: empty block range at %d", offset))
+		return b
+	}
+	b.params.Set("offset", strconv.FormatUint(offset, 10))
+	b.params.Set("num_blk", strconv.FormatUint(count, 10))
+	return b
+}
+
+// WithRetries sets the number of times the faulted I/O fails before it succeeds
+func (b *FaultInjectionBuilder) WithRetries(retries uint64) *FaultInjectionBuilder {
+	b.params.Set("retries", strconv.FormatUint(retries, 10))
+	return b
+}
+
+// WithTimeRange restricts the fault to the period from begin to end after the injection is added,
+// an end of 0 leaves the injection active until it is removed
+func (b *FaultInjectionBuilder) WithTimeRange(begin time.Duration, end time.Duration) *FaultInjectionBuilder {
+	if end != 0 && end <= begin {
+		b.errs = append(b.errs, fmt.Errorf("fault injection: end %v is not after begin %v", end, begin))
+		return b
+	}
+	b.params.Set("begin_at", strconv.FormatInt(begin.Milliseconds(), 10))
+	if end != 0 {
+		b.params.Set("end_at", strconv.FormatInt(end.Milliseconds(), 10))
+	}
+	return b
+}
+
+// Build returns the fault injection uri
+func (b *FaultInjectionBuilder) Build() (string, error) {
+	var acc common.ErrorAccumulator
+	for _, err := range b.errs {
+		acc.Accumulate(err)
+	}
+	if err := acc.GetError(); err != nil {
+		return "", err
+	}
+	uri := "inject://" + b.device
+	if len(b.params) != 0 {
+		uri += "?" + b.params.Encode()
+	}
+	return uri, nil
+}
+
+type faultInjection struct {
+	address string
+	uri     string
+}
+
+// FaultInjectionScope records the fault injections added through it so that
+// they are removed together, eg: by AfterEach whether or not the test case failed
+//
+//	var faults = mayastorclient.NewFaultInjectionScope()
+//
+//	AfterEach(func() {
+//		Expect(faults.RemoveAll()).To(Succeed())
+//	})
+//
+//	It("...", func() {
+//		Expect(faults.Add(nodeIP, uri)).To(Succeed())
+//	})
+type FaultInjectionScope struct {
+	mutex      sync.Mutex
+	injections []faultInjection
+}
+
+// NewFaultInjectionScope returns an empty fault injection scope
+func NewFaultInjectionScope() *FaultInjectionScope {
+	return &FaultInjectionScope{}
+}
+
+// Add adds the fault injection to the io-engine at the address and records it for removal
+func (s *FaultInjectionScope) Add(address string, uri string) error {
+	if err := AddFaultInjection(address, uri); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.injections = append(s.injections, faultInjection{address: address, uri: uri})
+	return nil
+}
+
+// Remove removes a fault injection added through the scope before the end of the scope
+func (s *FaultInjectionScope) Remove(address string, uri string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for ix, injection := range s.injections {
+		if injection.address == address && injection.uri == uri {
+			if err := RemoveFaultInjection(address, uri); err != nil {
+				return err
+			}
+			s.injections = append(s.injections[:ix], s.injections[ix+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("fault injection %s on %s was not added through the scope", uri, address)
+}
+
+// RemoveAll removes all fault injections added through the scope, injections
+// which fail to be removed are kept so that removal can be retried
+func (s *FaultInjectionScope) RemoveAll() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var errs common.ErrorAccumulator
+	var remaining []faultInjection
+	for _, injection := range s.injections {
+		if err := RemoveFaultInjection(injection.address, injection.uri); err != nil {
+			logf.Log.Info("failed to remove fault injection", "address", injection.address, "uri", injection.uri, "error", err)
+			errs.Accumulate(fmt.Errorf("%s on %s: %w", injection.uri, injection.address, err))
+			remaining = append(remaining, injection)
+		}
+	}
+	s.injections = remaining
+	return errs.GetError()
+}
@@ -1,6 +1,7 @@
 package mayastorclient
 
 import (
+	"context"
 	"fmt"
 
 	v0 "github.com/openebs/openebs-e2e/common/mayastorclient/v0"
@@ -92,6 +93,13 @@ func (w v0NexusWrapper) GetStateString() string {
 }
 
 type grpcV0 struct {
+	// ctx bounds the gRPC calls
+	ctx context.Context
+}
+
+func (g grpcV0) WithContext(ctx context.Context) GrpcInterface {
+	g.ctx = ctx
+	return g
 }
 
 func (g grpcV0) Version() string {
@@ -102,7 +110,7 @@ func (g grpcV0) Version() string {
 // The required semantics for this function are *always* return the list
 // of nexuses found, even if errors have occurred
 func (g grpcV0) ListNexuses(addrs []string) ([]MayastorNexus, error) {
-	v0nexuses, err := v0.ListNexuses(addrs)
+	v0nexuses, err := v0.ListNexuses(g.ctx, addrs)
 
 	var nexuses []MayastorNexus
 	for _, v0nexus := range v0nexuses {
@@ -112,11 +120,11 @@ func (g grpcV0) ListNexuses(addrs []string) ([]MayastorNexus, error) {
 }
 
 func (g grpcV0) FaultNexusChild(address string, Uuid string, Uri string) error {
-	return v0.FaultNexusChild(address, Uuid, Uri)
+	return v0.FaultNexusChild(g.ctx, address, Uuid, Uri)
 }
 
 func (g grpcV0) FindNexus(uuid string, addrs []string) (*MayastorNexus, error) {
-	v0nexus, err := v0.FindNexus(uuid, addrs)
+	v0nexus, err := v0.FindNexus(g.ctx, uuid, addrs)
 	if err == nil && v0nexus != nil {
 		var nexus MayastorNexus = v0NexusWrapper{*v0nexus}
 		return &nexus, err
@@ -126,7 +134,7 @@ func (g grpcV0) FindNexus(uuid string, addrs []string) (*MayastorNexus, error) {
 
 func (g grpcV0) ListNvmeControllers(addrs []string) ([]NvmeController, error) {
 	var ncs []NvmeController
-	v0ncs, err := v0.ListNvmeControllers(addrs)
+	v0ncs, err := v0.ListNvmeControllers(g.ctx, addrs)
 	if err == nil {
 		for _, v0nc := range v0ncs {
 			ncs = append(ncs, v0nc)
@@ -136,7 +144,7 @@ func (g grpcV0) ListNvmeControllers(addrs []string) ([]NvmeController, error) {
 }
 
 func (g grpcV0) GetPool(name, addr string) (MayastorPool, error) {
-	v0Pool, err := v0.GetPool(name, addr)
+	v0Pool, err := v0.GetPool(g.ctx, name, addr)
 	if err == nil {
 		var pool MayastorPool = v0Pool
 		return pool, nil
@@ -149,7 +157,7 @@ func (g grpcV0) GetPool(name, addr string) (MayastorPool, error) {
 // of pools found, even if errors have occurred
 func (g grpcV0) ListPools(addrs []string) ([]MayastorPool, error) {
 	var pools []MayastorPool
-	v0pools, err := v0.ListPools(addrs)
+	v0pools, err := v0.ListPools(g.ctx, addrs)
 	for _, v0Pool := range v0pools {
 		pools = append(pools, v0Pool)
 	}
@@ -157,23 +165,23 @@ func (g grpcV0) ListPools(addrs []string) ([]MayastorPool, error) {
 }
 
 func (g grpcV0) DestroyAllPools(addrs []string) error {
-	return v0.DestroyAllPools(addrs)
+	return v0.DestroyAllPools(g.ctx, addrs)
 }
 
 func (g grpcV0) DestroyPool(name, addr string) error {
-	return v0.DestroyPool(name, addr)
+	return v0.DestroyPool(g.ctx, name, addr)
 }
 
 func (g grpcV0) RmReplica(address string, uuid string) error {
-	return v0.RmReplica(address, uuid)
+	return v0.RmReplica(g.ctx, address, uuid)
 }
 
 func (g grpcV0) CreateReplicaExt(address string, uuid string, size uint64, pool string, thin bool) error {
-	return v0.CreateReplicaExt(address, uuid, size, pool, thin)
+	return v0.CreateReplicaExt(g.ctx, address, uuid, size, pool, thin)
 }
 
 func (g grpcV0) CreateReplica(address string, uuid string, size uint64, pool string) error {
-	return v0.CreateReplica(address, uuid, size, pool)
+	return v0.CreateReplica(g.ctx, address, uuid, size, pool)
 }
 
 // ListReplicas list the set of replicas found for the set of IP addresses
@@ -181,7 +189,7 @@ func (g grpcV0) CreateReplica(address string, uuid string, size uint64, pool str
 // of replicas found, even if errors have occurred
 func (g grpcV0) ListReplicas(addrs []string) ([]MayastorReplica, error) {
 	var replicas []MayastorReplica
-	v0Replicas, err := v0.ListReplicas(addrs)
+	v0Replicas, err := v0.ListReplicas(g.ctx, addrs)
 	for _, v0Repl := range v0Replicas {
 		replicas = append(replicas, v0Repl)
 	}
@@ -190,12 +198,12 @@ func (g grpcV0) ListReplicas(addrs []string) ([]MayastorReplica, error) {
 }
 
 func (g grpcV0) RmNodeReplicas(addrs []string) error {
-	return v0.RmNodeReplicas(addrs)
+	return v0.RmNodeReplicas(g.ctx, addrs)
 }
 
 func (g grpcV0) FindReplicas(uuid string, addrs []string) ([]MayastorReplica, error) {
 	var replicas []MayastorReplica
-	v0Replicas, err := v0.FindReplicas(uuid, addrs)
+	v0Replicas, err := v0.FindReplicas(g.ctx, uuid, addrs)
 	if err == nil {
 		for _, v0Repl := range v0Replicas {
 			replicas = append(replicas, v0Repl)
@@ -209,9 +217,41 @@ func (g grpcV0) GetRebuildHistory(uuid string, addrs string) (RebuildHistory, er
 	panic(fmt.Errorf("gRPC v0 does not have rebuild history rpc call"))
 }
 
+func (g grpcV0) StartRebuild(uuid string, childUri string, addrs string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) StopRebuild(uuid string, childUri string, addrs string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) PauseRebuild(uuid string, childUri string, addrs string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ResumeRebuild(uuid string, childUri string, addrs string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) GetRebuildState(uuid string, childUri string, addrs string) (string, error) {
+	return "", fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ListRebuildHistory(addrs string) (map[string]RebuildHistory, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) GetNvmeAnaState(uuid string, addrs string) (NvmeAnaState, error) {
+	return "", fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) SetNvmeAnaState(uuid string, anaState NvmeAnaState, addrs string) error {
+	return fmt.Errorf("unsupported")
+}
+
 func (g grpcV0) GetRebuildStats(uuid string, dstUri string, addrs string) (RebuildStats, error) {
 	var rebuildStats RebuildStats
-	v0RebuildStats, err := v0.GetRebuildStats(uuid, dstUri, addrs)
+	v0RebuildStats, err := v0.GetRebuildStats(g.ctx, uuid, dstUri, addrs)
 	if err == nil {
 		rebuildStats = v0RebuildStatsWrapper{v0RebuildStats}
 	}
@@ -219,7 +259,7 @@ func (g grpcV0) GetRebuildStats(uuid string, dstUri string, addrs string) (Rebui
 }
 
 func (g grpcV0) CheckAndSetConnect(nodes []string) error {
-	return v0.CheckAndSetConnect(nodes)
+	return v0.CheckAndSetConnect(g.ctx, nodes)
 }
 
 func (g grpcV0) CanConnect() bool {
@@ -235,13 +275,61 @@ func (g grpcV0) ChecksumReplica(address string, replicaUUID string, poolName str
 }
 
 func (g grpcV0) ShareBdev(address string, bdevUuid string) (string, error) {
-	return v0.ShareBdev(address, bdevUuid)
+	return v0.ShareBdev(g.ctx, address, bdevUuid)
 }
 
 func (g grpcV0) UnshareBdev(address string, bdevUuid string) error {
-	return v0.UnshareBdev(address, bdevUuid)
+	return v0.UnshareBdev(g.ctx, address, bdevUuid)
 }
 
 func (g grpcV0) ResetIOStats(address string) error {
 	panic(fmt.Errorf("gRPC v0 does not have io stats reset rpc call"))
 }
+
+func (g grpcV0) AddFaultInjection(address string, uri string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) RemoveFaultInjection(address string, uri string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ListFaultInjections(address string) ([]FaultInjection, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ExportPool(name, addr string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ImportPool(name string, disks []string, addr string) (MayastorPool, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ResizeReplica(address string, uuid string, size uint64) (MayastorReplica, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) CreateReplicaSnapshot(address string, replicaUuid string, snapshotUuid string, snapshotName string, entityId string, txnId string) (Snapshot, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) CreateNexusSnapshot(address string, nexusUuid string, snapshotName string, entityId string, txnId string, replicaSnapshots map[string]string) (NexusSnapshot, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ListSnapshots(address string) ([]Snapshot, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) DestroySnapshot(address string, snapshotUuid string, poolName string) error {
+	return fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) CreateSnapshotClone(address string, snapshotUuid string, cloneName string, cloneUuid string) (MayastorReplica, error) {
+	return nil, fmt.Errorf("unsupported")
+}
+
+func (g grpcV0) ListSnapshotClones(address string, snapshotUuid string) ([]MayastorReplica, error) {
+	return nil, fmt.Errorf("unsupported")
+}
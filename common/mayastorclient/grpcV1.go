@@ -1,11 +1,20 @@
 package mayastorclient
 
 import (
+	"context"
+
 	v1 "github.com/openebs/openebs-e2e/common/mayastorclient/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type grpcV1 struct {
+	// ctx bounds the gRPC calls
+	ctx context.Context
+}
+
+func (g grpcV1) WithContext(ctx context.Context) GrpcInterface {
+	g.ctx = ctx
+	return g
 }
 
 func (g grpcV1) Version() string {
@@ -117,7 +126,7 @@ func (w v1NexusWrapper) GetStateString() string {
 // The required semantics for this function are *always* return the list
 // of nexuses found, even if errors have occurred
 func (g grpcV1) ListNexuses(addrs []string) ([]MayastorNexus, error) {
-	v1nexuses, err := v1.ListNexuses(addrs)
+	v1nexuses, err := v1.ListNexuses(g.ctx, addrs)
 	var nexuses []MayastorNexus
 	for _, v1nexus := range v1nexuses {
 		nexuses = append(nexuses, v1NexusWrapper{v1nexus})
@@ -126,11 +135,11 @@ func (g grpcV1) ListNexuses(addrs []string) ([]MayastorNexus, error) {
 }
 
 func (g grpcV1) FaultNexusChild(address string, Uuid string, Uri string) error {
-	return v1.FaultNexusChild(address, Uuid, Uri)
+	return v1.FaultNexusChild(g.ctx, address, Uuid, Uri)
 }
 
 func (g grpcV1) FindNexus(uuid string, addrs []string) (*MayastorNexus, error) {
-	v1nexus, err := v1.FindNexus(uuid, addrs)
+	v1nexus, err := v1.FindNexus(g.ctx, uuid, addrs)
 	if err == nil && v1nexus != nil {
 		var nexus MayastorNexus = v1NexusWrapper{*v1nexus}
 		return &nexus, err
@@ -140,7 +149,7 @@ func (g grpcV1) FindNexus(uuid string, addrs []string) (*MayastorNexus, error) {
 
 func (g grpcV1) ListNvmeControllers(addrs []string) ([]NvmeController, error) {
 	var ncs []NvmeController
-	v1ncs, err := v1.ListNvmeControllers(addrs)
+	v1ncs, err := v1.ListNvmeControllers(g.ctx, addrs)
 	if err == nil {
 		for _, v1nc := range v1ncs {
 			ncs = append(ncs, v1nc)
@@ -150,7 +159,7 @@ func (g grpcV1) ListNvmeControllers(addrs []string) ([]NvmeController, error) {
 }
 
 func (g grpcV1) GetPool(name, addr string) (MayastorPool, error) {
-	v1Pool, err := v1.GetPool(name, addr)
+	v1Pool, err := v1.GetPool(g.ctx, name, addr)
 	if err == nil {
 		var pool MayastorPool = v1Pool
 		return pool, nil
@@ -163,7 +172,7 @@ func (g grpcV1) GetPool(name, addr string) (MayastorPool, error) {
 // of pools found, even if errors have occurred
 func (g grpcV1) ListPools(addrs []string) ([]MayastorPool, error) {
 	var pools []MayastorPool
-	v1pools, err := v1.ListPools(addrs)
+	v1pools, err := v1.ListPools(g.ctx, addrs)
 	for _, v1Pool := range v1pools {
 		pools = append(pools, v1Pool)
 	}
@@ -171,23 +180,23 @@ func (g grpcV1) ListPools(addrs []string) ([]MayastorPool, error) {
 }
 
 func (g grpcV1) DestroyAllPools(addrs []string) error {
-	return v1.DestroyAllPools(addrs)
+	return v1.DestroyAllPools(g.ctx, addrs)
 }
 
 func (g grpcV1) DestroyPool(name, addr string) error {
-	return v1.DestroyPool(name, addr)
+	return v1.DestroyPool(g.ctx, name, addr)
 }
 
 func (g grpcV1) RmReplica(address string, uuid string) error {
-	return v1.RmReplica(address, uuid)
+	return v1.RmReplica(g.ctx, address, uuid)
 }
 
 func (g grpcV1) CreateReplicaExt(address string, uuid string, size uint64, pool string, thin bool) error {
-	return v1.CreateReplicaExt(address, uuid, size, pool, thin)
+	return v1.CreateReplicaExt(g.ctx, address, uuid, size, pool, thin)
 }
 
 func (g grpcV1) CreateReplica(address string, uuid string, size uint64, pool string) error {
-	return v1.CreateReplica(address, uuid, size, pool)
+	return v1.CreateReplica(g.ctx, address, uuid, size, pool)
 }
 
 // ListReplicas list the set of replicas found for the set of IP addresses
@@ -195,7 +204,7 @@ func (g grpcV1) CreateReplica(address string, uuid string, size uint64, pool str
 // of replicas found, even if errors have occurred
 func (g grpcV1) ListReplicas(addrs []string) ([]MayastorReplica, error) {
 	var replicas []MayastorReplica
-	v1Replicas, err := v1.ListReplicas(addrs)
+	v1Replicas, err := v1.ListReplicas(g.ctx, addrs)
 
 	for _, v1Repl := range v1Replicas {
 		replicas = append(replicas, v1Repl)
@@ -205,12 +214,12 @@ func (g grpcV1) ListReplicas(addrs []string) ([]MayastorReplica, error) {
 }
 
 func (g grpcV1) RmNodeReplicas(addrs []string) error {
-	return v1.RmNodeReplicas(addrs)
+	return v1.RmNodeReplicas(g.ctx, addrs)
 }
 
 func (g grpcV1) FindReplicas(uuid string, addrs []string) ([]MayastorReplica, error) {
 	var replicas []MayastorReplica
-	v1Replicas, err := v1.FindReplicas(uuid, addrs)
+	v1Replicas, err := v1.FindReplicas(g.ctx, uuid, addrs)
 	if err == nil {
 		for _, v1Repl := range v1Replicas {
 			replicas = append(replicas, v1Repl)
@@ -222,7 +231,7 @@ func (g grpcV1) FindReplicas(uuid string, addrs []string) ([]MayastorReplica, er
 
 func (g grpcV1) GetRebuildHistory(uuid string, addrs string) (RebuildHistory, error) {
 	var rebuildHistory RebuildHistory
-	v1RebuildHistory, err := v1.GetRebuildHistory(uuid, addrs)
+	v1RebuildHistory, err := v1.GetRebuildHistory(g.ctx, uuid, addrs)
 	if err == nil {
 		rebuildHistory = v1RebuildHistoryWrapper{v1RebuildHistory}
 	}
@@ -231,15 +240,56 @@ func (g grpcV1) GetRebuildHistory(uuid string, addrs string) (RebuildHistory, er
 
 func (g grpcV1) GetRebuildStats(uuid string, dstUri string, addrs string) (RebuildStats, error) {
 	var rebuildStats RebuildStats
-	v1RebuildStats, err := v1.GetRebuildStats(uuid, dstUri, addrs)
+	v1RebuildStats, err := v1.GetRebuildStats(g.ctx, uuid, dstUri, addrs)
 	if err == nil {
 		rebuildStats = v1RebuildStatsWrapper{v1RebuildStats}
 	}
 	return rebuildStats, err
 }
 
+func (g grpcV1) StartRebuild(uuid string, childUri string, addrs string) error {
+	return v1.StartRebuild(g.ctx, uuid, childUri, addrs)
+}
+
+func (g grpcV1) StopRebuild(uuid string, childUri string, addrs string) error {
+	return v1.StopRebuild(g.ctx, uuid, childUri, addrs)
+}
+
+func (g grpcV1) PauseRebuild(uuid string, childUri string, addrs string) error {
+	return v1.PauseRebuild(g.ctx, uuid, childUri, addrs)
+}
+
+func (g grpcV1) ResumeRebuild(uuid string, childUri string, addrs string) error {
+	return v1.ResumeRebuild(g.ctx, uuid, childUri, addrs)
+}
+
+func (g grpcV1) GetRebuildState(uuid string, childUri string, addrs string) (string, error) {
+	return v1.GetRebuildState(g.ctx, uuid, childUri, addrs)
+}
+
+func (g grpcV1) ListRebuildHistory(addrs string) (map[string]RebuildHistory, error) {
+	var histories map[string]RebuildHistory
+	v1Histories, err := v1.ListRebuildHistory(g.ctx, addrs)
+	if err == nil {
+		histories = make(map[string]RebuildHistory)
+		for nexusUuid, history := range v1Histories {
+			histories[nexusUuid] = v1RebuildHistoryWrapper{history}
+		}
+	}
+	return histories, err
+}
+
+func (g grpcV1) GetNvmeAnaState(uuid string, addrs string) (NvmeAnaState, error) {
+	anaState, err := v1.GetNvmeAnaState(g.ctx, uuid, addrs)
+	return NvmeAnaState(anaState), err
+}
+
+func (g grpcV1) SetNvmeAnaState(uuid string, anaState NvmeAnaState, addrs string) error {
+	return v1.SetNvmeAnaState(g.ctx, uuid, string(anaState), addrs)
+}
+
 func (g grpcV1) CheckAndSetConnect(nodes []string) error {
-	return v1.CheckAndSetConnect(nodes)
+	return v1.CheckAndSetConnect(g.ctx, nodes)
 }
 
 func (g grpcV1) CanConnect() bool {
@@ -247,21 +297,109 @@ func (g grpcV1) CanConnect() bool {
 }
 
 func (g grpcV1) WipeReplica(address string, replicaUUID string, poolName string) error {
-	return v1.WipeReplica(address, replicaUUID, poolName)
+	return v1.WipeReplica(g.ctx, address, replicaUUID, poolName)
 }
 
 func (g grpcV1) ChecksumReplica(address string, replicaUUID string, poolName string) (uint32, error) {
-	return v1.ChecksumReplica(address, replicaUUID, poolName)
+	return v1.ChecksumReplica(g.ctx, address, replicaUUID, poolName)
 }
 
 func (g grpcV1) ShareBdev(address string, bdevUuid string) (string, error) {
-	return v1.ShareBdev(address, bdevUuid)
+	return v1.ShareBdev(g.ctx, address, bdevUuid)
 }
 
 func (g grpcV1) UnshareBdev(address string, bdevUuid string) error {
-	return v1.UnshareBdev(address, bdevUuid)
+	return v1.UnshareBdev(g.ctx, address, bdevUuid)
 }
 
 func (g grpcV1) ResetIOStats(address string) error {
-	return v1.ResetIOStats(address)
+	return v1.ResetIOStats(g.ctx, address)
+}
+
+func (g grpcV1) AddFaultInjection(address string, uri string) error {
+	return v1.AddFaultInjection(g.ctx, address, uri)
+}
+
+func (g grpcV1) RemoveFaultInjection(address string, uri string) error {
+	return v1.RemoveFaultInjection(g.ctx, address, uri)
+}
+
+func (g grpcV1) ListFaultInjections(address string) ([]FaultInjection, error) {
+	var injections []FaultInjection
+	v1Injections, err := v1.ListFaultInjections(g.ctx, address)
+	for _, v1Injection := range v1Injections {
+		injections = append(injections, v1Injection)
+	}
+	return injections, err
+}
+
+func (g grpcV1) ExportPool(name, addr string) error {
+	return v1.ExportPool(g.ctx, name, addr)
+}
+
+func (g grpcV1) ImportPool(name string, disks []string, addr string) (MayastorPool, error) {
+	v1Pool, err := v1.ImportPool(g.ctx, name, disks, addr)
+	if err == nil {
+		var pool MayastorPool = v1Pool
+		return pool, nil
+	}
+	return nil, err
+}
+
+func (g grpcV1) ResizeReplica(address string, uuid string, size uint64) (MayastorReplica, error) {
+	v1Replica, err := v1.ResizeReplica(g.ctx, address, uuid, size)
+	if err == nil {
+		var replica MayastorReplica = v1Replica
+		return replica, nil
+	}
+	return nil, err
+}
+
+func (g grpcV1) CreateReplicaSnapshot(address string, replicaUuid string, snapshotUuid string, snapshotName string, entityId string, txnId string) (Snapshot, error) {
+	v1Snapshot, err := v1.CreateReplicaSnapshot(g.ctx, address, replicaUuid, snapshotUuid, snapshotName, entityId, txnId)
+	if err == nil {
+		var snapshot Snapshot = v1Snapshot
+		return snapshot, nil
+	}
+	return nil, err
+}
+
+func (g grpcV1) CreateNexusSnapshot(address string, nexusUuid string, snapshotName string, entityId string, txnId string, replicaSnapshots map[string]string) (NexusSnapshot, error) {
+	v1NexusSnapshot, err := v1.CreateNexusSnapshot(g.ctx, address, nexusUuid, snapshotName, entityId, txnId, replicaSnapshots)
+	if err == nil {
+		var snapshot NexusSnapshot = v1NexusSnapshot
+		return snapshot, nil
+	}
+	return nil, err
+}
+
+func (g grpcV1) ListSnapshots(address string) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	v1Snapshots, err := v1.ListSnapshots(g.ctx, address)
+	for _, v1Snapshot := range v1Snapshots {
+		snapshots = append(snapshots, v1Snapshot)
+	}
+	return snapshots, err
+}
+
+func (g grpcV1) DestroySnapshot(address string, snapshotUuid string, poolName string) error {
+	return v1.DestroySnapshot(g.ctx, address, snapshotUuid, poolName)
+}
+
+func (g grpcV1) CreateSnapshotClone(address string, snapshotUuid string, cloneName string, cloneUuid string) (MayastorReplica, error) {
+	v1Clone, err := v1.CreateSnapshotClone(g.ctx, address, snapshotUuid, cloneName, cloneUuid)
+	if err == nil {
+		var clone MayastorReplica = v1Clone
+		return clone, nil
+	}
+	return nil, err
+}
+
+func (g grpcV1) ListSnapshotClones(address string, snapshotUuid string) ([]MayastorReplica, error) {
+	var clones []MayastorReplica
+	v1Clones, err := v1.ListSnapshotClones(g.ctx, address, snapshotUuid)
+	for _, v1Clone := range v1Clones {
+		clones = append(clones, v1Clone)
+	}
+	return clones, err
 }
@@ -1,6 +1,7 @@
 package mayastorclient
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -10,12 +11,24 @@ import (
 type GrpcInterface interface {
 	Version() string
 
+	// WithContext returns the interface with its gRPC calls bounded by ctx
+	WithContext(ctx context.Context) GrpcInterface
+
 	// Nexus abstraction
 	ListNexuses(addrs []string) ([]MayastorNexus, error)
 	FaultNexusChild(address string, Uuid string, Uri string) error
 	FindNexus(uuid string, addrs []string) (*MayastorNexus, error)
 	GetRebuildHistory(uuid string, addrs string) (RebuildHistory, error)            // uuid of the nexus
 	GetRebuildStats(uuid string, dstUri string, addrs string) (RebuildStats, error) // uuid of the nexus
+	// rebuild control, uuid of the nexus and uri of the child being rebuilt
+	StartRebuild(uuid string, childUri string, addrs string) error
+	StopRebuild(uuid string, childUri string, addrs string) error
+	PauseRebuild(uuid string, childUri string, addrs string) error
+	ResumeRebuild(uuid string, childUri string, addrs string) error
+	GetRebuildState(uuid string, childUri string, addrs string) (string, error)
+	ListRebuildHistory(addrs string) (map[string]RebuildHistory, error)     // by uuid of the nexus
+	GetNvmeAnaState(uuid string, addrs string) (NvmeAnaState, error)        // uuid of the nexus
+	SetNvmeAnaState(uuid string, anaState NvmeAnaState, addrs string) error // uuid of the nexus
 
 	// Nvme controller abstraction
 	ListNvmeControllers(addrs []string) ([]NvmeController, error)
@@ -25,6 +38,8 @@ type GrpcInterface interface {
 	ListPools(addrs []string) ([]MayastorPool, error)
 	DestroyAllPools(addrs []string) error
 	DestroyPool(name, addr string) error
+	ExportPool(name, addr string) error
+	ImportPool(name string, disks []string, addr string) (MayastorPool, error)
 
 	// Replica abstraction
 	RmReplica(address string, uuid string) error
@@ -35,6 +50,15 @@ type GrpcInterface interface {
 	FindReplicas(uuid string, addrs []string) ([]MayastorReplica, error)
 	WipeReplica(address string, replicaUUID string, poolName string) error
 	ChecksumReplica(address string, replicaUUID string, poolName string) (uint32, error)
+	ResizeReplica(address string, uuid string, size uint64) (MayastorReplica, error)
+
+	// Snapshot abstraction
+	CreateReplicaSnapshot(address string, replicaUuid string, snapshotUuid string, snapshotName string, entityId string, txnId string) (Snapshot, error)
+	CreateNexusSnapshot(address string, nexusUuid string, snapshotName string, entityId string, txnId string, replicaSnapshots map[string]string) (NexusSnapshot, error)
+	ListSnapshots(address string) ([]Snapshot, error)
+	DestroySnapshot(address string, snapshotUuid string, poolName string) error
+	CreateSnapshotClone(address string, snapshotUuid string, cloneName string, cloneUuid string) (MayastorReplica, error)
+	ListSnapshotClones(address string, snapshotUuid string) ([]MayastorReplica, error)
 
 	//bdev abstraction
 	ShareBdev(address string, bdevUuid string) (string, error)
@@ -46,6 +70,11 @@ type GrpcInterface interface {
 
 	// io stats
 	ResetIOStats(address string) error
+
+	// fault injection, uri is an io-engine fault injection uri, see FaultInjectionBuilder
+	AddFaultInjection(address string, uri string) error
+	RemoveFaultInjection(address string, uri string) error
+	ListFaultInjections(address string) ([]FaultInjection, error)
 }
 
 // The default grpc interface
@@ -76,14 +105,24 @@ func Initialise(nodes []string) {
 func GetGrpcIfc(version string) (GrpcInterface, error) {
 	switch version {
 	case "V0", "v0":
-		return grpcV0{}, nil
+		return grpcV0{ctx: context.Background()}, nil
 	case "V1", "v1":
-		return grpcV1{}, nil
+		return grpcV1{ctx: context.Background()}, nil
 	default:
 		return nil, fmt.Errorf("unknown version")
 	}
 }
 
+// WithContext returns the default grpc interface with its gRPC calls bounded by ctx,
+// so that calls in progress are cancelled when ctx is done, eg: with a Ginkgo SpecContext.
+// Calls are also bounded by the gRPC call timeout of the configuration.
+func WithContext(ctx context.Context) (GrpcInterface, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.WithContext(ctx), nil
+}
+
 func Version() string {
 	if defaultGrpcIfc == nil {
 		return ""
@@ -149,6 +188,22 @@ func DestroyPool(name, addr string) error {
 }
 */
 
+// ExportPool exports the pool from the io-engine, the data on the disks of the pool is retained
+func ExportPool(name, addr string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ExportPool(name, addr)
+}
+
+// ImportPool imports the pool from the disks, eg: a pool which has been exported
+func ImportPool(name string, disks []string, addr string) (MayastorPool, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ImportPool(name, disks, addr)
+}
+
 func RmReplica(address string, uuid string) error {
 	if defaultGrpcIfc == nil {
 		return fmt.Errorf("mayastor client package has not been initialised")
@@ -205,6 +260,70 @@ func GetRebuildStats(uuid string, dstUri string, addrs string) (RebuildStats, er
 	return defaultGrpcIfc.GetRebuildStats(uuid, dstUri, addrs)
 }
 
+// StartRebuild starts the rebuild of the child of the nexus
+func StartRebuild(uuid string, childUri string, addrs string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.StartRebuild(uuid, childUri, addrs)
+}
+
+// StopRebuild stops the rebuild of the child of the nexus
+func StopRebuild(uuid string, childUri string, addrs string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.StopRebuild(uuid, childUri, addrs)
+}
+
+// PauseRebuild pauses the rebuild of the child of the nexus
+func PauseRebuild(uuid string, childUri string, addrs string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.PauseRebuild(uuid, childUri, addrs)
+}
+
+// ResumeRebuild resumes the paused rebuild of the child of the nexus
+func ResumeRebuild(uuid string, childUri string, addrs string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ResumeRebuild(uuid, childUri, addrs)
+}
+
+// GetRebuildState returns the state of the rebuild of the child of the nexus, eg: running, paused, completed
+func GetRebuildState(uuid string, childUri string, addrs string) (string, error) {
+	if defaultGrpcIfc == nil {
+		return "", fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.GetRebuildState(uuid, childUri, addrs)
+}
+
+// ListRebuildHistory returns the rebuild history of all nexuses on the io-engine by uuid of the nexus
+func ListRebuildHistory(addrs string) (map[string]RebuildHistory, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ListRebuildHistory(addrs)
+}
+
+// GetNvmeAnaState returns the NVMe ANA state of the nexus
+func GetNvmeAnaState(uuid string, addrs string) (NvmeAnaState, error) {
+	if defaultGrpcIfc == nil {
+		return "", fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.GetNvmeAnaState(uuid, addrs)
+}
+
+// SetNvmeAnaState sets the NVMe ANA state of the nexus, the state reported to the initiators
+func SetNvmeAnaState(uuid string, anaState NvmeAnaState, addrs string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.SetNvmeAnaState(uuid, anaState, addrs)
+}
+
 func CanConnect() bool {
 	if defaultGrpcIfc != nil {
 		return defaultGrpcIfc.CanConnect()
@@ -228,6 +347,63 @@ func ChecksumReplica(address string, replicaUUID string, poolName string) (uint3
 	return defaultGrpcIfc.ChecksumReplica(address, replicaUUID, poolName)
 }
 
+// ResizeReplica grows the replica to size bytes
+func ResizeReplica(address string, uuid string, size uint64) (MayastorReplica, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ResizeReplica(address, uuid, size)
+}
+
+// CreateReplicaSnapshot creates a snapshot of the replica
+func CreateReplicaSnapshot(address string, replicaUuid string, snapshotUuid string, snapshotName string, entityId string, txnId string) (Snapshot, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.CreateReplicaSnapshot(address, replicaUuid, snapshotUuid, snapshotName, entityId, txnId)
+}
+
+// CreateNexusSnapshot creates a snapshot of the replicas of the nexus, replicaSnapshots maps
+// the uuid of each replica to the uuid of its snapshot, an empty snapshot uuid skips the replica
+func CreateNexusSnapshot(address string, nexusUuid string, snapshotName string, entityId string, txnId string, replicaSnapshots map[string]string) (NexusSnapshot, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.CreateNexusSnapshot(address, nexusUuid, snapshotName, entityId, txnId, replicaSnapshots)
+}
+
+// ListSnapshots lists the snapshots held by the io-engine
+func ListSnapshots(address string) ([]Snapshot, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ListSnapshots(address)
+}
+
+// DestroySnapshot destroys the snapshot in the pool
+func DestroySnapshot(address string, snapshotUuid string, poolName string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.DestroySnapshot(address, snapshotUuid, poolName)
+}
+
+// CreateSnapshotClone creates a clone replica of the snapshot
+func CreateSnapshotClone(address string, snapshotUuid string, cloneName string, cloneUuid string) (MayastorReplica, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.CreateSnapshotClone(address, snapshotUuid, cloneName, cloneUuid)
+}
+
+// ListSnapshotClones lists the clones of the snapshot, all clones if snapshotUuid is empty
+func ListSnapshotClones(address string, snapshotUuid string) ([]MayastorReplica, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ListSnapshotClones(address, snapshotUuid)
+}
+
 func ShareBdev(address string, bdevUuid string) (string, error) {
 	if defaultGrpcIfc == nil {
 		return "", fmt.Errorf("mayastor client package has not been initialised")
@@ -251,3 +427,28 @@ func ResetIOStats(address string) error {
 	logf.Log.Info("reset io stats")
 	return defaultGrpcIfc.ResetIOStats(address)
 }
+
+// AddFaultInjection adds the fault injection defined by the uri to the io-engine at the address,
+// see FaultInjectionBuilder and FaultInjectionScope
+func AddFaultInjection(address string, uri string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.AddFaultInjection(address, uri)
+}
+
+// RemoveFaultInjection removes the fault injection added with the uri from the io-engine at the address
+func RemoveFaultInjection(address string, uri string) error {
+	if defaultGrpcIfc == nil {
+		return fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.RemoveFaultInjection(address, uri)
+}
+
+// ListFaultInjections lists the fault injections of the io-engine at the address
+func ListFaultInjections(address string) ([]FaultInjection, error) {
+	if defaultGrpcIfc == nil {
+		return nil, fmt.Errorf("mayastor client package has not been initialised")
+	}
+	return defaultGrpcIfc.ListFaultInjections(address)
+}
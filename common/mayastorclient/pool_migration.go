@@ -0,0 +1,128 @@
+package mayastorclient
+
+import (
+	"fmt"
+
+	"github.com/openebs/openebs-e2e/common"
+	"github.com/openebs/openebs-e2e/common/controlplane"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// poolReplica is the identity and content of a replica of a pool
+type poolReplica struct {
+	uuid     string
+	size     uint64
+	checksum uint32
+}
+
+// listPoolReplicas returns the replicas of the pool on the io-engine at the address by uuid,
+// with the checksums of their content
+func listPoolReplicas(poolName string, address string) (map[string]poolReplica, error) {
+	replicas, err := ListReplicas([]string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicas on %s, error: %w", address, err)
+	}
+	poolReplicas := make(map[string]poolReplica)
+	for _, replica := range replicas {
+		if replica.GetPool() != poolName {
+			continue
+		}
+		checksum, err := ChecksumReplica(address, replica.GetUuid(), poolName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum replica %s on %s, error: %w", replica.GetUuid(), address, err)
+		}
+		poolReplicas[replica.GetUuid()] = poolReplica{
+			uuid:     replica.GetUuid(),
+			size:     replica.GetSize(),
+			checksum: checksum,
+		}
+	}
+	return poolReplicas, nil
+}
+
+// checkPoolUnmanaged returns an error if the control plane manages the pool,
+// ie: a DiskPool with the name of the pool exists
+func checkPoolUnmanaged(poolName string) error {
+	msps, err := controlplane.ListMsPools()
+	if err != nil {
+		return fmt.Errorf("failed to list control plane pools, error: %w", err)
+	}
+	for _, msp := range msps {
+		if msp.Name == poolName {
+			return fmt.Errorf("pool %s is managed by the control plane on node %s", poolName, msp.Spec.Node)
+		}
+	}
+	return nil
+}
+
+// VerifyPoolExportImport exports the pool from the io-engine at the address, re-imports it
+// from the same disks and verifies that the uuids, sizes and checksums of the replicas of
+// the pool survived, the replicas should not be in use, eg: their volumes unpublished.
+// The pool must not be managed by the control plane, ie: it must not be a DiskPool,
+// otherwise the control plane re-imports the exported pool, an error is returned if it is.
+func VerifyPoolExportImport(poolName string, address string) error {
+	if err := checkPoolUnmanaged(poolName); err != nil {
+		return err
+	}
+	pool, err := GetPool(poolName, address)
+	if err != nil {
+		return fmt.Errorf("failed to get pool %s on %s, error: %w", poolName, address, err)
+	}
+	disks := pool.GetDisks()
+	before, err := listPoolReplicas(poolName, address)
+	if err != nil {
+		return err
+	}
+	logf.Log.Info("VerifyPoolExportImport", "pool", poolName, "address", address, "disks", disks, "replicas", len(before))
+
+	if err = ExportPool(poolName, address); err != nil {
+		return fmt.Errorf("failed to export pool %s on %s, error: %w", poolName, address, err)
+	}
+	pools, err := ListPools([]string{address})
+	if err != nil {
+		return fmt.Errorf("failed to list pools on %s, error: %w", address, err)
+	}
+	var errs common.ErrorAccumulator
+	for _, p := range pools {
+		if p.GetName() == poolName {
+			errs.Accumulate(fmt.Errorf("pool %s is listed on %s after export", poolName, address))
+		}
+	}
+
+	pool, err = ImportPool(poolName, disks, address)
+	if err != nil {
+		errs.Accumulate(fmt.Errorf("failed to import pool %s on %s, error: %w", poolName, address, err))
+		return errs.GetError()
+	}
+	if !pool.IsPoolOnline() {
+		errs.Accumulate(fmt.Errorf("imported pool %s on %s is %s", poolName, address, pool.GetStateString()))
+	}
+
+	after, err := listPoolReplicas(poolName, address)
+	if err != nil {
+		errs.Accumulate(err)
+		return errs.GetError()
+	}
+	for uuid, replica := range before {
+		imported, ok := after[uuid]
+		if !ok {
+			errs.Accumulate(fmt.Errorf("replica %s of pool %s is missing after import", uuid, poolName))
+			continue
+		}
+		if imported.size != replica.size {
+			errs.Accumulate(fmt.Errorf("replica %s of pool %s size changed from %d to %d on import",
+				uuid, poolName, replica.size, imported.size))
+		}
+		if imported.checksum != replica.checksum {
+			errs.Accumulate(fmt.Errorf("replica %s of pool %s checksum changed from %x to %x on import",
+				uuid, poolName, replica.checksum, imported.checksum))
+		}
+	}
+	for uuid := range after {
+		if _, ok := before[uuid]; !ok {
+			errs.Accumulate(fmt.Errorf("replica %s of pool %s appeared after import", uuid, poolName))
+		}
+	}
+	return errs.GetError()
+}
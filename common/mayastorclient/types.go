@@ -85,6 +85,47 @@ type RebuildStats interface {
 	StartTime() *timestamppb.Timestamp
 }
 
+// FaultInjection is a fault injection of the io-engine
+type FaultInjection interface {
+	GetUri() string
+	GetDeviceName() string
+	IsActive() bool
+}
+
+// Snapshot is a snapshot of a replica held by the io-engine
+type Snapshot interface {
+	GetUuid() string
+	GetName() string
+	GetSourceUuid() string // uuid of the replica
+	GetPoolName() string
+	GetPoolUuid() string
+	GetSize() uint64
+	GetNumClones() uint64
+	GetEntityId() string // uuid of the volume
+	GetTxnId() string
+	IsValid() bool
+	IsReadyAsSource() bool
+	IsDiscarded() bool
+}
+
+// NexusSnapshot is the result of a snapshot of the replicas of a nexus by replica uuid
+type NexusSnapshot interface {
+	GetReplicasDone() []string
+	GetReplicasFailed() map[string]uint32 // status code of the failure
+	GetReplicasSkipped() []string
+}
+
+// NvmeAnaState is the NVMe ANA state of a nexus, named as reported by nvme-cli on the initiator
+type NvmeAnaState string
+
+const (
+	NvmeAnaOptimized      NvmeAnaState = "optimized"
+	NvmeAnaNonOptimized   NvmeAnaState = "non-optimized"
+	NvmeAnaInaccessible   NvmeAnaState = "inaccessible"
+	NvmeAnaPersistentLoss NvmeAnaState = "persistent-loss"
+	NvmeAnaChange         NvmeAnaState = "change"
+)
+
 type MayastorReplicaArray []MayastorReplica
 
 func (msr MayastorReplicaArray) Len() int           { return len(msr) }
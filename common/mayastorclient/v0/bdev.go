@@ -13,7 +13,7 @@ import (
 )
 
 // ShareBdev share a bdev with uuid
-func ShareBdev(address string, bdevUuid string) (string, error) {
+func ShareBdev(ctx context.Context, address string, bdevUuid string) (string, error) {
 	logf.Log.Info("ShareBdev", "address", address, "bdevUuid", bdevUuid)
 	var bdevUri string
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
@@ -30,14 +30,14 @@ func ShareBdev(address string, bdevUuid string) (string, error) {
 	}(conn)
 	c := mayastorGrpc.NewBdevRpcClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.BdevShareRequest{
 		Name: bdevUuid,
 	}
 	var response *mayastorGrpc.BdevShareReply
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.Share(ctx, &req)
 		return err
 	})
@@ -51,7 +51,7 @@ func ShareBdev(address string, bdevUuid string) (string, error) {
 }
 
 // UnshareBdev unshare a bdev with uuid
-func UnshareBdev(address string, bdevUuid string) error {
+func UnshareBdev(ctx context.Context, address string, bdevUuid string) error {
 	logf.Log.Info("UnshareBdev", "address", address, "bdevUuid", bdevUuid)
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
 	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -67,13 +67,13 @@ func UnshareBdev(address string, bdevUuid string) error {
 	}(conn)
 	c := mayastorGrpc.NewBdevRpcClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.CreateReply{
 		Name: bdevUuid,
 	}
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.Unshare(ctx, &req)
 		return err
 	})
@@ -84,7 +84,7 @@ func (msn V0MayastorNexus) GetChildren() []v0MayastorNexusChild {
 	return children
 }
 
-func listNexuses(address string) ([]V0MayastorNexus, error) {
+func listNexuses(ctx context.Context, address string) ([]V0MayastorNexus, error) {
 	var nexusInfos []V0MayastorNexus
 	var err error
 
@@ -102,11 +102,11 @@ func listNexuses(address string) ([]V0MayastorNexus, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListNexusV2Reply
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListNexusV2(ctx, &null)
 		return err
 	})
@@ -138,11 +138,11 @@ func listNexuses(address string) ([]V0MayastorNexus, error) {
 
 // ListNexuses given a list of node ip addresses, enumerate the set of nexuses on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListNexuses(addrs []string) ([]V0MayastorNexus, error) {
+func ListNexuses(ctx context.Context, addrs []string) ([]V0MayastorNexus, error) {
 	var accErr error
 	var nexusInfos []V0MayastorNexus
 	for _, address := range addrs {
-		nexusInfo, err := listNexuses(address)
+		nexusInfo, err := listNexuses(ctx, address)
 		if err == nil {
 			nexusInfos = append(nexusInfos, nexusInfo...)
 		} else {
@@ -156,7 +156,7 @@ func ListNexuses(addrs []string) ([]V0MayastorNexus, error) {
 	return nexusInfos, accErr
 }
 
-func FaultNexusChild(address string, Uuid string, Uri string) error {
+func FaultNexusChild(ctx context.Context, address string, Uuid string, Uri string) error {
 	var err error
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
 	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -172,7 +172,7 @@ func FaultNexusChild(address string, Uuid string, Uri string) error {
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	faultRequest := mayastorGrpc.FaultNexusChildRequest{
@@ -180,7 +180,7 @@ func FaultNexusChild(address string, Uuid string, Uri string) error {
 		Uri:  Uri,
 	}
 	var response *mayastorGrpc.Null
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.FaultNexusChild(ctx, &faultRequest)
 		return err
 	})
@@ -199,10 +199,10 @@ func FaultNexusChild(address string, Uuid string, Uri string) error {
 
 // FindNexus given a list of node ip addresses, return the V0MayastorNexus with matching uuid
 // returns accumulated errors if gRPC communication failed.
-func FindNexus(uuid string, addrs []string) (*V0MayastorNexus, error) {
+func FindNexus(ctx context.Context, uuid string, addrs []string) (*V0MayastorNexus, error) {
 	var accErr error
 	for _, address := range addrs {
-		nexusInfos, err := listNexuses(address)
+		nexusInfos, err := listNexuses(ctx, address)
 		if err == nil {
 			for _, ni := range nexusInfos {
 				if ni.GetUuid() == uuid {
@@ -222,7 +222,7 @@ func FindNexus(uuid string, addrs []string) (*V0MayastorNexus, error) {
 
 // GetRebuildStats given a node ip address, return the V0RebuildHistory with matching uuid and uri
 // returns accumulated errors if gRPC communication failed.
-func GetRebuildStats(uuid string, dstUri string, addrs string) (V0RebuildStatsReply, error) {
+func GetRebuildStats(ctx context.Context, uuid string, dstUri string, addrs string) (V0RebuildStatsReply, error) {
 	var err error
 	addrPort := fmt.Sprintf("%s:%d", addrs, mayastorPort)
 	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -239,7 +239,7 @@ func GetRebuildStats(uuid string, dstUri string, addrs string) (V0RebuildStatsRe
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	rebuildStatsRequest := mayastorGrpc.RebuildStatsRequest{
@@ -247,7 +247,7 @@ func GetRebuildStats(uuid string, dstUri string, addrs string) (V0RebuildStatsRe
 		Uri:  dstUri,
 	}
 	var response *mayastorGrpc.RebuildStatsReply
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.GetRebuildStats(ctx, &rebuildStatsRequest)
 		return err
 	})
@@ -39,7 +39,7 @@ func (nvmectlr v0NvmeController) GetBlkSize() uint32 {
 	return nvmectlr.BlkSize
 }
 
-func listNvmeController(address string) ([]v0NvmeController, error) {
+func listNvmeController(ctx context.Context, address string) ([]v0NvmeController, error) {
 	var nvmeControllers []v0NvmeController
 	var err error
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
@@ -56,11 +56,11 @@ func listNvmeController(address string) ([]v0NvmeController, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListNvmeControllersReply
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListNvmeControllers(ctx, &null)
 		return err
 	})
@@ -88,11 +88,11 @@ func listNvmeController(address string) ([]v0NvmeController, error) {
 
 // ListNvmeControllers given a list of node ip addresses, enumerate the set of nvmeControllers on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListNvmeControllers(addrs []string) ([]v0NvmeController, error) {
+func ListNvmeControllers(ctx context.Context, addrs []string) ([]v0NvmeController, error) {
 	var accErr error
 	var nvmeControllers []v0NvmeController
 	for _, address := range addrs {
-		nvmeController, err := listNvmeController(address)
+		nvmeController, err := listNvmeController(ctx, address)
 		if err == nil {
 			nvmeControllers = append(nvmeControllers, nvmeController...)
 		} else {
@@ -73,7 +73,7 @@ func (msp v0MayastorPool) ToCrdState() string {
 	}
 }
 
-func listPool(address string) ([]v0MayastorPool, error) {
+func listPool(ctx context.Context, address string) ([]v0MayastorPool, error) {
 	var poolInfos []v0MayastorPool
 	var err error
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
@@ -90,11 +90,11 @@ func listPool(address string) ([]v0MayastorPool, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListPoolsReply
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListPools(ctx, &null)
 		return err
 	})
@@ -122,8 +122,8 @@ func listPool(address string) ([]v0MayastorPool, error) {
 	return poolInfos, err
 }
 
-func GetPool(name, addr string) (*v0MayastorPool, error) {
-	poolInfo, err := listPool(addr)
+func GetPool(ctx context.Context, name, addr string) (*v0MayastorPool, error) {
+	poolInfo, err := listPool(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -137,11 +137,11 @@ func GetPool(name, addr string) (*v0MayastorPool, error) {
 
 // ListPools given a list of node ip addresses, enumerate the set of pools on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListPools(addrs []string) ([]v0MayastorPool, error) {
+func ListPools(ctx context.Context, addrs []string) ([]v0MayastorPool, error) {
 	var accErr error
 	var poolInfos []v0MayastorPool
 	for _, address := range addrs {
-		poolInfo, err := listPool(address)
+		poolInfo, err := listPool(ctx, address)
 		if err == nil {
 			poolInfos = append(poolInfos, poolInfo...)
 		} else {
@@ -155,10 +155,10 @@ func ListPools(addrs []string) ([]v0MayastorPool, error) {
 	return poolInfos, accErr
 }
 
-func DestroyAllPools(addrs []string) error {
+func DestroyAllPools(ctx context.Context, addrs []string) error {
 
 	for _, addr := range addrs {
-		poolInfo, err := listPool(addr)
+		poolInfo, err := listPool(ctx, addr)
 		if err != nil {
 			return err
 		}
@@ -166,7 +166,7 @@ func DestroyAllPools(addrs []string) error {
 			continue
 		}
 		for _, pool := range poolInfo {
-			err = DestroyPool(pool.GetName(), addr)
+			err = DestroyPool(ctx, pool.GetName(), addr)
 			if err != nil {
 				return err
 			}
@@ -176,7 +176,7 @@ func DestroyAllPools(addrs []string) error {
 	return nil
 }
 
-func DestroyPool(name, addr string) error {
+func DestroyPool(ctx context.Context, name, addr string) error {
 	var err error
 	addrPort := fmt.Sprintf("%s:%d", addr, mayastorPort)
 	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -192,7 +192,7 @@ func DestroyPool(name, addr string) error {
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	_, err = c.DestroyPool(ctx, &mayastorGrpc.DestroyPoolRequest{Name: name})
@@ -50,7 +50,7 @@ func (msr v0MayastorReplica) GetUri() string {
 	return msr.Uri
 }
 
-func listReplica(address string) ([]v0MayastorReplica, error) {
+func listReplica(ctx context.Context, address string) ([]v0MayastorReplica, error) {
 	var replicaInfos []v0MayastorReplica
 	var err error
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
@@ -67,11 +67,11 @@ func listReplica(address string) ([]v0MayastorReplica, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListReplicasReply
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListReplicas(ctx, &null)
 		return err
 	})
@@ -101,7 +101,7 @@ func listReplica(address string) ([]v0MayastorReplica, error) {
 }
 
 // RmReplica remove a replica identified by node and uuid
-func RmReplica(address string, uuid string) error {
+func RmReplica(ctx context.Context, address string, uuid string) error {
 	logf.Log.Info("RmReplica", "address", address, "UUID", uuid)
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
 	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -117,11 +117,11 @@ func RmReplica(address string, uuid string) error {
 	}(conn)
 	c := mayastorGrpc.NewMayastorClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.DestroyReplicaRequest{Uuid: uuid}
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.DestroyReplica(ctx, &req)
 		return err
 	})
@@ -130,7 +130,7 @@ func RmReplica(address string, uuid string) error {
 }
 
 // CreateReplicaExt create a replica on a mayastor node
-func CreateReplicaExt(address string, uuid string, size uint64, pool string, thin bool) error {
+func CreateReplicaExt(ctx context.Context, address string, uuid string, size uint64, pool string, thin bool) error {
 	shareProto := mayastorGrpc.ShareProtocolReplica_REPLICA_NVMF
 	logf.Log.Info("CreateReplica", "address", address, "UUID", uuid, "size", size, "pool", pool, "Thin", thin, "Share", shareProto)
 	addrPort := fmt.Sprintf("%s:%d", address, mayastorPort)
@@ -148,7 +148,7 @@ func CreateReplicaExt(address string, uuid string, size uint64, pool string, thi
 		}
 	}(conn)
 	c := mayastorGrpc.NewMayastorClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.CreateReplicaRequest{
@@ -159,7 +159,7 @@ func CreateReplicaExt(address string, uuid string, size uint64, pool string, thi
 		Share: shareProto,
 	}
 
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.CreateReplica(ctx, &req)
 		return err
 	})
@@ -171,17 +171,17 @@ func CreateReplicaExt(address string, uuid string, size uint64, pool string, thi
 //
 //	 thin fixed to false and share fixed to NVMF.
 //	Other parameters must be specified
-func CreateReplica(address string, uuid string, size uint64, pool string) error {
-	return CreateReplicaExt(address, uuid, size, pool, false)
+func CreateReplica(ctx context.Context, address string, uuid string, size uint64, pool string) error {
+	return CreateReplicaExt(ctx, address, uuid, size, pool, false)
 }
 
 // ListReplicas given a list of node ip addresses, enumerate the set of replicas on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListReplicas(addrs []string) ([]v0MayastorReplica, error) {
+func ListReplicas(ctx context.Context, addrs []string) ([]v0MayastorReplica, error) {
 	var accErr error
 	var replicaInfos []v0MayastorReplica
 	for _, address := range addrs {
-		replicaInfo, err := listReplica(address)
+		replicaInfo, err := listReplica(ctx, address)
 		if err == nil {
 			replicaInfos = append(replicaInfos, replicaInfo...)
 		} else {
@@ -197,13 +197,13 @@ func ListReplicas(addrs []string) ([]v0MayastorReplica, error) {
 
 // RmNodeReplicas given a list of node ip addresses, delete the set of replicas on mayastor using gRPC on each of those nodes
 // returns errors if gRPC communication failed.
-func RmNodeReplicas(addrs []string) error {
+func RmNodeReplicas(ctx context.Context, addrs []string) error {
 	var accErr error
 	for _, address := range addrs {
-		replicaInfos, err := listReplica(address)
+		replicaInfos, err := listReplica(ctx, address)
 		if err == nil {
 			for _, replicaInfo := range replicaInfos {
-				err = RmReplica(address, replicaInfo.GetUuid())
+				err = RmReplica(ctx, address, replicaInfo.GetUuid())
 			}
 		}
 		if err != nil {
@@ -219,11 +219,11 @@ func RmNodeReplicas(addrs []string) error {
 
 // FindReplicas given a list of node ip addresses, enumerate the set of replicas on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func FindReplicas(uuid string, addrs []string) ([]v0MayastorReplica, error) {
+func FindReplicas(ctx context.Context, uuid string, addrs []string) ([]v0MayastorReplica, error) {
 	var accErr error
 	var replicaInfos []v0MayastorReplica
 	for _, address := range addrs {
-		replicaInfo, err := listReplica(address)
+		replicaInfo, err := listReplica(ctx, address)
 		if err == nil {
 			for _, repl := range replicaInfo {
 				if repl.GetUuid() == uuid {
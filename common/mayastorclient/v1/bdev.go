@@ -8,16 +8,14 @@ import (
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // ShareBdev share a bdev with uuid
-func ShareBdev(address string, bdevUuid string) (string, error) {
+func ShareBdev(ctx context.Context, address string, bdevUuid string) (string, error) {
 	logf.Log.Info("ShareBdev", "address", address, "bdevUuid", bdevUuid)
 	var bdevShareUri string
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("ShareBdev", "error", err)
 		return bdevShareUri, err
@@ -30,7 +28,7 @@ func ShareBdev(address string, bdevUuid string) (string, error) {
 	}(conn)
 	c := mayastorGrpc.NewBdevRpcClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.BdevShareRequest{
@@ -38,7 +36,7 @@ func ShareBdev(address string, bdevUuid string) (string, error) {
 		Protocol: mayastorGrpc.ShareProtocol_NVMF,
 	}
 	var response *mayastorGrpc.BdevShareResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.Share(ctx, &req)
 		return err
 	})
@@ -52,10 +50,9 @@ func ShareBdev(address string, bdevUuid string) (string, error) {
 }
 
 // UnshareBdev unshare a bdev with uuid
-func UnshareBdev(address string, bdevUuid string) error {
+func UnshareBdev(ctx context.Context, address string, bdevUuid string) error {
 	logf.Log.Info("UnshareBdev", "address", address, "bdevUuid", bdevUuid)
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("UnshareBdev", "error", err)
 		return err
@@ -68,13 +65,13 @@ func UnshareBdev(address string, bdevUuid string) error {
 	}(conn)
 	c := mayastorGrpc.NewBdevRpcClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.BdevUnshareRequest{
 		Name: bdevUuid,
 	}
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.Unshare(ctx, &req)
 		return err
 	})
@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
+
+	"google.golang.org/grpc"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type v1FaultInjection struct {
+	Uri        string
+	DeviceName string
+	Active     bool
+}
+
+func (fi v1FaultInjection) GetUri() string {
+	return fi.Uri
+}
+
+func (fi v1FaultInjection) GetDeviceName() string {
+	return fi.DeviceName
+}
+
+func (fi v1FaultInjection) IsActive() bool {
+	return fi.Active
+}
+
+// AddFaultInjection adds the fault injection defined by the uri to the io-engine at the address
+func AddFaultInjection(ctx context.Context, address string, uri string) error {
+	logf.Log.Info("AddFaultInjection", "address", address, "uri", uri)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("AddFaultInjection", "error", err)
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("AddFaultInjection", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewTestRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	// not retried, adding an injection is not idempotent
+	_, err = c.AddFaultInjection(ctx, &mayastorGrpc.AddFaultInjectionRequest{Uri: uri})
+	if err != nil {
+		logf.Log.Info("AddFaultInjection", "address", address, "uri", uri, "error", err)
+	}
+	return niceError(err)
+}
+
+// RemoveFaultInjection removes the fault injection added with the uri from the io-engine at the address
+func RemoveFaultInjection(ctx context.Context, address string, uri string) error {
+	logf.Log.Info("RemoveFaultInjection", "address", address, "uri", uri)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("RemoveFaultInjection", "error", err)
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("RemoveFaultInjection", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewTestRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	_, err = c.RemoveFaultInjection(ctx, &mayastorGrpc.RemoveFaultInjectionRequest{Uri: uri})
+	if err != nil {
+		logf.Log.Info("RemoveFaultInjection", "address", address, "uri", uri, "error", err)
+	}
+	return niceError(err)
+}
+
+// ListFaultInjections lists the fault injections of the io-engine at the address
+func ListFaultInjections(ctx context.Context, address string) ([]v1FaultInjection, error) {
+	var injections []v1FaultInjection
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ListFaultInjections", "error", err)
+		return injections, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ListFaultInjections", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewTestRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	var response *mayastorGrpc.ListFaultInjectionsReply
+	retryBackoff(ctx, func() error {
+		response, err = c.ListFaultInjections(ctx, &mayastorGrpc.ListFaultInjectionsRequest{})
+		return err
+	})
+	if err != nil {
+		logf.Log.Info("ListFaultInjections", "address", address, "error", err)
+		return injections, niceError(err)
+	}
+	if response == nil {
+		return injections, fmt.Errorf("nil response for ListFaultInjections on %s", address)
+	}
+	for _, injection := range response.Injections {
+		injections = append(injections, v1FaultInjection{
+			Uri:        injection.Uri,
+			DeviceName: injection.DeviceName,
+			Active:     injection.IsActive,
+		})
+	}
+	return injections, nil
+}
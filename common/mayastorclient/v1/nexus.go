@@ -6,10 +6,9 @@ import (
 
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"google.golang.org/grpc"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -99,12 +98,11 @@ func (msn V1MayastorNexus) GetChildren() []v1MayastorNexusChild {
 	return children
 }
 
-func listNexuses(address string) ([]V1MayastorNexus, error) {
+func listNexuses(ctx context.Context, address string) ([]V1MayastorNexus, error) {
 	var nexusInfos []V1MayastorNexus
 	var err error
 
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("listNexuses", "error", err)
 		return nexusInfos, err
@@ -117,11 +115,11 @@ func listNexuses(address string) ([]V1MayastorNexus, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewNexusRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListNexusResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListNexus(ctx, &mayastorGrpc.ListNexusOptions{})
 		return err
 	})
@@ -153,11 +151,11 @@ func listNexuses(address string) ([]V1MayastorNexus, error) {
 
 // ListNexuses given a list of node ip addresses, enumerate the set of nexuses on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListNexuses(addrs []string) ([]V1MayastorNexus, error) {
+func ListNexuses(ctx context.Context, addrs []string) ([]V1MayastorNexus, error) {
 	var accErr error
 	var nexusInfos []V1MayastorNexus
 	for _, address := range addrs {
-		nexusInfo, err := listNexuses(address)
+		nexusInfo, err := listNexuses(ctx, address)
 		if err == nil {
 			nexusInfos = append(nexusInfos, nexusInfo...)
 		} else {
@@ -171,10 +169,9 @@ func ListNexuses(addrs []string) ([]V1MayastorNexus, error) {
 	return nexusInfos, accErr
 }
 
-func FaultNexusChild(address string, Uuid string, Uri string) error {
+func FaultNexusChild(ctx context.Context, address string, Uuid string, Uri string) error {
 	var err error
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("FaultNexusChild", "error", err)
 		return err
@@ -187,7 +184,7 @@ func FaultNexusChild(address string, Uuid string, Uri string) error {
 	}(conn)
 
 	c := mayastorGrpc.NewNexusRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	faultRequest := mayastorGrpc.FaultNexusChildRequest{
@@ -195,7 +192,7 @@ func FaultNexusChild(address string, Uuid string, Uri string) error {
 		Uri:  Uri,
 	}
 	var response *mayastorGrpc.FaultNexusChildResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.FaultNexusChild(ctx, &faultRequest)
 		return err
 	})
@@ -214,10 +211,10 @@ func FaultNexusChild(address string, Uuid string, Uri string) error {
 
 // FindNexus given a list of node ip addresses, return the common.MayastorNexus with matching uuid
 // returns accumulated errors if gRPC communication failed.
-func FindNexus(uuid string, addrs []string) (*V1MayastorNexus, error) {
+func FindNexus(ctx context.Context, uuid string, addrs []string) (*V1MayastorNexus, error) {
 	var accErr error
 	for _, address := range addrs {
-		nexusInfos, err := listNexuses(address)
+		nexusInfos, err := listNexuses(ctx, address)
 		if err == nil {
 			for _, ni := range nexusInfos {
 				if ni.Uuid == uuid {
@@ -237,12 +234,11 @@ func FindNexus(uuid string, addrs []string) (*V1MayastorNexus, error) {
 
 // GetRebuildHistory given a node ip address, return the V1RebuildHistory with matching uuid
 // returns accumulated errors if gRPC communication failed.
-func GetRebuildHistory(uuid string, address string) (V1RebuildHistory, error) {
+func GetRebuildHistory(ctx context.Context, uuid string, address string) (V1RebuildHistory, error) {
 	var rebuildHistory V1RebuildHistory
 	var err error
 
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("GetRebuildHistory", "error", err)
 		return rebuildHistory, err
@@ -255,14 +251,14 @@ func GetRebuildHistory(uuid string, address string) (V1RebuildHistory, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewNexusRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	rebuildHistoryRequest := mayastorGrpc.RebuildHistoryRequest{
 		Uuid: uuid,
 	}
 	var response *mayastorGrpc.RebuildHistoryResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.GetRebuildHistory(ctx, &rebuildHistoryRequest)
 		return err
 	})
@@ -287,12 +283,11 @@ func GetRebuildHistory(uuid string, address string) (V1RebuildHistory, error) {
 
 // GetRebuildStats given a node ip address, return the V1RebuildStatsResponse with matching nexus uuid and destination uri
 // returns accumulated errors if gRPC communication failed.
-func GetRebuildStats(uuid string, dstUri string, address string) (V1RebuildStatsResponse, error) {
+func GetRebuildStats(ctx context.Context, uuid string, dstUri string, address string) (V1RebuildStatsResponse, error) {
 	var rebuildStats V1RebuildStatsResponse
 	var err error
 
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("GetRebuildStats", "error", err)
 		return rebuildStats, err
@@ -305,7 +300,7 @@ func GetRebuildStats(uuid string, dstUri string, address string) (V1RebuildStats
 	}(conn)
 
 	c := mayastorGrpc.NewNexusRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	rebuildStatsRequest := mayastorGrpc.RebuildStatsRequest{
@@ -313,7 +308,7 @@ func GetRebuildStats(uuid string, dstUri string, address string) (V1RebuildStats
 		Uri:       dstUri,
 	}
 	var response *mayastorGrpc.RebuildStatsResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.GetRebuildStats(ctx, &rebuildStatsRequest)
 		return err
 	})
@@ -462,3 +457,95 @@ func (msn V1MayastorNexus) GetStateString() string {
 	}
 	return "?"
 }
+
+// anaStateNames are the names of the NVMe ANA states as reported by nvme-cli on the initiator
+var anaStateNames = map[mayastorGrpc.NvmeAnaState]string{
+	mayastorGrpc.NvmeAnaState_NVME_ANA_OPTIMIZED_STATE:       "optimized",
+	mayastorGrpc.NvmeAnaState_NVME_ANA_NON_OPTIMIZED_STATE:   "non-optimized",
+	mayastorGrpc.NvmeAnaState_NVME_ANA_INACCESSIBLE_STATE:    "inaccessible",
+	mayastorGrpc.NvmeAnaState_NVME_ANA_PERSISTENT_LOSS_STATE: "persistent-loss",
+	mayastorGrpc.NvmeAnaState_NVME_ANA_CHANGE_STATE:          "change",
+}
+
+// GetNvmeAnaState returns the NVMe ANA state of the nexus with the uuid on the io-engine at the address,
+// the state is named as reported by nvme-cli, eg: optimized, inaccessible
+func GetNvmeAnaState(ctx context.Context, uuid string, address string) (string, error) {
+	var anaState string
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("GetNvmeAnaState", "error", err)
+		return anaState, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("GetNvmeAnaState", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewNexusRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	var response *mayastorGrpc.GetNvmeAnaStateResponse
+	retryBackoff(ctx, func() error {
+		response, err = c.GetNvmeAnaState(ctx, &mayastorGrpc.GetNvmeAnaStateRequest{Uuid: uuid})
+		return err
+	})
+
+	if err == nil {
+		if response == nil {
+			err = fmt.Errorf("nil response to GetNvmeAnaState")
+		} else if name, ok := anaStateNames[response.AnaState]; ok {
+			anaState = name
+		} else {
+			err = fmt.Errorf("nexus %s has invalid ANA state %s", uuid, response.AnaState.String())
+		}
+	} else {
+		err = niceError(err)
+		logf.Log.Info("GetNvmeAnaState", "error", err)
+	}
+	return anaState, err
+}
+
+// SetNvmeAnaState sets the NVMe ANA state of the nexus with the uuid on the io-engine at the address,
+// the state is named as reported by nvme-cli, eg: optimized, inaccessible
+func SetNvmeAnaState(ctx context.Context, uuid string, anaState string, address string) error {
+	logf.Log.Info("SetNvmeAnaState", "address", address, "nexus", uuid, "anaState", anaState)
+	request := mayastorGrpc.SetNvmeAnaStateRequest{Uuid: uuid}
+	for state, name := range anaStateNames {
+		if name == anaState {
+			request.AnaState = state
+		}
+	}
+	if request.AnaState == mayastorGrpc.NvmeAnaState_NVME_ANA_INVALID_STATE {
+		return fmt.Errorf("invalid ANA state %q", anaState)
+	}
+
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("SetNvmeAnaState", "error", err)
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("SetNvmeAnaState", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewNexusRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	// setting the ANA state is idempotent, so the call is retried
+	retryBackoff(ctx, func() error {
+		_, err = c.SetNvmeAnaState(ctx, &request)
+		return err
+	})
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("SetNvmeAnaState", "error", err)
+	}
+	return err
+}
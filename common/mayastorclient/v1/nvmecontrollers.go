@@ -5,9 +5,8 @@ import (
 	"fmt"
 
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
+	"google.golang.org/grpc"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -39,11 +38,10 @@ func (nvmectlr v1NvmeController) GetBlkSize() uint32 {
 	return nvmectlr.BlkSize
 }
 
-func listNvmeController(address string) ([]v1NvmeController, error) {
+func listNvmeController(ctx context.Context, address string) ([]v1NvmeController, error) {
 	var nvmeControllers []v1NvmeController
 	var err error
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("listReplica", "error", err)
 		return nvmeControllers, err
@@ -56,11 +54,11 @@ func listNvmeController(address string) ([]v1NvmeController, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewHostRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListNvmeControllersResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListNvmeControllers(ctx, &null)
 		return err
 	})
@@ -88,11 +86,11 @@ func listNvmeController(address string) ([]v1NvmeController, error) {
 
 // ListNvmeControllers given a list of node ip addresses, enumerate the set of nvmeControllers on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListNvmeControllers(addrs []string) ([]v1NvmeController, error) {
+func ListNvmeControllers(ctx context.Context, addrs []string) ([]v1NvmeController, error) {
 	var accErr error
 	var nvmeControllers []v1NvmeController
 	for _, address := range addrs {
-		nvmeController, err := listNvmeController(address)
+		nvmeController, err := listNvmeController(ctx, address)
 		if err == nil {
 			nvmeControllers = append(nvmeControllers, nvmeController...)
 		} else {
@@ -8,7 +8,6 @@ import (
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -76,11 +75,10 @@ func (msp v1MayastorPool) ToCrdState() string {
 	}
 }
 
-func listPool(address string) ([]v1MayastorPool, error) {
+func listPool(ctx context.Context, address string) ([]v1MayastorPool, error) {
 	var poolInfos []v1MayastorPool
 	var err error
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("listPool", "error", err)
 		return poolInfos, err
@@ -93,11 +91,11 @@ func listPool(address string) ([]v1MayastorPool, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewPoolRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListPoolsResponse
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		response, err = c.ListPools(ctx, &mayastorGrpc.ListPoolOptions{})
 		return err
 	})
@@ -126,8 +124,8 @@ func listPool(address string) ([]v1MayastorPool, error) {
 	return poolInfos, err
 }
 
-func GetPool(name, addr string) (*v1MayastorPool, error) {
-	poolInfo, err := listPool(addr)
+func GetPool(ctx context.Context, name, addr string) (*v1MayastorPool, error) {
+	poolInfo, err := listPool(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -141,11 +139,11 @@ func GetPool(name, addr string) (*v1MayastorPool, error) {
 
 // ListPools given a list of node ip addresses, enumerate the set of pools on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListPools(addrs []string) ([]v1MayastorPool, error) {
+func ListPools(ctx context.Context, addrs []string) ([]v1MayastorPool, error) {
 	var accErr error
 	var poolInfos []v1MayastorPool
 	for _, address := range addrs {
-		poolInfo, err := listPool(address)
+		poolInfo, err := listPool(ctx, address)
 		if err == nil {
 			poolInfos = append(poolInfos, poolInfo...)
 		} else {
@@ -159,10 +157,10 @@ func ListPools(addrs []string) ([]v1MayastorPool, error) {
 	return poolInfos, accErr
 }
 
-func DestroyAllPools(addrs []string) error {
+func DestroyAllPools(ctx context.Context, addrs []string) error {
 
 	for _, addr := range addrs {
-		poolInfo, err := listPool(addr)
+		poolInfo, err := listPool(ctx, addr)
 		if err != nil {
 			return err
 		}
@@ -170,7 +168,7 @@ func DestroyAllPools(addrs []string) error {
 			continue
 		}
 		for _, pool := range poolInfo {
-			err = DestroyPool(pool.Name, addr)
+			err = DestroyPool(ctx, pool.Name, addr)
 			if err != nil {
 				return err
 			}
@@ -180,10 +178,9 @@ func DestroyAllPools(addrs []string) error {
 	return nil
 }
 
-func DestroyPool(name, address string) error {
+func DestroyPool(ctx context.Context, name, address string) error {
 	var err error
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("destroyPool", "error", err)
 		return err
@@ -196,9 +193,79 @@ func DestroyPool(name, address string) error {
 	}(conn)
 
 	c := mayastorGrpc.NewPoolRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	_, err = c.DestroyPool(ctx, &mayastorGrpc.DestroyPoolRequest{Name: name})
 	return err
 }
+
+// ExportPool exports the pool from the io-engine at the address, the pool is no longer
+// listed by the io-engine but the data on the disks of the pool is retained
+func ExportPool(ctx context.Context, name, address string) error {
+	logf.Log.Info("ExportPool", "address", address, "name", name)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ExportPool", "error", err)
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ExportPool", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewPoolRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	_, err = c.ExportPool(ctx, &mayastorGrpc.ExportPoolRequest{Name: name})
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("ExportPool", "address", address, "name", name, "error", err)
+	}
+	return err
+}
+
+// ImportPool imports the pool from the disks on the io-engine at the address, eg: a pool which has been exported
+func ImportPool(ctx context.Context, name string, disks []string, address string) (*v1MayastorPool, error) {
+	logf.Log.Info("ImportPool", "address", address, "name", name, "disks", disks)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ImportPool", "error", err)
+		return nil, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ImportPool", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewPoolRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	pool, err := c.ImportPool(ctx, &mayastorGrpc.ImportPoolRequest{
+		Name:     name,
+		Disks:    disks,
+		Pooltype: mayastorGrpc.PoolType_Lvs,
+	})
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("ImportPool", "address", address, "name", name, "error", err)
+		return nil, err
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("nil response for ImportPool %s on %s", name, address)
+	}
+	return &v1MayastorPool{
+		Name:     pool.Name,
+		Disks:    pool.Disks,
+		State:    pool.State,
+		Capacity: pool.Capacity,
+		Used:     pool.Used,
+		Uuid:     pool.Uuid,
+	}, nil
+}
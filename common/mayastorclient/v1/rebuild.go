@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
+
+	"google.golang.org/grpc"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// rebuildControl makes a rebuild control call for the child of the nexus on the io-engine at the address
+func rebuildControl(ctx context.Context, name string, uuid string, childUri string, address string,
+	call func(ctx context.Context, c mayastorGrpc.NexusRpcClient) error) error {
+	logf.Log.Info(name, "address", address, "nexus", uuid, "child", childUri)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info(name, "error", err)
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info(name, "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewNexusRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	// not retried, the calls change the state of the rebuild
+	err = call(ctx, c)
+	if err != nil {
+		logf.Log.Info(name, "address", address, "nexus", uuid, "child", childUri, "error", err)
+	}
+	return niceError(err)
+}
+
+// StartRebuild starts the rebuild of the child of the nexus
+func StartRebuild(ctx context.Context, uuid string, childUri string, address string) error {
+	return rebuildControl(ctx, "StartRebuild", uuid, childUri, address,
+		func(ctx context.Context, c mayastorGrpc.NexusRpcClient) error {
+			_, err := c.StartRebuild(ctx, &mayastorGrpc.StartRebuildRequest{NexusUuid: uuid, Uri: childUri})
+			return err
+		})
+}
+
+// StopRebuild stops the rebuild of the child of the nexus
+func StopRebuild(ctx context.Context, uuid string, childUri string, address string) error {
+	return rebuildControl(ctx, "StopRebuild", uuid, childUri, address,
+		func(ctx context.Context, c mayastorGrpc.NexusRpcClient) error {
+			_, err := c.StopRebuild(ctx, &mayastorGrpc.StopRebuildRequest{NexusUuid: uuid, Uri: childUri})
+			return err
+		})
+}
+
+// PauseRebuild pauses the rebuild of the child of the nexus
+func PauseRebuild(ctx context.Context, uuid string, childUri string, address string) error {
+	return rebuildControl(ctx, "PauseRebuild", uuid, childUri, address,
+		func(ctx context.Context, c mayastorGrpc.NexusRpcClient) error {
+			_, err := c.PauseRebuild(ctx, &mayastorGrpc.PauseRebuildRequest{NexusUuid: uuid, Uri: childUri})
+			return err
+		})
+}
+
+// ResumeRebuild resumes the paused rebuild of the child of the nexus
+func ResumeRebuild(ctx context.Context, uuid string, childUri string, address string) error {
+	return rebuildControl(ctx, "ResumeRebuild", uuid, childUri, address,
+		func(ctx context.Context, c mayastorGrpc.NexusRpcClient) error {
+			_, err := c.ResumeRebuild(ctx, &mayastorGrpc.ResumeRebuildRequest{NexusUuid: uuid, Uri: childUri})
+			return err
+		})
+}
+
+// GetRebuildState returns the state of the rebuild of the child of the nexus, eg: running, paused, completed
+func GetRebuildState(ctx context.Context, uuid string, childUri string, address string) (string, error) {
+	var state string
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("GetRebuildState", "error", err)
+		return state, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("GetRebuildState", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewNexusRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	var response *mayastorGrpc.RebuildStateResponse
+	retryBackoff(ctx, func() error {
+		response, err = c.GetRebuildState(ctx, &mayastorGrpc.RebuildStateRequest{NexusUuid: uuid, Uri: childUri})
+		return err
+	})
+
+	if err == nil {
+		if response == nil {
+			err = fmt.Errorf("nil response to GetRebuildState")
+		} else {
+			state = response.State
+		}
+	} else {
+		err = niceError(err)
+		logf.Log.Info("GetRebuildState", "error", err)
+	}
+	return state, err
+}
+
+// ListRebuildHistory returns the rebuild history of all nexuses on the io-engine at the address by nexus uuid
+func ListRebuildHistory(ctx context.Context, address string) (map[string]V1RebuildHistory, error) {
+	histories := make(map[string]V1RebuildHistory)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ListRebuildHistory", "error", err)
+		return histories, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ListRebuildHistory", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewNexusRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	var response *mayastorGrpc.ListRebuildHistoryResponse
+	retryBackoff(ctx, func() error {
+		response, err = c.ListRebuildHistory(ctx, &mayastorGrpc.ListRebuildHistoryRequest{})
+		return err
+	})
+
+	if err == nil {
+		if response == nil {
+			err = fmt.Errorf("nil response to ListRebuildHistory")
+		} else {
+			for nexusUuid, history := range response.Histories {
+				histories[nexusUuid] = V1RebuildHistory{
+					Uuid:    history.GetUuid(),
+					Records: history.GetRecords(),
+				}
+			}
+		}
+	} else {
+		err = niceError(err)
+		logf.Log.Info("ListRebuildHistory", "error", err)
+	}
+	return histories, err
+}
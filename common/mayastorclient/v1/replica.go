@@ -8,10 +8,9 @@ import (
 
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
 
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"google.golang.org/grpc"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -62,13 +61,12 @@ func (msr v1MayastorReplica) GetPoolUuid() string {
 	return msr.Pooluuid
 }
 
-func listReplica(address string) ([]v1MayastorReplica, error) {
+func listReplica(ctx context.Context, address string) ([]v1MayastorReplica, error) {
 	var replicaInfos []v1MayastorReplica
 	var err error
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
-		logf.Log.Info("listReplica connect failure", "address", address, "addrPort", addrPort, "error", err)
+		logf.Log.Info("listReplica connect failure", "address", address, "error", err)
 		return replicaInfos, err
 	}
 	defer func(conn *grpc.ClientConn) {
@@ -79,11 +77,11 @@ func listReplica(address string) ([]v1MayastorReplica, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewReplicaRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
 	defer cancel()
 
 	var response *mayastorGrpc.ListReplicasResponse
-	retryBackoffOnUnavailable(func() error {
+	retryBackoffOnUnavailable(ctx, func() error {
 		response, err = c.ListReplicas(ctx, &mayastorGrpc.ListReplicaOptions{})
 		return err
 	})
@@ -115,10 +113,9 @@ func listReplica(address string) ([]v1MayastorReplica, error) {
 }
 
 // RmReplica remove a replica identified by node and uuid
-func RmReplica(address string, uuid string) error {
+func RmReplica(ctx context.Context, address string, uuid string) error {
 	logf.Log.Info("RmReplica", "address", address, "UUID", uuid)
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("rmReplicas", "error", err)
 		return err
@@ -131,11 +128,11 @@ func RmReplica(address string, uuid string) error {
 	}(conn)
 	c := mayastorGrpc.NewReplicaRpcClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.DestroyReplicaRequest{Uuid: uuid}
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.DestroyReplica(ctx, &req)
 		return err
 	})
@@ -144,13 +141,12 @@ func RmReplica(address string, uuid string) error {
 }
 
 // CreateReplicaExt create a replica on a mayastor node
-func CreateReplicaExt(address string, uuid string, size uint64, pool string, thin bool) error {
+func CreateReplicaExt(ctx context.Context, address string, uuid string, size uint64, pool string, thin bool) error {
 	shareProto := mayastorGrpc.ShareProtocol_NVMF
 	logf.Log.Info("CreateReplica", "address", address, "UUID", uuid, "size", size, "pool", pool, "Thin", thin, "Share", shareProto)
-	addrPort := getAddrPort(address)
 	var err error
 
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("createReplica", "error", err)
 		return err
@@ -162,7 +158,7 @@ func CreateReplicaExt(address string, uuid string, size uint64, pool string, thi
 		}
 	}(conn)
 	c := mayastorGrpc.NewReplicaRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
 	req := mayastorGrpc.CreateReplicaRequest{
@@ -174,7 +170,7 @@ func CreateReplicaExt(address string, uuid string, size uint64, pool string, thi
 		Share:    shareProto,
 	}
 
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.CreateReplica(ctx, &req)
 		return err
 	})
@@ -186,17 +182,62 @@ func CreateReplicaExt(address string, uuid string, size uint64, pool string, thi
 //
 //	 thin fixed to false and share fixed to NVMF.
 //	Other parameters must be specified
-func CreateReplica(address string, uuid string, size uint64, pool string) error {
-	return CreateReplicaExt(address, uuid, size, pool, false)
+func CreateReplica(ctx context.Context, address string, uuid string, size uint64, pool string) error {
+	return CreateReplicaExt(ctx, address, uuid, size, pool, false)
+}
+
+// ResizeReplica grows the replica identified by node and uuid to size bytes, returns the resized replica
+func ResizeReplica(ctx context.Context, address string, uuid string, size uint64) (v1MayastorReplica, error) {
+	logf.Log.Info("ResizeReplica", "address", address, "UUID", uuid, "size", size)
+	var replicaInfo v1MayastorReplica
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ResizeReplica", "error", err)
+		return replicaInfo, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ResizeReplica", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewReplicaRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	replica, err := c.ResizeReplica(ctx, &mayastorGrpc.ResizeReplicaRequest{Uuid: uuid, RequestedSize: size})
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("ResizeReplica", "address", address, "UUID", uuid, "error", err)
+		return replicaInfo, err
+	}
+	if replica == nil {
+		return replicaInfo, fmt.Errorf("nil response for ResizeReplica %s on %s", uuid, address)
+	}
+	return newV1Replica(replica), nil
+}
+
+func newV1Replica(replica *mayastorGrpc.Replica) v1MayastorReplica {
+	return v1MayastorReplica{
+		Name:     replica.Name,
+		Uuid:     replica.Uuid,
+		Poolname: replica.Poolname,
+		Thin:     replica.Thin,
+		Size:     replica.Size,
+		Share:    replica.Share,
+		Uri:      replica.Uri,
+		Pooluuid: replica.Pooluuid,
+	}
 }
 
 // ListReplicas given a list of node ip addresses, enumerate the set of replicas on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func ListReplicas(addrs []string) ([]v1MayastorReplica, error) {
+func ListReplicas(ctx context.Context, addrs []string) ([]v1MayastorReplica, error) {
 	var accErr error
 	var replicaInfos []v1MayastorReplica
 	for _, address := range addrs {
-		replicaInfo, err := listReplica(address)
+		replicaInfo, err := listReplica(ctx, address)
 		if err == nil {
 			replicaInfos = append(replicaInfos, replicaInfo...)
 		} else {
@@ -212,13 +253,13 @@ func ListReplicas(addrs []string) ([]v1MayastorReplica, error) {
 
 // RmNodeReplicas given a list of node ip addresses, delete the set of replicas on mayastor using gRPC on each of those nodes
 // returns errors if gRPC communication failed.
-func RmNodeReplicas(addrs []string) error {
+func RmNodeReplicas(ctx context.Context, addrs []string) error {
 	var accErr error
 	for _, address := range addrs {
-		replicaInfos, err := listReplica(address)
+		replicaInfos, err := listReplica(ctx, address)
 		if err == nil {
 			for _, replicaInfo := range replicaInfos {
-				err = RmReplica(address, replicaInfo.Uuid)
+				err = RmReplica(ctx, address, replicaInfo.Uuid)
 			}
 		}
 		if err != nil {
@@ -234,11 +275,11 @@ func RmNodeReplicas(addrs []string) error {
 
 // FindReplicas given a list of node ip addresses, enumerate the set of replicas on mayastor using gRPC on each of those nodes
 // returns accumulated errors if gRPC communication failed.
-func FindReplicas(uuid string, addrs []string) ([]v1MayastorReplica, error) {
+func FindReplicas(ctx context.Context, uuid string, addrs []string) ([]v1MayastorReplica, error) {
 	var accErr error
 	var replicaInfos []v1MayastorReplica
 	for _, address := range addrs {
-		replicaInfo, err := listReplica(address)
+		replicaInfo, err := listReplica(ctx, address)
 		if err == nil {
 			for _, repl := range replicaInfo {
 				if repl.Uuid == uuid {
@@ -257,11 +298,10 @@ func FindReplicas(uuid string, addrs []string) ([]v1MayastorReplica, error) {
 }
 
 // WipeReplica fill a replica with zeroes
-func WipeReplica(address string, replicaUuid string, poolName string) error {
+func WipeReplica(ctx context.Context, address string, replicaUuid string, poolName string) error {
 	desc := fmt.Sprintf("addr:%s uuid:%s pool:%s", address, replicaUuid, poolName)
 	logf.Log.Info("WipeReplica", "address", address, "UUID", replicaUuid, "poolName", poolName)
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("WipeReplica", "target", desc, "error", err)
 		return err
@@ -292,7 +332,7 @@ func WipeReplica(address string, replicaUuid string, poolName string) error {
 	}
 	startTime := time.Now()
 	logf.Log.Info("Start WipeReplica .............", "target", desc)
-	stream, err := c.WipeReplica(context.TODO(), &req)
+	stream, err := c.WipeReplica(ctx, &req)
 	if err == nil {
 		for {
 			resp, rcvErr := stream.Recv()
@@ -318,13 +358,12 @@ func WipeReplica(address string, replicaUuid string, poolName string) error {
 }
 
 // ChecksumReplica calculate the checksum of a replica
-func ChecksumReplica(address string, replicaUuid string, poolName string) (uint32, error) {
+func ChecksumReplica(ctx context.Context, address string, replicaUuid string, poolName string) (uint32, error) {
 	var cksum uint32
 	var cksumSet = false
 	desc := fmt.Sprintf("addr:%s uuid:%s pool:%s", address, replicaUuid, poolName)
 	logf.Log.Info("ChecksumReplica", "address", address, "UUID", replicaUuid, "poolName", poolName)
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("ChecksumReplica", "target", desc, "error", err)
 		return cksum, err
@@ -338,7 +377,7 @@ func ChecksumReplica(address string, replicaUuid string, poolName string) (uint3
 	c := mayastorGrpc.NewTestRpcClient(conn)
 
 	var features *mayastorGrpc.TestFeatures
-	features, err = c.GetFeatures(context.TODO(), &emptypb.Empty{})
+	features, err = c.GetFeatures(ctx, &emptypb.Empty{})
 	if err != nil {
 		logf.Log.Info("failed to retrieve test features set", "err", err)
 		return cksum, err
@@ -365,7 +404,7 @@ func ChecksumReplica(address string, replicaUuid string, poolName string) (uint3
 	logf.Log.Info("ChecksumReplica ", "req.WipeOptions", req.WipeOptions, "req.WipeOptions.Options", req.WipeOptions.Options)
 	logf.Log.Info("Start ChecksumReplica .............", "target", desc, "wipeM", req.GetWipeOptions().GetOptions().GetWipeMethod(),
 		"cksa", req.GetWipeOptions().GetOptions().GetCksumAlg())
-	stream, err := c.WipeReplica(context.TODO(), &req)
+	stream, err := c.WipeReplica(ctx, &req)
 	if err == nil {
 		for {
 			resp, rcvErr := stream.Recv()
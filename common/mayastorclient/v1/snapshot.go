@@ -0,0 +1,362 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
+
+	"google.golang.org/grpc"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type v1Snapshot struct {
+	Uuid          string
+	Name          string
+	SourceUuid    string
+	PoolName      string
+	PoolUuid      string
+	Size          uint64
+	NumClones     uint64
+	EntityId      string
+	TxnId         string
+	Valid         bool
+	ReadyAsSource bool
+	Discarded     bool
+}
+
+func (s v1Snapshot) GetUuid() string {
+	return s.Uuid
+}
+
+func (s v1Snapshot) GetName() string {
+	return s.Name
+}
+
+func (s v1Snapshot) GetSourceUuid() string {
+	return s.SourceUuid
+}
+
+func (s v1Snapshot) GetPoolName() string {
+	return s.PoolName
+}
+
+func (s v1Snapshot) GetPoolUuid() string {
+	return s.PoolUuid
+}
+
+func (s v1Snapshot) GetSize() uint64 {
+	return s.Size
+}
+
+func (s v1Snapshot) GetNumClones() uint64 {
+	return s.NumClones
+}
+
+func (s v1Snapshot) GetEntityId() string {
+	return s.EntityId
+}
+
+func (s v1Snapshot) GetTxnId() string {
+	return s.TxnId
+}
+
+func (s v1Snapshot) IsValid() bool {
+	return s.Valid
+}
+
+func (s v1Snapshot) IsReadyAsSource() bool {
+	return s.ReadyAsSource
+}
+
+func (s v1Snapshot) IsDiscarded() bool {
+	return s.Discarded
+}
+
+func newV1Snapshot(info *mayastorGrpc.SnapshotInfo) v1Snapshot {
+	return v1Snapshot{
+		Uuid:          info.SnapshotUuid,
+		Name:          info.SnapshotName,
+		SourceUuid:    info.SourceUuid,
+		PoolName:      info.PoolName,
+		PoolUuid:      info.PoolUuid,
+		Size:          info.SnapshotSize,
+		NumClones:     info.NumClones,
+		EntityId:      info.EntityId,
+		TxnId:         info.TxnId,
+		Valid:         info.ValidSnapshot,
+		ReadyAsSource: info.ReadyAsSource,
+		Discarded:     info.DiscardedSnapshot,
+	}
+}
+
+type v1NexusSnapshot struct {
+	ReplicasDone    []string
+	ReplicasFailed  map[string]uint32
+	ReplicasSkipped []string
+}
+
+func (s v1NexusSnapshot) GetReplicasDone() []string {
+	return s.ReplicasDone
+}
+
+func (s v1NexusSnapshot) GetReplicasFailed() map[string]uint32 {
+	return s.ReplicasFailed
+}
+
+func (s v1NexusSnapshot) GetReplicasSkipped() []string {
+	return s.ReplicasSkipped
+}
+
+// CreateReplicaSnapshot creates a snapshot of the replica on the io-engine at the address
+func CreateReplicaSnapshot(ctx context.Context, address string, replicaUuid string, snapshotUuid string,
+	snapshotName string, entityId string, txnId string) (v1Snapshot, error) {
+	logf.Log.Info("CreateReplicaSnapshot", "address", address, "replica", replicaUuid, "snapshot", snapshotUuid)
+	var snapshot v1Snapshot
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("CreateReplicaSnapshot", "error", err)
+		return snapshot, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("CreateReplicaSnapshot", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewSnapshotRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	// not retried, creating a snapshot is not idempotent
+	response, err := c.CreateReplicaSnapshot(ctx, &mayastorGrpc.CreateReplicaSnapshotRequest{
+		ReplicaUuid:  replicaUuid,
+		SnapshotUuid: snapshotUuid,
+		SnapshotName: snapshotName,
+		EntityId:     entityId,
+		TxnId:        txnId,
+	})
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("CreateReplicaSnapshot", "address", address, "replica", replicaUuid, "error", err)
+		return snapshot, err
+	}
+	if response == nil || response.Snapshot == nil {
+		return snapshot, fmt.Errorf("nil response for CreateReplicaSnapshot %s on %s", snapshotUuid, address)
+	}
+	return newV1Snapshot(response.Snapshot), nil
+}
+
+// CreateNexusSnapshot creates a snapshot of the replicas of the nexus on the io-engine at the address,
+// replicaSnapshots maps the uuid of each replica to the uuid of its snapshot, an empty snapshot uuid skips the replica
+func CreateNexusSnapshot(ctx context.Context, address string, nexusUuid string, snapshotName string,
+	entityId string, txnId string, replicaSnapshots map[string]string) (v1NexusSnapshot, error) {
+	logf.Log.Info("CreateNexusSnapshot", "address", address, "nexus", nexusUuid, "replicas", replicaSnapshots)
+	var snapshot v1NexusSnapshot
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("CreateNexusSnapshot", "error", err)
+		return snapshot, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("CreateNexusSnapshot", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewSnapshotRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	req := mayastorGrpc.NexusCreateSnapshotRequest{
+		NexusUuid:    nexusUuid,
+		EntityId:     entityId,
+		TxnId:        txnId,
+		SnapshotName: snapshotName,
+	}
+	for replicaUuid, snapshotUuid := range replicaSnapshots {
+		descriptor := mayastorGrpc.NexusCreateSnapshotReplicaDescriptor{ReplicaUuid: replicaUuid, Skip: snapshotUuid == ""}
+		if snapshotUuid != "" {
+			snapshotUuid := snapshotUuid
+			descriptor.SnapshotUuid = &snapshotUuid
+		}
+		req.Replicas = append(req.Replicas, &descriptor)
+	}
+
+	// not retried, creating a snapshot is not idempotent
+	response, err := c.CreateNexusSnapshot(ctx, &req)
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("CreateNexusSnapshot", "address", address, "nexus", nexusUuid, "error", err)
+		return snapshot, err
+	}
+	if response == nil {
+		return snapshot, fmt.Errorf("nil response for CreateNexusSnapshot %s on %s", nexusUuid, address)
+	}
+	snapshot.ReplicasFailed = make(map[string]uint32)
+	for _, status := range response.ReplicasDone {
+		if status.StatusCode == 0 {
+			snapshot.ReplicasDone = append(snapshot.ReplicasDone, status.ReplicaUuid)
+		} else {
+			snapshot.ReplicasFailed[status.ReplicaUuid] = status.StatusCode
+		}
+	}
+	snapshot.ReplicasSkipped = response.ReplicasSkipped
+	return snapshot, nil
+}
+
+// ListSnapshots lists the snapshots on the io-engine at the address
+func ListSnapshots(ctx context.Context, address string) ([]v1Snapshot, error) {
+	var snapshots []v1Snapshot
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ListSnapshots", "error", err)
+		return snapshots, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ListSnapshots", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewSnapshotRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	var response *mayastorGrpc.ListSnapshotsResponse
+	retryBackoff(ctx, func() error {
+		response, err = c.ListSnapshot(ctx, &mayastorGrpc.ListSnapshotsRequest{})
+		return err
+	})
+
+	if err == nil {
+		if response != nil {
+			for _, info := range response.Snapshots {
+				snapshots = append(snapshots, newV1Snapshot(info))
+			}
+		} else {
+			err = fmt.Errorf("nil response for ListSnapshot on %s", address)
+			logf.Log.Info("ListSnapshots", "error", err)
+		}
+	} else {
+		err = niceError(err)
+		logf.Log.Info("ListSnapshots", "error", err)
+	}
+	return snapshots, err
+}
+
+// DestroySnapshot destroys the snapshot in the pool on the io-engine at the address
+func DestroySnapshot(ctx context.Context, address string, snapshotUuid string, poolName string) error {
+	logf.Log.Info("DestroySnapshot", "address", address, "snapshot", snapshotUuid, "pool", poolName)
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("DestroySnapshot", "error", err)
+		return err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("DestroySnapshot", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewSnapshotRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	req := mayastorGrpc.DestroySnapshotRequest{SnapshotUuid: snapshotUuid}
+	if poolName != "" {
+		req.Pool = &mayastorGrpc.DestroySnapshotRequest_PoolName{PoolName: poolName}
+	}
+	_, err = c.DestroySnapshot(ctx, &req)
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("DestroySnapshot", "address", address, "snapshot", snapshotUuid, "error", err)
+	}
+	return err
+}
+
+// CreateSnapshotClone creates a clone replica of the snapshot on the io-engine at the address
+func CreateSnapshotClone(ctx context.Context, address string, snapshotUuid string, cloneName string, cloneUuid string) (v1MayastorReplica, error) {
+	logf.Log.Info("CreateSnapshotClone", "address", address, "snapshot", snapshotUuid, "clone", cloneUuid)
+	var clone v1MayastorReplica
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("CreateSnapshotClone", "error", err)
+		return clone, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("CreateSnapshotClone", "error on close", err)
+		}
+	}(conn)
+	c := mayastorGrpc.NewSnapshotRpcClient(conn)
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	replica, err := c.CreateSnapshotClone(ctx, &mayastorGrpc.CreateSnapshotCloneRequest{
+		SnapshotUuid: snapshotUuid,
+		CloneName:    cloneName,
+		CloneUuid:    cloneUuid,
+	})
+	if err != nil {
+		err = niceError(err)
+		logf.Log.Info("CreateSnapshotClone", "address", address, "snapshot", snapshotUuid, "error", err)
+		return clone, err
+	}
+	if replica == nil {
+		return clone, fmt.Errorf("nil response for CreateSnapshotClone %s on %s", cloneUuid, address)
+	}
+	return newV1Replica(replica), nil
+}
+
+// ListSnapshotClones lists the clones of the snapshot on the io-engine at the address,
+// all clones if snapshotUuid is empty
+func ListSnapshotClones(ctx context.Context, address string, snapshotUuid string) ([]v1MayastorReplica, error) {
+	var clones []v1MayastorReplica
+	conn, err := dial(address)
+	if err != nil {
+		logf.Log.Info("ListSnapshotClones", "error", err)
+		return clones, err
+	}
+	defer func(conn *grpc.ClientConn) {
+		err := conn.Close()
+		if err != nil {
+			logf.Log.Info("ListSnapshotClones", "error on close", err)
+		}
+	}(conn)
+
+	c := mayastorGrpc.NewSnapshotRpcClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, callTimeout())
+	defer cancel()
+
+	req := mayastorGrpc.ListSnapshotCloneRequest{}
+	if snapshotUuid != "" {
+		req.SnapshotUuid = &snapshotUuid
+	}
+	var response *mayastorGrpc.ListSnapshotCloneResponse
+	retryBackoff(ctx, func() error {
+		response, err = c.ListSnapshotClone(ctx, &req)
+		return err
+	})
+
+	if err == nil {
+		if response != nil {
+			for _, replica := range response.Replicas {
+				clones = append(clones, newV1Replica(replica))
+			}
+		} else {
+			err = fmt.Errorf("nil response for ListSnapshotClone on %s", address)
+			logf.Log.Info("ListSnapshotClones", "error", err)
+		}
+	} else {
+		err = niceError(err)
+		logf.Log.Info("ListSnapshotClones", "error", err)
+	}
+	return clones, err
+}
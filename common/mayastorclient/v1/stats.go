@@ -6,16 +6,14 @@ import (
 
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/emptypb"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func ResetIOStats(address string) error {
+func ResetIOStats(ctx context.Context, address string) error {
 
 	logf.Log.Info("reset io stats", "address", address)
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		logf.Log.Info("ResetIOStats", "error", err)
 		return err
@@ -28,10 +26,10 @@ func ResetIOStats(address string) error {
 	}(conn)
 	c := mayastorGrpc.NewStatsRpcClient(conn)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	retryBackoff(func() error {
+	retryBackoff(ctx, func() error {
 		_, err = c.ResetIoStats(ctx, &emptypb.Empty{})
 		return err
 	})
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/openebs/openebs-e2e/common/e2e_config"
 	"github.com/openebs/openebs-e2e/common/k8s_portforward"
 	mayastorGrpc "github.com/openebs/openebs-e2e/common/mayastorclient/v1/protobuf"
 
@@ -56,10 +57,14 @@ func getAddrPort(address string) string {
 	return k8s_portforward.TryPortForwardNode(address, mayastorPort)
 }
 
-func mayastorInfo(address string) (*mayastorGrpc.MayastorInfoResponse, error) {
+// dial returns a gRPC connection to the io-engine at the address
+func dial(address string) (*grpc.ClientConn, error) {
+	return grpc.Dial(getAddrPort(address), grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+func mayastorInfo(ctx context.Context, address string) (*mayastorGrpc.MayastorInfoResponse, error) {
 	var err error
-	addrPort := getAddrPort(address)
-	conn, err := grpc.Dial(addrPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(address)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +73,7 @@ func mayastorInfo(address string) (*mayastorGrpc.MayastorInfoResponse, error) {
 	}(conn)
 
 	c := mayastorGrpc.NewHostRpcClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	info, err := c.GetMayastorInfo(ctx, &emptypb.Empty{})
@@ -77,12 +82,12 @@ func mayastorInfo(address string) (*mayastorGrpc.MayastorInfoResponse, error) {
 
 // CheckAndSetConnect call to cache connectable state to Mayastor instances on the cluster under test
 // Just dialing does not work, we need to make a simple gRPC call  (GetMayastorInfo)
-func CheckAndSetConnect(nodes []string) error {
+func CheckAndSetConnect(ctx context.Context, nodes []string) error {
 	var connErr error
 	logf.Log.Info("Checking gRPC connections to Mayastor on", "nodes", nodes)
 	if len(nodes) != 0 {
 		for _, node := range nodes {
-			info, err := mayastorInfo(node)
+			info, err := mayastorInfo(ctx, node)
 			logf.Log.Info("", "mayastorInfo", info)
 			if err != nil || info == nil {
 				connErr = fmt.Errorf("e:%v, i:%v", err, info)
@@ -100,19 +105,37 @@ func CanConnect() bool {
 	return canConnect
 }
 
-const ctxTimeout = 30 * time.Second
+// defaultCallTimeout is the gRPC call timeout if the configuration does not set a valid one
+const defaultCallTimeout = 30 * time.Second
+
+// callTimeout returns the timeout of gRPC calls which do not have a specific timeout
+func callTimeout() time.Duration {
+	return e2e_config.ParseTimeout(e2e_config.GetConfig().GrpcCallTimeout, defaultCallTimeout)
+}
+
+// sleepCtx sleeps for the duration, returning false if ctx is done first
+func sleepCtx(ctx context.Context, duration time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(duration):
+		return true
+	}
+}
 
 // retry a function upto 6 times with exponential backoff,
 // starting at 5 seconds if the error(s) returned are
 // is deadline_exceeded.
-func retryBackoff(f func() (err error)) {
+func retryBackoff(ctx context.Context, f func() (err error)) {
 	if !isDeadlineExceeded(f()) {
 		return
 	}
 	timeout := 5 * time.Second
 	for i := 0; i < 6; i++ {
 		logf.Log.Info("retrying gRPC call", "after", timeout)
-		time.Sleep(timeout)
+		if !sleepCtx(ctx, timeout) {
+			return
+		}
 		timeout *= 2
 		if !isDeadlineExceeded(f()) {
 			return
@@ -124,14 +147,16 @@ func retryBackoff(f func() (err error)) {
 // starting at 1 seconds upt 32 seconds (63 seconds total)
 // if the error(s) returned are
 // is deadline_exceeded or unavailable.
-func retryBackoffOnUnavailable(f func() (err error)) {
+func retryBackoffOnUnavailable(ctx context.Context, f func() (err error)) {
 	if !isRetryErr(f()) {
 		return
 	}
 	timeout := 5 * time.Second
 	for i := 0; i < 6; i++ {
 		logf.Log.Info("retrying gRPC call", "after", timeout)
-		time.Sleep(timeout)
+		if !sleepCtx(ctx, timeout) {
+			return
+		}
 		timeout *= 2
 		if !isRetryErr(f()) {
 			return
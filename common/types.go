@@ -293,6 +293,9 @@ type StateTarget struct {
 	Uuid      string        `json:"uuid"`
 }
 
+// MayastorNexus is a nexus, the target of a volume is a nexus
+type MayastorNexus = StateTarget
+
 type TargetChild struct {
 	State           string `json:"state"`
 	Uri             string `json:"uri"`
@@ -352,6 +355,26 @@ type MayastorNodeState struct {
 	Node_nqn     string `json:"node_nqn"`
 }
 
+// AppNode is a node of applications registered with the control plane,
+// ie the host of an NVMe initiator of volumes
+type AppNode struct {
+	Id    string       `json:"id"`
+	Spec  AppNodeSpec  `json:"spec"`
+	State AppNodeState `json:"state"`
+}
+
+type AppNodeSpec struct {
+	Id       string            `json:"id"`
+	Endpoint string            `json:"endpoint"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+type AppNodeState struct {
+	Id       string `json:"id"`
+	Endpoint string `json:"endpoint"`
+	Status   string `json:"status"`
+}
+
 type MayastorPool struct {
 	Name   string             `json:"name"`
 	Spec   MayastorPoolSpec   `json:"spec"`
@@ -374,6 +397,39 @@ type MayastorPoolStatus struct {
 	Committed uint64           `json:"committed"`
 }
 
+// BlockDevice is a block device of a node, the size is in 512 byte blocks
+type BlockDevice struct {
+	Available      bool                  `json:"available"`
+	ConnectionType string                `json:"connection_type"`
+	Devlinks       []string              `json:"devlinks"`
+	Devmajor       int32                 `json:"devmajor"`
+	Devminor       int32                 `json:"devminor"`
+	Devname        string                `json:"devname"`
+	Devpath        string                `json:"devpath"`
+	Devtype        string                `json:"devtype"`
+	Filesystem     BlockDeviceFilesystem `json:"filesystem"`
+	IsRotational   *bool                 `json:"is_rotational"`
+	Model          string                `json:"model"`
+	Partition      BlockDevicePartition  `json:"partition"`
+	Size           int64                 `json:"size"`
+}
+
+type BlockDeviceFilesystem struct {
+	Fstype     string `json:"fstype"`
+	Label      string `json:"label"`
+	Mountpoint string `json:"mountpoint"`
+	Uuid       string `json:"uuid"`
+}
+
+type BlockDevicePartition struct {
+	Name   string `json:"name"`
+	Number int32  `json:"number"`
+	Parent string `json:"parent"`
+	Scheme string `json:"scheme"`
+	Typeid string `json:"typeid"`
+	Uuid   string `json:"uuid"`
+}
+
 type SnapshotMetadata struct {
 	Status              string                           `json:"status"`
 	Timestamp           string                           `json:"timestamp"`
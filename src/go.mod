@@ -40,6 +40,7 @@ require (
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/openebs/openebs-e2e/apps v0.0.0 // indirect
+	github.com/openebs/openebs-e2e/tools/e2e-agent/api v0.0.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
@@ -75,3 +76,5 @@ require (
 replace github.com/openebs/openebs-e2e/common v0.0.0 => ../common
 
 replace github.com/openebs/openebs-e2e/apps v0.0.0 => ../apps
+
+replace github.com/openebs/openebs-e2e/tools/e2e-agent/api v0.0.0 => ../tools/e2e-agent/api
@@ -0,0 +1,117 @@
+// cp-state captures the state of the control plane and the io-engines of the
+// cluster as JSON, and reports the differences between two captures.
+//
+//	cp-state capture [-label label] [-o file]
+//	cp-state diff [-json] before.json after.json
+//
+// The cluster is selected and configured as for test runs, by the kubeconfig
+// and the e2e_ environment variables, eg: e2e_mayastor_version.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/openebs/openebs-e2e/common/controlplane"
+	"github.com/openebs/openebs-e2e/common/cpstate"
+	"github.com/openebs/openebs-e2e/common/mayastorclient"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage:\n")
+	fmt.Fprintf(os.Stderr, "  %s capture [-label label] [-timeout duration] [-o file]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s diff [-json] before.json after.json\n", os.Args[0])
+	os.Exit(2)
+}
+
+func main() {
+	log.SetLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(os.Stderr)))
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "capture":
+		err = capture(os.Args[2:])
+	case "diff":
+		err = diff(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func capture(args []string) error {
+	flags := flag.NewFlagSet("capture", flag.ExitOnError)
+	label := flags.String("label", "", "label of the capture")
+	timeout := flags.Duration("timeout", 2*time.Minute, "timeout of the capture")
+	output := flags.String("o", "", "output file, default stdout")
+	_ = flags.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	nodes, err := controlplane.WithContext(ctx).ListMsns()
+	if err != nil {
+		return fmt.Errorf("failed to list nodes, %w", err)
+	}
+	var addrs []string
+	for _, node := range nodes {
+		if addr := cpstate.NodeAddress(node); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	mayastorclient.Initialise(addrs)
+
+	state := cpstate.Capture(ctx, *label)
+	for kind, err := range state.Errors {
+		fmt.Fprintf(os.Stderr, "failed to capture %s: %s\n", kind, err)
+	}
+	if *output != "" {
+		return state.WriteFile(*output)
+	}
+	return printJSON(state)
+}
+
+func diff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the differences as JSON")
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		usage()
+	}
+
+	before, err := cpstate.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := cpstate.ReadFile(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	stateDiff := cpstate.Diff(before, after)
+	if *asJSON {
+		return printJSON(stateDiff)
+	}
+	fmt.Print(stateDiff.String())
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
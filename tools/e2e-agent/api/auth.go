@@ -0,0 +1,11 @@
+package api
+
+const (
+	// AuthTokenEnv is the environment variable of the e2e-agent holding the
+	// bearer token required by every route, authentication is disabled if not set
+	AuthTokenEnv = "E2E_AGENT_TOKEN"
+	// AuthSecretName is the Secret in the e2e-agent namespace holding the
+	// bearer token under AuthSecretKey
+	AuthSecretName = "e2e-agent-auth"
+	AuthSecretKey  = "token"
+)
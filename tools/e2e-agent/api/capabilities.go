@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RouteSchemaVersions is the schema version of the messages of each route.
+// Bump the version of a route when its request or response changes incompatibly.
+var RouteSchemaVersions = map[string]int{
+	RouteHome:                           1,
+	RouteCapabilities:                   1,
+	RouteUngracefulReboot:               1,
+	RouteGracefulReboot:                 1,
+	RouteDropConnectionsFromNodes:       1,
+	RouteAcceptConnectionsFromNodes:     1,
+	RouteCreateFaultyDevice:             1,
+	RouteDeleteFaultyDevice:             1,
+	RouteDeviceControl:                  2,
+	RouteKillIoEngine:                   2,
+	RouteKillCsiController:              1,
+	RouteKillCsiNode:                    1,
+	RouteGetDeviceState:                 2,
+	RouteNvmeConnect:                    2,
+	RouteNvmeDisconnect:                 2,
+	RouteNvmeList:                       2,
+	RouteNvmeListSubSys:                 2,
+	RouteChecksumDevice:                 2,
+	RouteFsCheckDevice:                  1,
+	RouteXFSCheckDevice:                 1,
+	RouteFsFreezeDevice:                 1,
+	RouteFsUnfreezeDevice:               1,
+	RouteListDevice:                     2,
+	RouteFlushDiskWriteCache:            1,
+	RouteZeroingDisk:                    2,
+	RouteParted:                         1,
+	RouteFindmnt:                        1,
+	RouteLsblk:                          2,
+	RouteDmesg:                          1,
+	RouteSyslog:                         1,
+	RouteListReservation:                2,
+	RouteNvmeConnectWithHostId:          2,
+	RouteBlkDiscard:                     2,
+	RouteEventList:                      1,
+	RouteEventPublish:                   1,
+	RouteEventSubscribe:                 1,
+	RouteEventUnsubscribe:               1,
+	RouteEventUnsubscribeAll:            1,
+	RouteStats:                          1,
+	RouteCmp:                            1,
+	RouteHugePageZero:                   2,
+	RouteLvmVersion:                     1,
+	RouteLvmListVg:                      2,
+	RouteLvmListPv:                      2,
+	RouteLvmCreatePv:                    1,
+	RouteLvmCreateVg:                    1,
+	RouteLvmRemovePv:                    1,
+	RouteLvmRemoveVg:                    1,
+	RouteLvmThinPoolAutoExtendThreshold: 1,
+	RouteLvmThinPoolAutoExtendPercent:   1,
+	RouteLvmLvChangeMonitor:             1,
+	RouteLvmLvRemoveThinPool:            1,
+	RouteCreateLoopDevice:               1,
+	RouteDeleteLoopDevice:               1,
+	RouteZfsVersion:                     1,
+	RouteZfsListPool:                    2,
+	RouteZfsCreatePool:                  1,
+	RouteZfsDestroyPool:                 1,
+	RouteCreateHostPathDisk:             1,
+	RouteRemoveHostPathDisk:             1,
+	RouteListRdmaDevice:                 2,
+	RouteCreateRdmaDevice:               1,
+	RouteDeleteRdmaDevice:               1,
+	RouteEnableNetworkInterface:         1,
+	RouteDisableNetworkInterface:        1,
+	RouteApplyNetworkEmulation:          1,
+	RouteRemoveNetworkEmulation:         1,
+	RouteListFaults:                     1,
+	RouteClearFaults:                    1,
+	RouteCreateDmFaultDevice:            1,
+	RouteReloadDmFaultDevice:            1,
+	RouteRemoveDmFaultDevice:            1,
+	RouteUpdateDmDust:                   1,
+	RouteStartJob:                       1,
+	RouteGetJob:                         2,
+	RouteCancelJob:                      1,
+	RouteFollowLog:                      1,
+	RouteWriteDataset:                   1,
+	RouteVerifyDataset:                  1,
+	RoutePauseProcess:                   1,
+	RouteResumeProcess:                  1,
+	RouteApplyCgroupPressure:            1,
+	RouteRemoveCgroupPressure:           1,
+	RouteOomKillContainer:               1,
+}
+
+// Capabilities is the response of RouteCapabilities
+type Capabilities struct {
+	// Version of the e2e-agent image
+	Version string `json:"version"`
+	// Routes maps every route served by the e2e-agent to its schema version
+	Routes map[string]int `json:"routes"`
+	// AuthRequired is true if every route requires the bearer token, see AuthTokenEnv
+	AuthRequired bool `json:"authRequired"`
+}
+
+// Unsatisfied returns a description of each required route which is either
+// not served, or served with a different schema version
+func (c Capabilities) Unsatisfied(required map[string]int) []string {
+	var unsatisfied []string
+	for route, version := range required {
+		served, ok := c.Routes[route]
+		if !ok {
+			unsatisfied = append(unsatisfied, fmt.Sprintf("%s: not supported", route))
+		} else if served != version {
+			unsatisfied = append(unsatisfied, fmt.Sprintf("%s: schema version %d, required %d", route, served, version))
+		}
+	}
+	sort.Strings(unsatisfied)
+	return unsatisfied
+}
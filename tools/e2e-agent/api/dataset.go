@@ -0,0 +1,74 @@
+package api
+
+import "fmt"
+
+// DatasetBlockSize is the granularity at which dataset verification reports differences
+const DatasetBlockSize = 4096
+
+// DatasetSpec describes a deterministic dataset, the same spec always generates
+// the same directory tree, file sizes, contents and extended attributes
+type DatasetSpec struct {
+	Seed  int64 `json:"seed"`
+	Files int   `json:"files"`
+	// file sizes are uniformly distributed between MinFileSize and MaxFileSize bytes
+	MinFileSize int64 `json:"minFileSize"`
+	MaxFileSize int64 `json:"maxFileSize"`
+	// Dirs directories, nested at most DirDepth deep, hold the files
+	Dirs     int `json:"dirs,omitempty"`
+	DirDepth int `json:"dirDepth,omitempty"`
+	// number of the files which are sparse, every other 64KiB of a sparse file is a hole
+	SparseFiles int `json:"sparseFiles,omitempty"`
+	// set user extended attributes on every file
+	Xattrs bool `json:"xattrs,omitempty"`
+}
+
+// DatasetRequest writes the dataset to Path, a directory on the node
+// eg: the mount point of a volume
+type DatasetRequest struct {
+	Path string      `json:"path"`
+	Spec DatasetSpec `json:"spec"`
+}
+
+type DatasetEntry struct {
+	// Path is relative to the root of the dataset
+	Path   string            `json:"path"`
+	Dir    bool              `json:"dir,omitempty"`
+	Size   int64             `json:"size,omitempty"`
+	Sparse bool              `json:"sparse,omitempty"`
+	Sha256 string            `json:"sha256,omitempty"`
+	Xattrs map[string]string `json:"xattrs,omitempty"`
+}
+
+// DatasetManifest is the response of RouteWriteDataset
+type DatasetManifest struct {
+	Spec    DatasetSpec    `json:"spec"`
+	Entries []DatasetEntry `json:"entries"`
+}
+
+// DatasetVerifyRequest verifies the dataset at Path against the manifest
+type DatasetVerifyRequest struct {
+	Path     string          `json:"path"`
+	Manifest DatasetManifest `json:"manifest"`
+}
+
+// DatasetMismatch describes an entry which differs from the manifest,
+// Blocks are the indices of the DatasetBlockSize blocks of a file which differ
+type DatasetMismatch struct {
+	Path   string  `json:"path"`
+	Reason string  `json:"reason"`
+	Blocks []int64 `json:"blocks,omitempty"`
+}
+
+// DatasetVerifyReport is the response of RouteVerifyDataset
+type DatasetVerifyReport struct {
+	Verified   int               `json:"verified"`
+	Mismatches []DatasetMismatch `json:"mismatches,omitempty"`
+}
+
+// Err returns an error describing the mismatches, or nil if there are none
+func (r DatasetVerifyReport) Err() error {
+	if len(r.Mismatches) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d dataset entries differ, first: %s %s", len(r.Mismatches), r.Mismatches[0].Path, r.Mismatches[0].Reason)
+}
@@ -0,0 +1,69 @@
+package api
+
+// DmTarget is a device mapper target used to inject block level faults
+type DmTarget string
+
+const (
+	// every IO fails
+	DmTargetError DmTarget = "error"
+	// IOs fail periodically, see DmFlakeyParams
+	DmTargetFlakey DmTarget = "flakey"
+	// IOs are delayed, see DmDelayParams
+	DmTargetDelay DmTarget = "delay"
+	// reads of bad blocks fail, see DmDustParams
+	DmTargetDust DmTarget = "dust"
+)
+
+// DmFaultDevice wraps BackingDevice, eg: a pool disk or a loop device,
+// in the device mapper device /dev/mapper/<Name>.
+// Only the parameters of Target are used.
+type DmFaultDevice struct {
+	Name          string          `json:"name"`
+	BackingDevice string          `json:"backingDevice"`
+	Target        DmTarget        `json:"target"`
+	Flakey        *DmFlakeyParams `json:"flakey,omitempty"`
+	Delay         *DmDelayParams  `json:"delay,omitempty"`
+	Dust          *DmDustParams   `json:"dust,omitempty"`
+	LeaseSeconds  int             `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
+
+// DmFlakeyParams alternates between UpSeconds of normal operation and
+// DownSeconds during which IOs fail.
+// If none of the flags is set both reads and writes fail,
+// DropWrites silently discards writes instead of failing them.
+type DmFlakeyParams struct {
+	UpSeconds   int  `json:"upSeconds"`
+	DownSeconds int  `json:"downSeconds"`
+	ErrorReads  bool `json:"errorReads,omitempty"`
+	ErrorWrites bool `json:"errorWrites,omitempty"`
+	DropWrites  bool `json:"dropWrites,omitempty"`
+}
+
+// DmDelayParams delays reads and writes by the given milliseconds
+type DmDelayParams struct {
+	ReadDelayMs  int `json:"readDelayMs"`
+	WriteDelayMs int `json:"writeDelayMs"`
+}
+
+// DmDustParams emulates bad blocks of BlockSize bytes, 512 if not set.
+// Reads of a bad block fail until the block is written, once Enabled.
+type DmDustParams struct {
+	BlockSize int      `json:"blockSize,omitempty"`
+	BadBlocks []uint64 `json:"badBlocks,omitempty"`
+	Enabled   bool     `json:"enabled"`
+}
+
+// DmDustUpdate changes the bad blocks of a dust device at runtime,
+// Enabled is left unchanged if not set
+type DmDustUpdate struct {
+	Name            string   `json:"name"`
+	AddBadBlocks    []uint64 `json:"addBadBlocks,omitempty"`
+	RemoveBadBlocks []uint64 `json:"removeBadBlocks,omitempty"`
+	ClearBadBlocks  bool     `json:"clearBadBlocks,omitempty"`
+	Enabled         *bool    `json:"enabled,omitempty"`
+}
+
+// DmFaultDeviceResponse is the response of RouteCreateDmFaultDevice
+type DmFaultDeviceResponse struct {
+	DevicePath string `json:"devicePath"`
+}
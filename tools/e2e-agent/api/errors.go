@@ -0,0 +1,37 @@
+package api
+
+type E2eAgentErrcode int
+
+const (
+	// general errors
+	ErrNone         E2eAgentErrcode = 0
+	ErrGeneral      E2eAgentErrcode = 1
+	ErrJsonDecode   E2eAgentErrcode = 2
+	ErrJsonEncode   E2eAgentErrcode = 3
+	ErrReadFail     E2eAgentErrcode = 4
+	ErrExecFailed   E2eAgentErrcode = 5
+	ErrFileNotExist E2eAgentErrcode = 6
+
+	// request validation errors, the value matches the HTTP status code
+	ErrUnauthorized        E2eAgentErrcode = 401
+	ErrUnprocessableEntity E2eAgentErrcode = 422
+
+	// event errors
+	ErrConnectFail          E2eAgentErrcode = 101
+	ErrConnectedOther       E2eAgentErrcode = 102
+	ErrNotConnected         E2eAgentErrcode = 103
+	ErrSubscriptionNotFound E2eAgentErrcode = 104
+	ErrSubscribedAlready    E2eAgentErrcode = 105
+	ErrSubscribeFail        E2eAgentErrcode = 106
+	ErrStreamAddFail        E2eAgentErrcode = 107
+	ErrStreamCreateFail     E2eAgentErrcode = 108
+	ErrPublishFail          E2eAgentErrcode = 109
+	ErrUnSubscribeFail      E2eAgentErrcode = 110
+)
+
+// E2eAgentError is the envelope wrapping every structured e2e-agent response.
+// For routes with a typed response, Output holds the JSON encoding of that type.
+type E2eAgentError struct {
+	Output    string          `json:"output"`
+	Errorcode E2eAgentErrcode `json:"errorcode"`
+}
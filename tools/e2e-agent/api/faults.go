@@ -0,0 +1,37 @@
+package api
+
+import "time"
+
+type FaultKind string
+
+const (
+	// connections from Target dropped on NetworkInterface
+	FaultDropConnection FaultKind = "dropConnection"
+	// device mapper device Target created
+	FaultFaultyDevice FaultKind = "faultyDevice"
+	// block device Target set offline
+	FaultDeviceOffline FaultKind = "deviceOffline"
+	// network interface Target disabled
+	FaultNetworkInterfaceDown FaultKind = "networkInterfaceDown"
+	// network emulation applied on network interface Target
+	FaultNetworkEmulation FaultKind = "networkEmulation"
+	// processes of container Target, or Target/process name, stopped with SIGSTOP
+	FaultProcessPaused FaultKind = "processPaused"
+	// cgroup of container Target throttled, Restore holds the previous limits
+	FaultCgroupPressure FaultKind = "cgroupPressure"
+)
+
+// Fault is a fault injected by the e2e-agent which has not been reverted.
+// The agent reverts a fault when its lease expires and on restart,
+// requests injecting faults accept an optional LeaseSeconds.
+type Fault struct {
+	Id               string    `json:"id"`
+	Kind             FaultKind `json:"kind"`
+	Target           string    `json:"target"`
+	NetworkInterface string    `json:"networkInterface,omitempty"`
+	// Restore maps the files changed by the fault to their previous content
+	Restore map[string]string `json:"restore,omitempty"`
+	Created time.Time         `json:"created"`
+	// Expires is the zero time if the fault has no lease
+	Expires time.Time `json:"expires"`
+}
@@ -0,0 +1,3 @@
+module github.com/openebs/openebs-e2e/tools/e2e-agent/api
+
+go 1.19
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// JobOperation is a long running operation executed as a job,
+// its arguments are those of the synchronous route of the same name
+type JobOperation string
+
+const (
+	JobChecksumDevice JobOperation = "checksumdevice" // Device.DevicePath
+	JobXFSCheckDevice JobOperation = "xfscheckdevice" // Device.DevicePath and Device.FsType
+	JobZeroingDisk    JobOperation = "zeroingdisk"    // Disk
+	JobBlkDiscard     JobOperation = "blkdiscard"     // BlkDiscard
+)
+
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// JobRequest starts a job, only the arguments of Operation are used
+type JobRequest struct {
+	Operation  JobOperation `json:"operation"`
+	Device     *Device      `json:"device,omitempty"`
+	Disk       *Disk        `json:"disk,omitempty"`
+	BlkDiscard *BlkDiscard  `json:"blkDiscard,omitempty"`
+}
+
+// JobResponse is the response of RouteStartJob
+type JobResponse struct {
+	Id string `json:"id"`
+}
+
+// Job is the status of a job, the agent forgets jobs an hour after they finish
+type Job struct {
+	Id        string       `json:"id"`
+	Operation JobOperation `json:"operation"`
+	State     JobState     `json:"state"`
+	// Output is the stdout of the job, eg: the cksum line, truncated to its last 64KiB
+	Output string `json:"output"`
+	// Stderr is the stderr of the job, truncated to its last 64KiB
+	Stderr string `json:"stderr"`
+	// Progress is the last line of Stderr, on which the job commands report their progress,
+	// eg: the bytes read so far by a checksum
+	Progress string `json:"progress"`
+	// ExitCode is valid once the job has finished, -1 if it did not exit
+	ExitCode int       `json:"exitCode"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// Done returns true once the job has finished
+func (j Job) Done() bool {
+	return j.State != JobRunning
+}
+
+// JobPath returns the path of RouteGetJob or RouteCancelJob for the job
+func JobPath(route string, id string) string {
+	return strings.Replace(route, "{id}", url.PathEscape(id), 1)
+}
@@ -0,0 +1,29 @@
+package api
+
+import "time"
+
+type LogSource string
+
+const (
+	// kernel ring buffer, as printed by dmesg
+	LogKernel LogSource = "kernel"
+	// systemd journal of the node
+	LogJournal LogSource = "journal"
+)
+
+// LogFollowRequest follows a log of the node from Since, or from the time of the
+// request if not set, until the client closes the connection.
+// Lines not matching the regular expression Filter, if set, are not sent.
+type LogFollowRequest struct {
+	Source LogSource `json:"source"`
+	Since  time.Time `json:"since,omitempty"`
+	Filter string    `json:"filter,omitempty"`
+}
+
+// LogLine is sent for every log line, the response of RouteFollowLog
+// is a stream of JSON encoded LogLine values separated by newlines
+type LogLine struct {
+	Time   time.Time `json:"time"`
+	Source LogSource `json:"source"`
+	Text   string    `json:"text"`
+}
@@ -0,0 +1,39 @@
+package api
+
+// ProcessTarget selects the processes of a container on the node.
+// ContainerId is the id of the container reported in the pod status,
+// with or without the runtime prefix, eg: containerd://.
+// If ProcessName is set only the processes of that name are selected,
+// otherwise all processes of the container.
+type ProcessTarget struct {
+	ContainerId string `json:"containerId"`
+	ProcessName string `json:"processName,omitempty"`
+}
+
+// ProcessPause stops the processes with SIGSTOP, they are continued
+// with SIGCONT after DurationSeconds or when resumed.
+type ProcessPause struct {
+	ProcessTarget
+	DurationSeconds int `json:"durationSeconds"`
+}
+
+// CgroupPressure throttles the cgroup of a container, requires cgroup v2.
+// CpuPercent limits the container to the percentage of one CPU, MemoryHighBytes
+// throttles and reclaims the memory of the container above that usage.
+// Limits which are not set are left unchanged, the previous limits are
+// restored when the pressure is removed.
+// ProcessName of the target is ignored.
+type CgroupPressure struct {
+	ProcessTarget
+	CpuPercent      int   `json:"cpuPercent,omitempty"`
+	MemoryHighBytes int64 `json:"memoryHighBytes,omitempty"`
+	LeaseSeconds    int   `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
+
+// ProcessFaultResponse lists the processes affected by a process fault,
+// Pids are those of the host
+type ProcessFaultResponse struct {
+	ContainerId string `json:"containerId"`
+	Cgroup      string `json:"cgroup"`
+	Pids        []int  `json:"pids"`
+}
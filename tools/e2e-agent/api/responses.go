@@ -0,0 +1,170 @@
+package api
+
+// NvmeDevice is a namespace reported by nvme list
+type NvmeDevice struct {
+	NameSpace    int    `json:"NameSpace"`
+	DevicePath   string `json:"DevicePath"`
+	Firmware     string `json:"Firmware"`
+	Index        int    `json:"Index"`
+	ModelNumber  string `json:"ModelNumber"`
+	SerialNumber string `json:"SerialNumber"`
+	UsedBytes    int64  `json:"UsedBytes"`
+	MaximumLBA   int64  `json:"MaximumLBA"`
+	PhysicalSize int64  `json:"PhysicalSize"`
+	SectorSize   int64  `json:"SectorSize"`
+}
+
+// NvmeListResponse is the response of RouteNvmeList
+type NvmeListResponse struct {
+	Devices []NvmeDevice `json:"Devices"`
+}
+
+// NvmePath is a controller path of an nvme subsystem
+type NvmePath struct {
+	Name      string `json:"Name"`
+	Transport string `json:"Transport"`
+	Address   string `json:"Address"`
+	State     string `json:"State"`
+	ANAState  string `json:"ANAState,omitempty"`
+}
+
+// NvmeSubsystem is a subsystem reported by nvme list-subsys
+type NvmeSubsystem struct {
+	Name  string     `json:"Name"`
+	NQN   string     `json:"NQN"`
+	Paths []NvmePath `json:"Paths"`
+}
+
+// NvmeListSubSysResponse is the response of RouteNvmeListSubSys,
+// the agent normalises the output of the different nvme-cli versions into this form
+type NvmeListSubSysResponse struct {
+	HostNQN    string          `json:"HostNQN,omitempty"`
+	HostID     string          `json:"HostID,omitempty"`
+	Subsystems []NvmeSubsystem `json:"Subsystems"`
+}
+
+// RegisteredController is a controller entry of an nvme reservation report
+type RegisteredController struct {
+	Cntlid int    `json:"cntlid"`
+	Rcsts  int    `json:"rcsts"`
+	Rkey   uint64 `json:"rkey"`
+	Hostid string `json:"hostid"`
+}
+
+// ReservationReport is the response of RouteListReservation
+type ReservationReport struct {
+	Gen       int                    `json:"gen"`
+	Rtype     int                    `json:"rtype"`
+	Regctl    int                    `json:"regctl"`
+	Ptpls     int                    `json:"ptpls"`
+	Regctlext []RegisteredController `json:"regctlext"`
+}
+
+// RdmaLink is an entry of the response of RouteListRdmaDevice
+type RdmaLink struct {
+	IfIndex       int    `json:"ifindex"`
+	IfName        string `json:"ifname"`
+	Port          int    `json:"port"`
+	State         string `json:"state"`
+	PhysicalState string `json:"physical_state"`
+	NetDev        string `json:"netdev"`
+	NetDevIndex   int    `json:"netdev_index"`
+}
+
+// DeviceState is the response of RouteGetDeviceState
+type DeviceState struct {
+	// Device is the name of the block device resolved from the disk, eg: sdb
+	Device string `json:"device"`
+	// State is the content of /sys/block/<device>/device/state, eg: running
+	State string `json:"state"`
+}
+
+// DeviceChecksum is the response of RouteChecksumDevice, the output of cksum
+type DeviceChecksum struct {
+	Checksum   uint32 `json:"checksum"`
+	Size       uint64 `json:"size"`
+	DevicePath string `json:"devicePath"`
+}
+
+// DeviceList is the response of RouteListDevice, the entries of /dev
+type DeviceList struct {
+	Devices []string `json:"devices"`
+}
+
+// BlockDevice is a block device reported by lsblk, Size is in bytes
+type BlockDevice struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Size       uint64        `json:"size"`
+	FsType     string        `json:"fstype,omitempty"`
+	MountPoint string        `json:"mountpoint,omitempty"`
+	Children   []BlockDevice `json:"children,omitempty"`
+}
+
+// LsblkResponse is the response of RouteLsblk
+type LsblkResponse struct {
+	BlockDevices []BlockDevice `json:"blockdevices"`
+}
+
+// LvmVg is an entry of the response of RouteLvmListVg, sizes are in bytes
+type LvmVg struct {
+	Name    string `json:"name"`
+	PvCount int    `json:"pvCount"`
+	LvCount int    `json:"lvCount"`
+	Attr    string `json:"attr"`
+	Size    uint64 `json:"size"`
+	Free    uint64 `json:"free"`
+}
+
+// LvmPv is an entry of the response of RouteLvmListPv, sizes are in bytes
+type LvmPv struct {
+	Name   string `json:"name"`
+	VgName string `json:"vgName"`
+	Attr   string `json:"attr"`
+	Size   uint64 `json:"size"`
+	Free   uint64 `json:"free"`
+}
+
+// ZfsPool is an entry of the response of RouteZfsListPool, sizes are in bytes
+type ZfsPool struct {
+	Name   string `json:"name"`
+	Size   uint64 `json:"size"`
+	Alloc  uint64 `json:"alloc"`
+	Free   uint64 `json:"free"`
+	Health string `json:"health"`
+}
+
+// KilledProcess is the response of RouteKillIoEngine
+type KilledProcess struct {
+	Name string `json:"name"`
+	Pids []int  `json:"pids"`
+}
+
+// NvmeConnection is the response of RouteNvmeConnect and RouteNvmeConnectWithHostId,
+// Output is the output of nvme connect, which is empty if the connection succeeded
+type NvmeConnection struct {
+	TargetIp string `json:"targetIp"`
+	Nqn      string `json:"nqn"`
+	HostNqn  string `json:"hostNqn,omitempty"`
+	HostId   string `json:"hostId,omitempty"`
+	Output   string `json:"output,omitempty"`
+}
+
+// NvmeDisconnection is the response of RouteNvmeDisconnect
+type NvmeDisconnection struct {
+	Nqn string `json:"nqn"`
+	// Controllers is the number of controllers disconnected
+	Controllers int `json:"controllers"`
+}
+
+// DiskWrite is the response of RouteZeroingDisk and RouteBlkDiscard,
+// Output is the output of the command, eg: the statistics of dd
+type DiskWrite struct {
+	Device string `json:"device"`
+	Output string `json:"output,omitempty"`
+}
+
+// HugePages is the response of RouteHugePageZero
+type HugePages struct {
+	NrHugePages int `json:"nrHugePages"`
+}
@@ -0,0 +1,92 @@
+// Package api holds the request and response messages exchanged between the
+// e2e-agent and its clients, so that both sides are compiled against the same
+// contract.
+package api
+
+// Routes served by the e2e-agent, all are POST requests unless stated otherwise
+const (
+	RouteHome                           = "/" // GET
+	RouteCapabilities                   = "/capabilities"
+	RouteUngracefulReboot               = "/ungracefulReboot"
+	RouteGracefulReboot                 = "/gracefulReboot"
+	RouteDropConnectionsFromNodes       = "/dropConnectionsFromNodes"
+	RouteAcceptConnectionsFromNodes     = "/acceptConnectionsFromNodes"
+	RouteCreateFaultyDevice             = "/createFaultyDevice"
+	RouteDeleteFaultyDevice             = "/deleteFaultyDevice"
+	RouteDeviceControl                  = "/devicecontrol"
+	RouteKillIoEngine                   = "/killioengine"
+	RouteKillCsiController              = "/killCsiController"
+	RouteKillCsiNode                    = "/killCsiNode"
+	RouteGetDeviceState                 = "/getdevicestate"
+	RouteNvmeConnect                    = "/nvmeconnect"
+	RouteNvmeDisconnect                 = "/nvmedisconnect"
+	RouteNvmeList                       = "/nvmelist"
+	RouteNvmeListSubSys                 = "/nvmelistsubsys"
+	RouteChecksumDevice                 = "/checksumdevice"
+	RouteFsCheckDevice                  = "/fscheckdevice"
+	RouteXFSCheckDevice                 = "/xfscheckdevice"
+	RouteFsFreezeDevice                 = "/fsfreezedevice"
+	RouteFsUnfreezeDevice               = "/fsunfreezedevice"
+	RouteListDevice                     = "/listdevice"
+	RouteFlushDiskWriteCache            = "/flushDiskWriteCache"
+	RouteZeroingDisk                    = "/zeroingdisk"
+	RouteParted                         = "/parted"
+	RouteFindmnt                        = "/findmnt"
+	RouteLsblk                          = "/lsblk"
+	RouteDmesg                          = "/dmesg"
+	RouteSyslog                         = "/syslog"
+	RouteListReservation                = "/listreservation"
+	RouteNvmeConnectWithHostId          = "/nvmeconnectwithhostid"
+	RouteBlkDiscard                     = "/blkdiscard"
+	RouteEventList                      = "/event/list"
+	RouteEventPublish                   = "/event/publish"
+	RouteEventSubscribe                 = "/event/subscribe"
+	RouteEventUnsubscribe               = "/event/unsubscribe"
+	RouteEventUnsubscribeAll            = "/event/unsubscribeall"
+	RouteStats                          = "/stats"
+	RouteCmp                            = "/cmp"
+	RouteHugePageZero                   = "/hugepagezero"
+	RouteLvmVersion                     = "/lvmversion"
+	RouteLvmListVg                      = "/lvmlistvg"
+	RouteLvmListPv                      = "/lvmlistpv"
+	RouteLvmCreatePv                    = "/lvmcreatepv"
+	RouteLvmCreateVg                    = "/lvmcreatevg"
+	RouteLvmRemovePv                    = "/lvmremovepv"
+	RouteLvmRemoveVg                    = "/lvmremovevg"
+	RouteLvmThinPoolAutoExtendThreshold = "/lvmthinpoolautoextendthreshold"
+	RouteLvmThinPoolAutoExtendPercent   = "/lvmthinpoolautoextendpercent"
+	RouteLvmLvChangeMonitor             = "/lvmlvchangemonitor"
+	RouteLvmLvRemoveThinPool            = "/lvmlvremovethinpool"
+	RouteCreateLoopDevice               = "/createloopdevice"
+	RouteDeleteLoopDevice               = "/deleteloopdevice"
+	RouteZfsVersion                     = "/zfsversion"
+	RouteZfsListPool                    = "/zfslistpool"
+	RouteZfsCreatePool                  = "/zfscreatepool"
+	RouteZfsDestroyPool                 = "/zfsdestroypool"
+	RouteCreateHostPathDisk             = "/createhostpathdisk"
+	RouteRemoveHostPathDisk             = "/removehostpathdisk"
+	RouteListRdmaDevice                 = "/listrdmadevice"
+	RouteCreateRdmaDevice               = "/createrdmadevice"
+	RouteDeleteRdmaDevice               = "/deleterdmadevice"
+	RouteEnableNetworkInterface         = "/enablenetworkinterface"
+	RouteDisableNetworkInterface        = "/disablenetworkinterface"
+	RouteApplyNetworkEmulation          = "/networkemulation/apply"
+	RouteRemoveNetworkEmulation         = "/networkemulation/remove"
+	RouteListFaults                     = "/faults"
+	RouteClearFaults                    = "/faults/clear"
+	RouteCreateDmFaultDevice            = "/dmfaultdevice/create"
+	RouteReloadDmFaultDevice            = "/dmfaultdevice/reload"
+	RouteRemoveDmFaultDevice            = "/dmfaultdevice/remove"
+	RouteUpdateDmDust                   = "/dmfaultdevice/dust"
+	RouteStartJob                       = "/jobs"
+	RouteGetJob                         = "/jobs/{id}"
+	RouteCancelJob                      = "/jobs/{id}/cancel"
+	RouteFollowLog                      = "/log/follow"
+	RouteWriteDataset                   = "/dataset/write"
+	RouteVerifyDataset                  = "/dataset/verify"
+	RoutePauseProcess                   = "/process/pause"
+	RouteResumeProcess                  = "/process/resume"
+	RouteApplyCgroupPressure            = "/process/pressure/apply"
+	RouteRemoveCgroupPressure           = "/process/pressure/remove"
+	RouteOomKillContainer               = "/process/oomkill"
+)
@@ -0,0 +1,137 @@
+package api
+
+// NodeList is the list of nodes to be passed to e2e-agent
+type NodeList struct {
+	Nodes            []string `json:"nodes"`
+	NetworkInterface string   `json:"networkInterface"`
+	LeaseSeconds     int      `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
+
+type CmdList struct {
+	Cmd string `json:"cmd"`
+}
+
+type Device struct {
+	Device       string `json:"device"`
+	Table        string `json:"table"`
+	DevicePath   string `json:"devicePath"`
+	Uuid         string `json:"uuid"`
+	FsType       string `json:"fsType"`
+	LeaseSeconds int    `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
+
+type ControlledDevice struct {
+	Device       string `json:"device"`
+	State        string `json:"state"`
+	LeaseSeconds int    `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
+
+type DiskPool struct {
+	Disk string `json:"disk"`
+}
+
+type Product struct {
+	Product string `json:"product"`
+	Pid     string `json:"pid"`
+}
+
+type Nvme struct {
+	TargetIp string `json:"targetIp"`
+	Nqn      string `json:"nqn"`
+	HostNqn  string `json:"hostNqn"`
+	HostId   string `json:"hostId"`
+}
+
+type Disk struct {
+	Device         string `json:"device"`
+	SeekParam      string `json:"seekParam"`
+	BlockSizeParam string `json:"blockSizeParam"`
+}
+
+type BlkDiscard struct {
+	Device  string `json:"device"`
+	Options string `json:"options"`
+}
+
+type CmpPaths struct {
+	Path1 string `json:"path1"`
+	Path2 string `json:"path2"`
+}
+
+type NetworkInterface struct {
+	NetworkInterface string `json:"networkInterface"`
+	LeaseSeconds     int    `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
+
+type Lvm struct {
+	Pv                          string `json:"pv"`                          // Physical volume
+	Vg                          string `json:"vg"`                          // Volume group
+	ThinPoolAutoExtendThreshold int    `json:"thinPoolAutoExtendThreshold"` // thin pool auto extend threshold
+	ThinPoolAutoExtendPercent   int    `json:"thinPoolAutoExtendPercent"`   // thin pool auto extend percent
+}
+
+// LoopDevice has the attributes of a virtual disk which is emulated for testing
+type LoopDevice struct {
+	// Size in bytes
+	Size int64 `json:"size"`
+	// The backing image name
+	// eg: fake123
+	ImageName string `json:"imageName"`
+	// Image directory
+	// eg: /tmp
+	ImgDir string `json:"imgDir"`
+	// the disk name
+	// eg: /tmp/loop9002
+	DiskPath string `json:"diskPath"`
+	// mount point if any
+	MountPoint string `json:"mountPoint"`
+}
+
+type Zpool struct {
+	PoolDiskPath string `json:"poolDiskPath"`
+	PoolName     string `json:"poolName"`
+}
+
+type Rdma struct {
+	DeviceName    string `json:"deviceName"`
+	InterfaceName string `json:"interfaceName"`
+}
+
+type Stats struct {
+	ServiceAddr string `json:"serviceAddr"`
+}
+
+type EventSubscriptionRequest struct {
+	EventServerAddr string `json:"eventServerAddr"`
+	Subject         string `json:"subject"`
+}
+
+type EventPublishRequest struct {
+	EventServerAddr string `json:"eventServerAddr"`
+	Subject         string `json:"subject"`
+	Data            string `json:"data"`
+}
+
+type EventRequest struct {
+	Subject string `json:"subject"`
+}
+
+// NetworkEmulation degrades the traffic sent from the node on NetworkInterface
+// to the Peers, using the tc netem queueing discipline.
+// Only egress is shaped, the traffic received from the Peers is not degraded,
+// to degrade both directions apply an emulation on the peer nodes too.
+// Zero valued parameters are not applied.
+type NetworkEmulation struct {
+	NetworkInterface string   `json:"networkInterface"`
+	Peers            []string `json:"peers"`
+	// DelayMs and JitterMs are in milliseconds
+	DelayMs  int `json:"delayMs,omitempty"`
+	JitterMs int `json:"jitterMs,omitempty"`
+	// percentages of packets lost, duplicated and reordered, reordering requires a delay
+	LossPercent      float64 `json:"lossPercent,omitempty"`
+	DuplicatePercent float64 `json:"duplicatePercent,omitempty"`
+	ReorderPercent   float64 `json:"reorderPercent,omitempty"`
+	// Rate limit in tc units, eg: 10mbit
+	Rate         string `json:"rate,omitempty"`
+	LeaseSeconds int    `json:"leaseSeconds,omitempty"` // fault lease, see Fault
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+// authToken is the bearer token required by every route, if set
+var authToken = os.Getenv(api.AuthTokenEnv)
+
+// authMiddleware rejects requests without the bearer token
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authToken != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) != 1 {
+				klog.Error("unauthorized request ", r.URL.Path, " from ", r.RemoteAddr)
+				w.WriteHeader(http.StatusUnauthorized)
+				WrapResult("unauthorized", api.ErrUnauthorized, w)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -9,7 +9,7 @@
 # as long as we do not make breaking changes.
 set -e
 IMAGE="openebs/e2e-agent"
-TAG="v3.0.6"
+TAG="v3.3.0"
 registry=""
 tag_as_latest=""
 
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+var capabilities api.Capabilities
+
+// initCapabilities records the routes registered with the router,
+// so that the capabilities always reflect what is actually served
+func initCapabilities(router *mux.Router) error {
+	capabilities = api.Capabilities{
+		Version:      Version,
+		Routes:       make(map[string]int),
+		AuthRequired: authToken != "",
+	}
+	return router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		version, ok := api.RouteSchemaVersions[path]
+		if !ok {
+			klog.Warning("no schema version for route ", path)
+			version = 1
+		}
+		capabilities.Routes[path] = version
+		return nil
+	})
+}
+
+// GetCapabilities returns the agent version and the routes it supports
+func GetCapabilities(w http.ResponseWriter, r *http.Request) {
+	WrapResponse(capabilities, w)
+}
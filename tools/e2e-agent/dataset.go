@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// contents are generated in chunks, every other chunk of a sparse file is a hole
+	datasetChunkSize = 64 * 1024
+	// limit of the differing blocks reported for a file
+	datasetMaxReportedBlocks = 64
+)
+
+type datasetFile struct {
+	path   string
+	size   int64
+	sparse bool
+	seed   int64
+}
+
+// datasetLayout returns the directories, excluding the root, and the files of the dataset
+func datasetLayout(spec api.DatasetSpec) ([]string, []datasetFile, error) {
+	if spec.Files < 0 || spec.Dirs < 0 || spec.DirDepth < 0 {
+		return nil, nil, fmt.Errorf("files, dirs and dirDepth must not be negative")
+	}
+	if spec.MinFileSize < 0 || spec.MaxFileSize < spec.MinFileSize {
+		return nil, nil, fmt.Errorf("invalid file sizes min %d max %d", spec.MinFileSize, spec.MaxFileSize)
+	}
+	if spec.SparseFiles < 0 || spec.SparseFiles > spec.Files {
+		return nil, nil, fmt.Errorf("invalid number of sparse files %d", spec.SparseFiles)
+	}
+	maxDepth := spec.DirDepth
+	if maxDepth == 0 {
+		maxDepth = 1
+	}
+	rng := rand.New(rand.NewSource(spec.Seed))
+
+	dirs := []string{""}
+	depths := []int{0}
+	for i := 0; i < spec.Dirs; i++ {
+		var parents []int
+		for ix, depth := range depths {
+			if depth < maxDepth {
+				parents = append(parents, ix)
+			}
+		}
+		parent := parents[rng.Intn(len(parents))]
+		dirs = append(dirs, path.Join(dirs[parent], fmt.Sprintf("dir%03d", i)))
+		depths = append(depths, depths[parent]+1)
+	}
+
+	files := make([]datasetFile, 0, spec.Files)
+	for i := 0; i < spec.Files; i++ {
+		dir := dirs[rng.Intn(len(dirs))]
+		files = append(files, datasetFile{
+			path:   path.Join(dir, fmt.Sprintf("file%05d", i)),
+			size:   spec.MinFileSize + rng.Int63n(spec.MaxFileSize-spec.MinFileSize+1),
+			sparse: i < spec.SparseFiles,
+			seed:   rng.Int63(),
+		})
+	}
+	return dirs[1:], files, nil
+}
+
+// chunk returns the expected contents of a chunk of the file
+func (f datasetFile) chunk(index int64, buf []byte) []byte {
+	length := f.size - index*datasetChunkSize
+	if length > datasetChunkSize {
+		length = datasetChunkSize
+	}
+	buf = buf[:length]
+	if f.isHole(index) {
+		for i := range buf {
+			buf[i] = 0
+		}
+		return buf
+	}
+	rng := rand.New(rand.NewSource(f.seed + index))
+	_, _ = rng.Read(buf)
+	return buf
+}
+
+func (f datasetFile) isHole(index int64) bool {
+	return f.sparse && index%2 == 1
+}
+
+func (f datasetFile) chunks() int64 {
+	return (f.size + datasetChunkSize - 1) / datasetChunkSize
+}
+
+func (f datasetFile) xattrs() map[string]string {
+	return map[string]string{
+		"user.e2e.path": f.path,
+		"user.e2e.seed": strconv.FormatInt(f.seed, 16),
+	}
+}
+
+// writeDatasetFile writes the file and returns its manifest entry
+func writeDatasetFile(root string, file datasetFile, xattrs bool) (api.DatasetEntry, error) {
+	entry := api.DatasetEntry{Path: file.path, Size: file.size, Sparse: file.sparse}
+	name := filepath.Join(root, file.path)
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return entry, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	buf := make([]byte, datasetChunkSize)
+	for index := int64(0); index < file.chunks(); index++ {
+		data := file.chunk(index, buf)
+		hash.Write(data)
+		if file.isHole(index) {
+			if _, err = f.Seek(int64(len(data)), io.SeekCurrent); err != nil {
+				return entry, err
+			}
+			continue
+		}
+		if _, err = f.Write(data); err != nil {
+			return entry, err
+		}
+	}
+	// a trailing hole is only allocated by setting the size
+	if err = f.Truncate(file.size); err != nil {
+		return entry, err
+	}
+	if err = f.Sync(); err != nil {
+		return entry, err
+	}
+	entry.Sha256 = hex.EncodeToString(hash.Sum(nil))
+	if xattrs {
+		entry.Xattrs = file.xattrs()
+		for key, value := range entry.Xattrs {
+			if err = syscall.Setxattr(name, key, []byte(value), 0); err != nil {
+				return entry, fmt.Errorf("failed to set xattr %s on %s, error: %v", key, file.path, err)
+			}
+		}
+	}
+	return entry, nil
+}
+
+// writeDataset writes the dataset under root and returns its manifest
+func writeDataset(root string, spec api.DatasetSpec) (api.DatasetManifest, error) {
+	manifest := api.DatasetManifest{Spec: spec}
+	dirs, files, err := datasetLayout(spec)
+	if err != nil {
+		return manifest, err
+	}
+	for _, dir := range dirs {
+		if err = os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			return manifest, err
+		}
+		manifest.Entries = append(manifest.Entries, api.DatasetEntry{Path: dir, Dir: true})
+	}
+	for _, file := range files {
+		entry, err := writeDatasetFile(root, file, spec.Xattrs)
+		if err != nil {
+			return manifest, err
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+	sort.Slice(manifest.Entries, func(i, j int) bool {
+		return manifest.Entries[i].Path < manifest.Entries[j].Path
+	})
+	return manifest, nil
+}
+
+// verifyDatasetFile compares the file with its expected contents and extended attributes
+func verifyDatasetFile(root string, file datasetFile, entry api.DatasetEntry) *api.DatasetMismatch {
+	mismatch := &api.DatasetMismatch{Path: entry.Path}
+	name := filepath.Join(root, file.path)
+	f, err := os.Open(name)
+	if err != nil {
+		mismatch.Reason = err.Error()
+		return mismatch
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		mismatch.Reason = err.Error()
+		return mismatch
+	}
+	if info.Size() != entry.Size {
+		mismatch.Reason = fmt.Sprintf("size %d, expected %d", info.Size(), entry.Size)
+		return mismatch
+	}
+
+	expectedBuf := make([]byte, datasetChunkSize)
+	actual := make([]byte, datasetChunkSize)
+	differing := 0
+	for index := int64(0); index < file.chunks(); index++ {
+		expected := file.chunk(index, expectedBuf)
+		if _, err = io.ReadFull(f, actual[:len(expected)]); err != nil {
+			mismatch.Reason = err.Error()
+			return mismatch
+		}
+		for offset := 0; offset < len(expected); offset += api.DatasetBlockSize {
+			end := offset + api.DatasetBlockSize
+			if end > len(expected) {
+				end = len(expected)
+			}
+			if bytes.Equal(expected[offset:end], actual[offset:end]) {
+				continue
+			}
+			differing++
+			if len(mismatch.Blocks) < datasetMaxReportedBlocks {
+				mismatch.Blocks = append(mismatch.Blocks, (index*datasetChunkSize+int64(offset))/api.DatasetBlockSize)
+			}
+		}
+	}
+	if differing != 0 {
+		mismatch.Reason = fmt.Sprintf("%d blocks differ", differing)
+		return mismatch
+	}
+
+	value := make([]byte, 1024)
+	for key, expected := range entry.Xattrs {
+		size, err := syscall.Getxattr(name, key, value)
+		if err != nil {
+			mismatch.Reason = fmt.Sprintf("xattr %s, error: %v", key, err)
+			return mismatch
+		}
+		if string(value[:size]) != expected {
+			mismatch.Reason = fmt.Sprintf("xattr %s is %q, expected %q", key, value[:size], expected)
+			return mismatch
+		}
+	}
+	return nil
+}
+
+// verifyDataset verifies the dataset under root against the manifest
+func verifyDataset(root string, manifest api.DatasetManifest) (api.DatasetVerifyReport, error) {
+	var report api.DatasetVerifyReport
+	_, files, err := datasetLayout(manifest.Spec)
+	if err != nil {
+		return report, err
+	}
+	layout := make(map[string]datasetFile, len(files))
+	for _, file := range files {
+		layout[file.path] = file
+	}
+
+	expected := make(map[string]bool, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		expected[entry.Path] = true
+		if entry.Dir {
+			info, err := os.Stat(filepath.Join(root, entry.Path))
+			if err != nil {
+				report.Mismatches = append(report.Mismatches, api.DatasetMismatch{Path: entry.Path, Reason: err.Error()})
+			} else if !info.IsDir() {
+				report.Mismatches = append(report.Mismatches, api.DatasetMismatch{Path: entry.Path, Reason: "not a directory"})
+			} else {
+				report.Verified++
+			}
+			continue
+		}
+		file, ok := layout[entry.Path]
+		if !ok {
+			return report, fmt.Errorf("manifest entry %s is not generated by the dataset spec", entry.Path)
+		}
+		if mismatch := verifyDatasetFile(root, file, entry); mismatch != nil {
+			report.Mismatches = append(report.Mismatches, *mismatch)
+		} else {
+			report.Verified++
+		}
+	}
+
+	err = filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, name)
+		if err != nil || rel == "." {
+			return err
+		}
+		if rel == "lost+found" {
+			return filepath.SkipDir
+		}
+		if !expected[filepath.ToSlash(rel)] {
+			report.Mismatches = append(report.Mismatches, api.DatasetMismatch{Path: rel, Reason: "not in manifest"})
+		}
+		return nil
+	})
+	return report, err
+}
+
+// datasetRoot returns the host path of the dataset root, which must be an existing directory
+func datasetRoot(dir string) (string, error) {
+	if !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("dataset path %s is not absolute", dir)
+	}
+	root := filepath.Join("/host", dir)
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("dataset path %s is not a directory", dir)
+	}
+	return root, nil
+}
+
+// WriteDataset writes a deterministic dataset to a directory on the node and returns its manifest
+func WriteDataset(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var req api.DatasetRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	root, err := datasetRoot(req.Path)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("write dataset, data: ", req)
+	manifest, err := writeDataset(root, req.Spec)
+	if err != nil {
+		msg = fmt.Sprintf("failed to write dataset, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrGeneral, w)
+		return
+	}
+	WrapResponse(manifest, w)
+}
+
+// VerifyDataset verifies a dataset written by WriteDataset against its manifest
+func VerifyDataset(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var req api.DatasetVerifyRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	root, err := datasetRoot(req.Path)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("verify dataset, path: ", req.Path, " spec: ", req.Manifest.Spec)
+	report, err := verifyDataset(root, req.Manifest)
+	if err != nil {
+		msg = fmt.Sprintf("failed to verify dataset, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrGeneral, w)
+		return
+	}
+	WrapResponse(report, w)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
@@ -14,24 +15,7 @@ const (
 	DiskImageNamePrefix = "openebs-disk"
 )
 
-// LoopDevice has the attributes of a virtual disk which is emulated for testing
-type LoopDevice struct {
-	// Size in bytes
-	Size int64 `json:"size"`
-	// The backing image name
-	// eg: fake123
-	ImageName string `json:"imageName"`
-	// Image directory
-	// eg: /tmp
-	ImgDir string `json:"imgDir"`
-	// the disk name
-	// eg: /tmp/loop9002
-	DiskPath string `json:"diskPath"`
-	// mount point if any
-	MountPoint string `json:"mountPoint"`
-}
-
-func (disk *LoopDevice) createDiskImage() error {
+func createDiskImage(disk *api.LoopDevice) error {
 	f, err := os.CreateTemp(disk.ImgDir, DiskImageNamePrefix+"-*.img")
 	if err != nil {
 		return fmt.Errorf("error creating disk image. Error : %v", err)
@@ -50,14 +34,14 @@ func (disk *LoopDevice) createDiskImage() error {
 
 // CreateLoopDevice creates a loop device if the disk is not present
 func CreateLoopDevice(w http.ResponseWriter, r *http.Request) {
-	var device LoopDevice
+	var device api.LoopDevice
 	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	klog.Info("creates a loop device, data: %v", device)
@@ -75,11 +59,11 @@ func CreateLoopDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := device.createDiskImage()
+	err := createDiskImage(&device)
 	if err != nil {
 		msg = fmt.Sprintf("failed to create disk image, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
@@ -90,7 +74,7 @@ func CreateLoopDevice(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("error creating loop device. Error : %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
 
@@ -99,24 +83,24 @@ func CreateLoopDevice(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to marshal the device struct to JSON Error : %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
-	WrapResult(string(jsonData), ErrNone, w)
+	WrapResult(string(jsonData), api.ErrNone, w)
 }
 
 // DeleteLoopDevice detaches the loop device from the backing
 // image. Also deletes the backing image and block device file in /dev
 func DeleteLoopDevice(w http.ResponseWriter, r *http.Request) {
-	var device LoopDevice
+	var device api.LoopDevice
 	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -141,17 +125,17 @@ func DeleteLoopDevice(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot detach loop device. Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
 	err = os.Remove(device.ImageName)
 	if err != nil {
 		msg = fmt.Sprintf("could not delete backing disk image. Error : %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 func formatDisk(diskPath string) error {
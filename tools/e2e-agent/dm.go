@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+var dmNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+
+// dmFlakeyFeatures returns the dm-flakey feature arguments for the params,
+// without features all IOs fail during the down interval
+func dmFlakeyFeatures(params api.DmFlakeyParams) ([]string, error) {
+	var features []string
+	if params.DropWrites && params.ErrorWrites {
+		return nil, fmt.Errorf("writes cannot be both dropped and failed")
+	}
+	if params.DropWrites {
+		features = append(features, "drop_writes")
+	}
+	if params.ErrorWrites && !params.ErrorReads {
+		features = append(features, "error_writes")
+	}
+	if params.ErrorReads && (params.DropWrites || !params.ErrorWrites) {
+		// error_reads requires linux 6.4 or later
+		features = append(features, "error_reads")
+	}
+	return features, nil
+}
+
+// dmTable returns the device mapper table of the device
+func dmTable(device api.DmFaultDevice, sectors string) (string, error) {
+	switch device.Target {
+	case api.DmTargetError:
+		return fmt.Sprintf("0 %s error", sectors), nil
+	case api.DmTargetFlakey:
+		if device.Flakey == nil {
+			return "", fmt.Errorf("no flakey parameters passed")
+		}
+		if device.Flakey.UpSeconds < 0 || device.Flakey.DownSeconds <= 0 {
+			return "", fmt.Errorf("invalid flakey intervals up %d down %d",
+				device.Flakey.UpSeconds, device.Flakey.DownSeconds)
+		}
+		features, err := dmFlakeyFeatures(*device.Flakey)
+		if err != nil {
+			return "", err
+		}
+		table := fmt.Sprintf("0 %s flakey %s 0 %d %d", sectors, device.BackingDevice,
+			device.Flakey.UpSeconds, device.Flakey.DownSeconds)
+		if len(features) != 0 {
+			table += fmt.Sprintf(" %d %s", len(features), strings.Join(features, " "))
+		}
+		return table, nil
+	case api.DmTargetDelay:
+		if device.Delay == nil {
+			return "", fmt.Errorf("no delay parameters passed")
+		}
+		if device.Delay.ReadDelayMs < 0 || device.Delay.WriteDelayMs < 0 {
+			return "", fmt.Errorf("delays must not be negative")
+		}
+		table := fmt.Sprintf("0 %s delay %s 0 %d", sectors, device.BackingDevice, device.Delay.ReadDelayMs)
+		if device.Delay.WriteDelayMs != device.Delay.ReadDelayMs {
+			table += fmt.Sprintf(" %s 0 %d", device.BackingDevice, device.Delay.WriteDelayMs)
+		}
+		return table, nil
+	case api.DmTargetDust:
+		if device.Dust == nil {
+			return "", fmt.Errorf("no dust parameters passed")
+		}
+		blockSize := device.Dust.BlockSize
+		if blockSize == 0 {
+			blockSize = 512
+		}
+		if blockSize < 512 || blockSize&(blockSize-1) != 0 {
+			return "", fmt.Errorf("invalid dust block size %d", device.Dust.BlockSize)
+		}
+		return fmt.Sprintf("0 %s dust %s 0 %d", sectors, device.BackingDevice, blockSize), nil
+	}
+	return "", fmt.Errorf("unsupported device mapper target %s", device.Target)
+}
+
+// dmDeviceTable validates the device and returns its device mapper table
+func dmDeviceTable(device api.DmFaultDevice) (string, error) {
+	if !dmNameRegexp.MatchString(device.Name) {
+		return "", fmt.Errorf("invalid device name %s", device.Name)
+	}
+	if device.BackingDevice == "" {
+		return "", fmt.Errorf("no backing device passed")
+	}
+	if _, err := os.Stat(device.BackingDevice); err != nil {
+		return "", err
+	}
+	sectors, err := bashLocal("blockdev --getsz " + device.BackingDevice)
+	if err != nil {
+		return "", err
+	}
+	return dmTable(device, sectors)
+}
+
+// updateDmDust sends the messages changing the bad blocks of a dust device
+func updateDmDust(update api.DmDustUpdate) error {
+	var messages []string
+	if update.ClearBadBlocks {
+		messages = append(messages, "clearbadblocks")
+	}
+	for _, block := range update.RemoveBadBlocks {
+		messages = append(messages, fmt.Sprintf("removebadblock %d", block))
+	}
+	for _, block := range update.AddBadBlocks {
+		messages = append(messages, fmt.Sprintf("addbadblock %d", block))
+	}
+	if update.Enabled != nil {
+		if *update.Enabled {
+			messages = append(messages, "enable")
+		} else {
+			messages = append(messages, "disable")
+		}
+	}
+	for _, message := range messages {
+		if _, err := bashLocal(fmt.Sprintf("dmsetup message %s 0 %s", update.Name, message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initDmDust adds the initial bad blocks of a dust device
+func initDmDust(device api.DmFaultDevice) error {
+	if device.Target != api.DmTargetDust {
+		return nil
+	}
+	return updateDmDust(api.DmDustUpdate{
+		Name:         device.Name,
+		AddBadBlocks: device.Dust.BadBlocks,
+		Enabled:      &device.Dust.Enabled,
+	})
+}
+
+// CreateDmFaultDevice wraps a device in a device mapper target which injects faults
+func CreateDmFaultDevice(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var device api.DmFaultDevice
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&device); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	table, err := dmDeviceTable(device)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("create device mapper fault device, data: ", device)
+	if _, err = bashLocal(fmt.Sprintf("dmsetup create %s --table '%s'", device.Name, table)); err != nil {
+		msg = fmt.Sprintf("failed to create device %s, Error: %s", device.Name, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	if err = initDmDust(device); err != nil {
+		msg = fmt.Sprintf("failed to add bad blocks to device %s, Error: %s", device.Name, err.Error())
+		klog.Error(msg)
+		if _, err = bashLocal("dmsetup remove " + device.Name); err != nil {
+			klog.Error("failed to remove device ", device.Name, " Error: ", err)
+		}
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	registerFault(api.FaultFaultyDevice, device.Name, "", device.LeaseSeconds)
+	WrapResponse(api.DmFaultDeviceResponse{DevicePath: "/dev/mapper/" + device.Name}, w)
+}
+
+// ReloadDmFaultDevice replaces the target of a device created by CreateDmFaultDevice,
+// the lease of the device is unchanged
+func ReloadDmFaultDevice(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var device api.DmFaultDevice
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&device); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	table, err := dmDeviceTable(device)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("reload device mapper fault device, data: ", device)
+	if _, err = bashLocal(fmt.Sprintf("dmsetup reload %s --table '%s' && dmsetup resume %s", device.Name, table, device.Name)); err != nil {
+		msg = fmt.Sprintf("failed to reload device %s, Error: %s", device.Name, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	// the bad blocks of the previous target are lost
+	if err = initDmDust(device); err != nil {
+		msg = fmt.Sprintf("failed to add bad blocks to device %s, Error: %s", device.Name, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResult("", api.ErrNone, w)
+}
+
+// RemoveDmFaultDevice removes a device created by CreateDmFaultDevice
+func RemoveDmFaultDevice(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var device api.DmFaultDevice
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&device); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	if !dmNameRegexp.MatchString(device.Name) {
+		msg = fmt.Sprintf("invalid device name %s", device.Name)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("remove device mapper fault device, data: ", device)
+	if _, err := bashLocal("dmsetup remove " + device.Name); err != nil {
+		msg = fmt.Sprintf("failed to remove device %s, Error: %s", device.Name, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	unregisterFault(api.FaultFaultyDevice, device.Name)
+	WrapResult("", api.ErrNone, w)
+}
+
+// UpdateDmDust adds or removes bad blocks of a dust device
+func UpdateDmDust(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var update api.DmDustUpdate
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&update); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	if !dmNameRegexp.MatchString(update.Name) {
+		msg = fmt.Sprintf("invalid device name %s", update.Name)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("update dust device, data: ", update)
+	if err := updateDmDust(update); err != nil {
+		msg = fmt.Sprintf("failed to update dust device %s, Error: %s", update.Name, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResult("", api.ErrNone, w)
+}
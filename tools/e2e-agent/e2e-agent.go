@@ -41,6 +41,11 @@ func Setup() error {
 			return err
 		}
 	}
+	if err = RevertPersistedFaults(); err != nil {
+		return err
+	}
+	// network emulation applied before a restart would otherwise persist
+	RemoveAllNetworkEmulation()
 	return nil
 }
 
@@ -5,41 +5,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
-type E2eAgentErrcode int
-
-const (
-	// general errors
-	ErrNone         E2eAgentErrcode = 0
-	ErrGeneral      E2eAgentErrcode = 1
-	ErrJsonDecode   E2eAgentErrcode = 2
-	ErrJsonEncode   E2eAgentErrcode = 3
-	ErrReadFail     E2eAgentErrcode = 4
-	ErrExecFailed   E2eAgentErrcode = 5
-	ErrFileNotExist E2eAgentErrcode = 6
-
-	// event errors
-	ErrConnectFail          E2eAgentErrcode = 101
-	ErrConnectedOther       E2eAgentErrcode = 102
-	ErrNotConnected         E2eAgentErrcode = 103
-	ErrSubscriptionNotFound E2eAgentErrcode = 104
-	ErrSubscribedAlready    E2eAgentErrcode = 105
-	ErrSubscribeFail        E2eAgentErrcode = 106
-	ErrStreamAddFail        E2eAgentErrcode = 107
-	ErrStreamCreateFail     E2eAgentErrcode = 108
-	ErrPublishFail          E2eAgentErrcode = 109
-	ErrUnSubscribeFail      E2eAgentErrcode = 110
-)
-
-type E2eAgentError struct {
-	Output    string          `json:"output"`
-	Errorcode E2eAgentErrcode `json:"errorcode"`
-}
-
-func WrapResult(output string, errorcode E2eAgentErrcode, w http.ResponseWriter) {
-	e2eagenterr := E2eAgentError{
+func WrapResult(output string, errorcode api.E2eAgentErrcode, w http.ResponseWriter) {
+	e2eagenterr := api.E2eAgentError{
 		Output:    output,
 		Errorcode: errorcode,
 	}
@@ -50,10 +21,22 @@ func WrapResult(output string, errorcode E2eAgentErrcode, w http.ResponseWriter)
 		w.WriteHeader(InternalServerErrorCode)
 		return
 	}
-	if errorcode != ErrNone {
+	if errorcode != api.ErrNone {
 		klog.Error("output: ", output, " errorcode: ", errorcode)
 	} else {
 		klog.Info("output: ", output, " errorcode: ", errorcode)
 	}
 	fmt.Fprint(w, string(jsn))
 }
+
+// WrapResponse wraps the JSON encoding of a typed api response
+func WrapResponse(response interface{}, w http.ResponseWriter) {
+	jsn, err := json.Marshal(response)
+	if err != nil {
+		msg := fmt.Sprintf("failed to marshal response, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonEncode, w)
+		return
+	}
+	WrapResult(string(jsn), api.ErrNone, w)
+}
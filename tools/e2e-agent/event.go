@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	nats "github.com/nats-io/nats.go"
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
@@ -33,21 +34,6 @@ type EventSubscription struct {
 	messages        []EventMessage
 }
 
-type EventSubscriptionRequest struct {
-	EventServerAddr string `json:"eventServerAddr"`
-	Subject         string `json:"subject"`
-}
-
-type EventPublishRequest struct {
-	EventServerAddr string `json:"eventServerAddr"`
-	Subject         string `json:"subject"`
-	Data            string `json:"data"`
-}
-
-type EventRequest struct {
-	Subject string `json:"subject"`
-}
-
 func init() {
 	g_eventSubscriptions = make(map[*nats.Subscription]EventSubscription)
 }
@@ -78,11 +64,11 @@ func messageHandler(msg *nats.Msg) {
 }
 
 func EventList(w http.ResponseWriter, r *http.Request) {
-	var event_req EventRequest
+	var event_req api.EventRequest
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&event_req); err != nil {
 		msg := fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	klog.Info("EventList: subject: ", event_req.Subject)
@@ -107,10 +93,10 @@ func EventList(w http.ResponseWriter, r *http.Request) {
 	jsn, err := json.Marshal(returnedmessages)
 	if err != nil {
 		msg := fmt.Sprintf("EventList: failed to marshal messages, Error: %s", err.Error())
-		WrapResult(msg, ErrJsonEncode, w)
+		WrapResult(msg, api.ErrJsonEncode, w)
 		return
 	}
-	WrapResult(string(jsn), ErrNone, w)
+	WrapResult(string(jsn), api.ErrNone, w)
 }
 
 func ensureConnection(eventServerAddr string) error {
@@ -158,11 +144,11 @@ func ensureStream() error {
 }
 
 func EventSubscribe(w http.ResponseWriter, r *http.Request) {
-	var subscription_req EventSubscriptionRequest
+	var subscription_req api.EventSubscriptionRequest
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&subscription_req); err != nil {
 		msg := fmt.Sprintf("EventSubscribe: failed to read JSON encoded data, Error: %s", err.Error())
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -173,13 +159,13 @@ func EventSubscribe(w http.ResponseWriter, r *http.Request) {
 	var err error
 	if err = ensureConnection(subscription_req.EventServerAddr); err != nil {
 		msg := fmt.Sprintf("EventSubscribe: failed to connect to %s", subscription_req.EventServerAddr)
-		WrapResult(msg, ErrConnectFail, w)
+		WrapResult(msg, api.ErrConnectFail, w)
 		return
 	}
 
 	if err = ensureStream(); err != nil {
 		msg := fmt.Sprintf("EventSubscribe: failed to create stream, error: %s", err.Error())
-		WrapResult(msg, ErrStreamCreateFail, w)
+		WrapResult(msg, api.ErrStreamCreateFail, w)
 		return
 	}
 
@@ -187,7 +173,7 @@ func EventSubscribe(w http.ResponseWriter, r *http.Request) {
 	for _, v := range g_eventSubscriptions {
 		if v.subject_pattern == subscription_req.Subject {
 			msg := fmt.Sprintf("EventSubscribe: already subscribed to this subject %s", v.subject_pattern)
-			WrapResult(msg, ErrSubscribedAlready, w)
+			WrapResult(msg, api.ErrSubscribedAlready, w)
 			return
 		}
 	}
@@ -196,24 +182,24 @@ func EventSubscribe(w http.ResponseWriter, r *http.Request) {
 	subscription, err := g_stream.Subscribe(subscription_req.Subject, messageHandler)
 	if err != nil {
 		msg := fmt.Sprintf("EventSubscribe: failed to subscribe, error: %s", err)
-		WrapResult(msg, ErrSubscribeFail, w)
+		WrapResult(msg, api.ErrSubscribeFail, w)
 	} else {
 		klog.Info("EventSubscribe: new subscription to ", subscription_req.Subject)
 		g_eventSubscriptions[subscription] = EventSubscription{
 			subject_pattern: subscription_req.Subject,
 			messages:        []EventMessage{},
 		}
-		WrapResult("", ErrNone, w)
+		WrapResult("", api.ErrNone, w)
 	}
 }
 
 func EventPublish(w http.ResponseWriter, r *http.Request) {
-	var publish_req EventPublishRequest
+	var publish_req api.EventPublishRequest
 	d := json.NewDecoder(r.Body)
 	var err error
 	if err = d.Decode(&publish_req); err != nil {
 		msg := fmt.Sprintf("EventPublish: failed to read JSON encoded data, Error: %s", err.Error())
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -223,31 +209,31 @@ func EventPublish(w http.ResponseWriter, r *http.Request) {
 
 	if err = ensureConnection(publish_req.EventServerAddr); err != nil {
 		msg := fmt.Sprintf("EventPublish: failed to connect to %s", publish_req.EventServerAddr)
-		WrapResult(msg, ErrConnectFail, w)
+		WrapResult(msg, api.ErrConnectFail, w)
 		return
 	}
 
 	if err = ensureStream(); err != nil {
 		msg := fmt.Sprintf("EventPublish: failed to create stream, error: %s", err.Error())
-		WrapResult(msg, ErrStreamCreateFail, w)
+		WrapResult(msg, api.ErrStreamCreateFail, w)
 		return
 	}
 
 	_, err = g_stream.Publish(publish_req.Subject, []byte(publish_req.Data))
 	if err != nil {
 		msg := fmt.Sprintf("EventPublish: failed to publish to nats service, error: %s", err.Error())
-		WrapResult(msg, ErrPublishFail, w)
+		WrapResult(msg, api.ErrPublishFail, w)
 	} else {
-		WrapResult("", ErrNone, w)
+		WrapResult("", api.ErrNone, w)
 	}
 }
 
 func EventUnsubscribe(w http.ResponseWriter, r *http.Request) {
-	var event_req EventRequest
+	var event_req api.EventRequest
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&event_req); err != nil {
 		msg := fmt.Sprintf("EventSubscribe: failed to read JSON encoded data, Error: %s", err.Error())
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -262,16 +248,16 @@ func EventUnsubscribe(w http.ResponseWriter, r *http.Request) {
 			err := k.Unsubscribe()
 			delete(g_eventSubscriptions, k)
 			if err != nil {
-				WrapResult(fmt.Sprintf("EventUnsubscribe: unsubscribe failed %v", err), ErrUnSubscribeFail, w)
+				WrapResult(fmt.Sprintf("EventUnsubscribe: unsubscribe failed %v", err), api.ErrUnSubscribeFail, w)
 			} else {
-				WrapResult("", ErrNone, w)
+				WrapResult("", api.ErrNone, w)
 			}
 			return
 		}
 	}
 
 	msg := fmt.Sprintf("EventUnsubscribe: subscription %s not found", event_req.Subject)
-	WrapResult(msg, ErrSubscriptionNotFound, w)
+	WrapResult(msg, api.ErrSubscriptionNotFound, w)
 }
 
 func EventUnsubscribeAll(w http.ResponseWriter, r *http.Request) {
@@ -297,11 +283,11 @@ func EventUnsubscribeAll(w http.ResponseWriter, r *http.Request) {
 		g_eventServer = ""
 		g_stream = nil
 		if err != nil {
-			WrapResult(fmt.Sprintf("EventUnsubscribeAll: unsubscribe failed %v", err), ErrUnSubscribeFail, w)
+			WrapResult(fmt.Sprintf("EventUnsubscribeAll: unsubscribe failed %v", err), api.ErrUnSubscribeFail, w)
 		} else {
-			WrapResult("", ErrNone, w)
+			WrapResult("", api.ErrNone, w)
 		}
 	} else {
-		WrapResult("EventUnsubscribeAll: was not connected", ErrNotConnected, w)
+		WrapResult("EventUnsubscribeAll: was not connected", api.ErrNotConnected, w)
 	}
 }
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+// faults are persisted on the host so that they can be reverted
+// by the next instance of the agent if this one is restarted
+const faultsFile = "/host/var/tmp/e2e-agent-faults.json"
+
+type activeFault struct {
+	api.Fault
+	timer *time.Timer
+}
+
+var (
+	faultsMutex sync.Mutex
+	faults      = make(map[string]*activeFault)
+)
+
+// faultId identifies a fault by what it affects, injecting the same
+// fault twice replaces the lease of the first one
+func faultId(kind api.FaultKind, target string) string {
+	return string(kind) + ":" + target
+}
+
+// registerFault records a fault injected by the agent,
+// the fault is reverted when the lease expires if leaseSeconds is not 0
+func registerFault(kind api.FaultKind, target string, networkInterface string, leaseSeconds int) {
+	addFault(api.Fault{
+		Kind:             kind,
+		Target:           target,
+		NetworkInterface: networkInterface,
+	}, leaseSeconds)
+}
+
+// registerRestorableFault records a fault which is reverted by writing
+// back the previous content of the files it changed
+func registerRestorableFault(kind api.FaultKind, target string, restore map[string]string, leaseSeconds int) {
+	addFault(api.Fault{
+		Kind:    kind,
+		Target:  target,
+		Restore: restore,
+	}, leaseSeconds)
+}
+
+// restoreOfFault returns the previous content of the files changed by a fault,
+// nil if the fault is not registered
+func restoreOfFault(kind api.FaultKind, target string) map[string]string {
+	faultsMutex.Lock()
+	defer faultsMutex.Unlock()
+
+	if fault, ok := faults[faultId(kind, target)]; ok {
+		return fault.Restore
+	}
+	return nil
+}
+
+func addFault(f api.Fault, leaseSeconds int) {
+	faultsMutex.Lock()
+	defer faultsMutex.Unlock()
+
+	id := faultId(f.Kind, f.Target)
+	if fault, ok := faults[id]; ok && fault.timer != nil {
+		fault.timer.Stop()
+	}
+	f.Id = id
+	f.Created = time.Now()
+	fault := &activeFault{Fault: f}
+	if leaseSeconds > 0 {
+		lease := time.Duration(leaseSeconds) * time.Second
+		fault.Expires = fault.Created.Add(lease)
+		fault.timer = time.AfterFunc(lease, func() { expireFault(fault) })
+	}
+	faults[id] = fault
+	klog.Info("registered fault ", id, " lease: ", leaseSeconds, "s")
+	saveFaults()
+}
+
+// unregisterFault forgets a fault which has been reverted by a request
+func unregisterFault(kind api.FaultKind, target string) {
+	faultsMutex.Lock()
+	defer faultsMutex.Unlock()
+
+	id := faultId(kind, target)
+	fault, ok := faults[id]
+	if !ok {
+		return
+	}
+	if fault.timer != nil {
+		fault.timer.Stop()
+	}
+	delete(faults, id)
+	klog.Info("unregistered fault ", id)
+	saveFaults()
+}
+
+// expireFault reverts a fault whose lease has expired, a fault which
+// fails to revert is kept so that it is reported as leaked.
+// The fault is reverted without holding faultsMutex, reverting may take a while.
+func expireFault(fault *activeFault) {
+	faultsMutex.Lock()
+	if faults[fault.Id] != fault {
+		// reverted or injected again since the timer was started
+		faultsMutex.Unlock()
+		return
+	}
+	delete(faults, fault.Id)
+	saveFaults()
+	faultsMutex.Unlock()
+
+	klog.Info("lease expired for fault ", fault.Id)
+	if err := revertFault(fault.Fault); err != nil {
+		klog.Error("failed to revert fault ", fault.Id, " Error: ", err)
+		keepFault(fault)
+	}
+}
+
+// keepFault records again a fault which failed to revert, unless the
+// fault has been injected again while it was being reverted
+func keepFault(fault *activeFault) {
+	faultsMutex.Lock()
+	defer faultsMutex.Unlock()
+
+	if _, ok := faults[fault.Id]; ok {
+		return
+	}
+	fault.timer = nil
+	faults[fault.Id] = fault
+	saveFaults()
+}
+
+// revertFault undoes a fault injected by the agent
+func revertFault(fault api.Fault) error {
+	var err error
+	switch fault.Kind {
+	case api.FaultDropConnection:
+		err = AcceptConnectionsFromNodes([]string{fault.Target}, fault.NetworkInterface)
+	case api.FaultFaultyDevice:
+		_, err = bashLocal("dmsetup remove " + fault.Target)
+	case api.FaultDeviceOffline:
+		_, err = bashLocal("echo running > /host/sys/block/" + fault.Target + "/device/state")
+	case api.FaultNetworkInterfaceDown:
+		_, err = bashLocal(fmt.Sprintf("ifconfig %s up", fault.Target))
+	case api.FaultNetworkEmulation:
+		err = removeNetworkEmulation(fault.Target)
+	case api.FaultProcessPaused:
+		err = resumeProcesses(fault.Target)
+	case api.FaultCgroupPressure:
+		err = restoreFiles(fault.Restore)
+	default:
+		err = fmt.Errorf("unknown fault kind %s", fault.Kind)
+	}
+	return err
+}
+
+// sortedFaults returns the faults ordered by creation time, faultsMutex must be held
+func sortedFaults() []api.Fault {
+	list := make([]api.Fault, 0, len(faults))
+	for _, fault := range faults {
+		list = append(list, fault.Fault)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created.Before(list[j].Created)
+	})
+	return list
+}
+
+// saveFaults persists the faults, faultsMutex must be held
+func saveFaults() {
+	jsn, err := json.Marshal(sortedFaults())
+	if err != nil {
+		klog.Error("failed to marshal faults, Error: ", err)
+		return
+	}
+	if err = os.WriteFile(faultsFile, jsn, 0644); err != nil {
+		klog.Error("failed to save faults to ", faultsFile, " Error: ", err)
+	}
+}
+
+// RevertPersistedFaults reverts the faults left behind by a previous
+// instance of the agent, the leases no longer apply as the agent that
+// would have enforced them is gone
+func RevertPersistedFaults() error {
+	jsn, err := os.ReadFile(faultsFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var persisted []api.Fault
+	if err = json.Unmarshal(jsn, &persisted); err != nil {
+		klog.Error("failed to unmarshal faults from ", faultsFile, " Error: ", err)
+	}
+	for _, fault := range persisted {
+		klog.Info("reverting fault ", fault.Id)
+		// faults do not survive a reboot of the node, so failures are expected
+		if err = revertFault(fault); err != nil {
+			klog.Warning("failed to revert fault ", fault.Id, " Error: ", err)
+		}
+	}
+	return os.Remove(faultsFile)
+}
+
+// ListFaults returns the faults injected by the agent which have not been reverted
+func ListFaults(w http.ResponseWriter, r *http.Request) {
+	faultsMutex.Lock()
+	list := sortedFaults()
+	faultsMutex.Unlock()
+	WrapResponse(list, w)
+}
+
+// ClearFaults reverts all faults injected by the agent and returns them,
+// the faults are reverted without holding faultsMutex
+func ClearFaults(w http.ResponseWriter, r *http.Request) {
+	var failures []string
+	faultsMutex.Lock()
+	list := sortedFaults()
+	active := make([]*activeFault, 0, len(list))
+	for _, f := range list {
+		fault := faults[f.Id]
+		if fault.timer != nil {
+			fault.timer.Stop()
+		}
+		delete(faults, f.Id)
+		active = append(active, fault)
+	}
+	saveFaults()
+	faultsMutex.Unlock()
+
+	cleared := make([]api.Fault, 0, len(active))
+	for _, fault := range active {
+		if err := revertFault(fault.Fault); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", fault.Id, err.Error()))
+			keepFault(fault)
+			continue
+		}
+		cleared = append(cleared, fault.Fault)
+	}
+
+	if len(failures) != 0 {
+		msg := fmt.Sprintf("failed to revert faults, Error: %s", strings.Join(failures, ", "))
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResponse(cleared, w)
+}
@@ -5,6 +5,7 @@ go 1.19
 require (
 	github.com/gorilla/mux v1.8.0
 	github.com/nats-io/nats.go v1.28.0
+	github.com/openebs/openebs-e2e/tools/e2e-agent/api v0.0.0
 	k8s.io/klog/v2 v2.80.1
 )
 
@@ -19,3 +20,5 @@ require (
 	golang.org/x/sys v0.15.0 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
 )
+
+replace github.com/openebs/openebs-e2e/tools/e2e-agent/api v0.0.0 => ./api
@@ -5,19 +5,20 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
 // CreateHostPathDisk creates hostpath disk
 func CreateHostPathDisk(w http.ResponseWriter, r *http.Request) {
-	var device LoopDevice
+	var device api.LoopDevice
 	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	klog.Info("creates a hostpath device, data: %v", device)
@@ -40,7 +41,7 @@ func CreateHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to erase disk %s, Error: %s", device.DiskPath, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
@@ -49,7 +50,7 @@ func CreateHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to format disk partition %s with ext4, Error: %s", device.DiskPath, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
@@ -58,7 +59,7 @@ func CreateHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to create directory %s, Error: %s", device.MountPoint, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
@@ -67,22 +68,22 @@ func CreateHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to mount partition %s to %s, Error: %s", device.MountPoint, device.DiskPath, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
-	WrapResult(msg, ErrNone, w)
+	WrapResult(msg, api.ErrNone, w)
 }
 
 // RemoveHostPathDisk removes hostpath disk
 func RemoveHostPathDisk(w http.ResponseWriter, r *http.Request) {
-	var device LoopDevice
+	var device api.LoopDevice
 	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	klog.Info("remove a hostpath device, data: %v", device)
@@ -105,7 +106,7 @@ func RemoveHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to unmount partition %s, Error: %s", device.MountPoint, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
@@ -114,7 +115,7 @@ func RemoveHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to erase disk %s, Error: %s", device.DiskPath, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
 
@@ -123,8 +124,8 @@ func RemoveHostPathDisk(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to remove directory %s, Error: %s", device.MountPoint, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(msg, api.ErrGeneral, w)
 		return
 	}
-	WrapResult(msg, ErrNone, w)
+	WrapResult(msg, api.ErrNone, w)
 }
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+const (
+	jobOutputLimit = 64 * 1024
+	// finished jobs are forgotten after jobRetention
+	jobRetention = time.Hour
+)
+
+type job struct {
+	mutex     sync.Mutex
+	status    api.Job
+	stdout    jobStream
+	stderr    jobStream
+	cmd       *exec.Cmd
+	cancelled bool
+}
+
+// jobStream is the stdout or stderr of a job, keeping its last jobOutputLimit bytes
+type jobStream struct {
+	j    *job
+	data []byte
+}
+
+var (
+	jobsMutex sync.Mutex
+	jobs      = make(map[string]*job)
+	jobCount  int
+	// job ids of different instances of the agent do not collide
+	jobIdPrefix = fmt.Sprintf("%x", time.Now().Unix())
+)
+
+// Write appends to the stream, keeping the last jobOutputLimit bytes
+func (s *jobStream) Write(p []byte) (int, error) {
+	s.j.mutex.Lock()
+	defer s.j.mutex.Unlock()
+	s.data = append(s.data, p...)
+	if len(s.data) > jobOutputLimit {
+		s.data = s.data[len(s.data)-jobOutputLimit:]
+	}
+	return len(p), nil
+}
+
+// snapshot returns the status of the job
+func (j *job) snapshot() api.Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	status := j.status
+	status.Output = string(j.stdout.data)
+	status.Stderr = string(j.stderr.data)
+	// the job commands report their progress on stderr,
+	// dd and similar tools overwrite their progress line with \r
+	lines := strings.FieldsFunc(status.Stderr, func(r rune) bool { return r == '\n' || r == '\r' })
+	if len(lines) != 0 {
+		status.Progress = strings.TrimSpace(lines[len(lines)-1])
+	}
+	return status
+}
+
+func checksumDeviceCommand(device api.Device) string {
+	return fmt.Sprintf("cksum %s", device.DevicePath)
+}
+
+// checksumDeviceJobCommand is checksumDeviceCommand reading the device with dd to report
+// the bytes read so far, the output has the same <checksum> <size> <device> format
+func checksumDeviceJobCommand(device api.Device) string {
+	return fmt.Sprintf("set -o pipefail && dd if=%s bs=1M status=progress | cksum | sed 's|$| %s|'",
+		device.DevicePath, device.DevicePath)
+}
+
+func zeroingDiskCommand(disk api.Disk) string {
+	return fmt.Sprintf("dd if=/dev/zero of=/host%s count=1 oflag=direct status=progress %s %s && sync",
+		disk.Device,
+		disk.SeekParam,
+		disk.BlockSizeParam,
+	)
+}
+
+func blkDiscardCommand(data api.BlkDiscard) string {
+	return fmt.Sprintf("blkdiscard %s %s", data.Device, data.Options)
+}
+
+// blkDiscardJobCommand is blkDiscardCommand discarding the device in steps,
+// reporting each step discarded on stderr
+func blkDiscardJobCommand(data api.BlkDiscard) string {
+	return fmt.Sprintf("blkdiscard --verbose --step 1G %s %s >&2", data.Device, data.Options)
+}
+
+// xfsCheckDeviceCommand mounts and unmounts the device to replay the log
+// before checking it, as XFSCheckDevice does
+func xfsCheckDeviceCommand(device api.Device) string {
+	// mounting replays the log, the directory is only removed once it is
+	// no longer a mount point so the content of the filesystem is never deleted
+	return fmt.Sprintf("dir=$(mktemp -d) && { if mount %s $dir -t xfs; then umount $dir && rmdir $dir; else rmdir $dir; fi; xfs_repair -n %s; }",
+		device.DevicePath, device.DevicePath)
+}
+
+// jobCommand validates the job request and returns the command it runs
+func jobCommand(req api.JobRequest) (string, error) {
+	switch req.Operation {
+	case api.JobChecksumDevice:
+		if req.Device == nil || req.Device.DevicePath == "" {
+			return "", fmt.Errorf("no device path passed")
+		}
+		return checksumDeviceJobCommand(*req.Device), nil
+	case api.JobXFSCheckDevice:
+		if req.Device == nil || req.Device.DevicePath == "" {
+			return "", fmt.Errorf("no device path passed")
+		}
+		if req.Device.FsType != "xfs" {
+			return "", fmt.Errorf("not a supported filesystem for fscheck %s", req.Device.FsType)
+		}
+		return xfsCheckDeviceCommand(*req.Device), nil
+	case api.JobZeroingDisk:
+		if req.Disk == nil || req.Disk.Device == "" || req.Disk.SeekParam == "" || req.Disk.BlockSizeParam == "" {
+			return "", fmt.Errorf("no device or seek or block param passed")
+		}
+		return zeroingDiskCommand(*req.Disk), nil
+	case api.JobBlkDiscard:
+		if req.BlkDiscard == nil || req.BlkDiscard.Device == "" {
+			return "", fmt.Errorf("no device param passed")
+		}
+		return blkDiscardJobCommand(*req.BlkDiscard), nil
+	}
+	return "", fmt.Errorf("unsupported job operation %s", req.Operation)
+}
+
+// pruneJobs forgets the jobs which finished more than jobRetention ago, jobsMutex must be held
+func pruneJobs() {
+	for id, j := range jobs {
+		status := j.snapshot()
+		if status.Done() && time.Since(status.Finished) > jobRetention {
+			delete(jobs, id)
+		}
+	}
+}
+
+// runJob waits for the command of the job to exit and records its status
+func runJob(j *job) {
+	err := j.cmd.Wait()
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.status.Finished = time.Now()
+	j.status.ExitCode = j.cmd.ProcessState.ExitCode()
+	var exitErr *exec.ExitError
+	switch {
+	case j.cancelled:
+		j.status.State = api.JobCancelled
+	case err == nil:
+		j.status.State = api.JobSucceeded
+	default:
+		j.status.State = api.JobFailed
+		if !errors.As(err, &exitErr) {
+			j.stderr.data = append(j.stderr.data, []byte(err.Error())...)
+		}
+	}
+	klog.Info("job ", j.status.Id, " ", j.status.State, " exit code ", j.status.ExitCode)
+}
+
+// StartJob starts a long running operation and returns the job id
+func StartJob(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var req api.JobRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	command, err := jobCommand(req)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	pruneJobs()
+	jobCount++
+	j := &job{
+		status: api.Job{
+			Id:        fmt.Sprintf("%s-%d", jobIdPrefix, jobCount),
+			Operation: req.Operation,
+			State:     api.JobRunning,
+			Started:   time.Now(),
+		},
+	}
+	j.stdout.j = j
+	j.stderr.j = j
+	j.cmd = exec.Command("bash", "-c", command)
+	j.cmd.Stdout = &j.stdout
+	j.cmd.Stderr = &j.stderr
+	// run the command in its own process group so that cancelling kills its children too
+	j.cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	klog.Info("starting job ", j.status.Id, " command ", command)
+	if err = j.cmd.Start(); err != nil {
+		msg = fmt.Sprintf("failed to start job, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	jobs[j.status.Id] = j
+	go runJob(j)
+	WrapResponse(api.JobResponse{Id: j.status.Id}, w)
+}
+
+// lookupJob returns the job named by the request path
+func lookupJob(r *http.Request) (*job, string) {
+	id := mux.Vars(r)["id"]
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	return jobs[id], id
+}
+
+// GetJob returns the status of a job
+func GetJob(w http.ResponseWriter, r *http.Request) {
+	j, id := lookupJob(r)
+	if j == nil {
+		msg := fmt.Sprintf("job %s not found", id)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrFileNotExist, w)
+		return
+	}
+	WrapResponse(j.snapshot(), w)
+}
+
+// CancelJob kills the command of a running job, cancelling a finished job has no effect
+func CancelJob(w http.ResponseWriter, r *http.Request) {
+	j, id := lookupJob(r)
+	if j == nil {
+		msg := fmt.Sprintf("job %s not found", id)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrFileNotExist, w)
+		return
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.status.State == api.JobRunning && !j.cancelled {
+		klog.Info("cancelling job ", id)
+		j.cancelled = true
+		if err := syscall.Kill(-j.cmd.Process.Pid, syscall.SIGKILL); err != nil {
+			msg := fmt.Sprintf("failed to cancel job %s, Error: %s", id, err.Error())
+			klog.Error(msg)
+			WrapResult(msg, api.ErrExecFailed, w)
+			return
+		}
+	}
+	WrapResult("", api.ErrNone, w)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+// dmesg --time-format iso prints the timestamp with a comma before the microseconds
+var dmesgTimeLayouts = []string{
+	"2006-01-02T15:04:05,000000-07:00",
+	"2006-01-02T15:04:05,000000-0700",
+}
+
+// logParser converts a line printed by the log command to a LogLine,
+// returning false if the line should be skipped
+type logParser func(text string, last time.Time) (api.LogLine, bool)
+
+// parseDmesgLine parses a line printed by dmesg --time-format iso,
+// lines without a timestamp are continuations of the previous line
+func parseDmesgLine(text string, last time.Time) (api.LogLine, bool) {
+	line := api.LogLine{Time: last, Source: api.LogKernel, Text: text}
+	fields := strings.SplitN(text, " ", 2)
+	for _, layout := range dmesgTimeLayouts {
+		if t, err := time.Parse(layout, fields[0]); err == nil {
+			line.Time = t
+			if len(fields) == 2 {
+				line.Text = fields[1]
+			} else {
+				line.Text = ""
+			}
+			break
+		}
+	}
+	return line, true
+}
+
+// journalEntry holds the fields of journalctl -o json used by the agent,
+// MESSAGE is not a string if it is not valid UTF-8
+type journalEntry struct {
+	RealtimeTimestamp string      `json:"__REALTIME_TIMESTAMP"`
+	Identifier        string      `json:"SYSLOG_IDENTIFIER"`
+	Message           interface{} `json:"MESSAGE"`
+}
+
+// parseJournalLine parses a line printed by journalctl -o json
+func parseJournalLine(text string, last time.Time) (api.LogLine, bool) {
+	var entry journalEntry
+	if err := json.Unmarshal([]byte(text), &entry); err != nil {
+		klog.Warning("failed to unmarshal journal entry, Error: ", err)
+		return api.LogLine{}, false
+	}
+	message, ok := entry.Message.(string)
+	if !ok {
+		return api.LogLine{}, false
+	}
+	line := api.LogLine{Time: last, Source: api.LogJournal, Text: message}
+	if usecs, err := strconv.ParseInt(entry.RealtimeTimestamp, 10, 64); err == nil {
+		line.Time = time.UnixMicro(usecs)
+	}
+	if entry.Identifier != "" {
+		line.Text = entry.Identifier + ": " + message
+	}
+	return line, true
+}
+
+// FollowLog streams the lines of a log of the node until the client closes the connection
+func FollowLog(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var req api.LogFollowRequest
+	var filter *regexp.Regexp
+	var args []string
+	var parse logParser
+
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		w.WriteHeader(UnprocessableEntityErrorCode)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	since := req.Since
+	if since.IsZero() {
+		since = time.Now()
+	}
+	switch req.Source {
+	case api.LogKernel:
+		args = []string{"dmesg", "--follow", "--time-format", "iso"}
+		parse = parseDmesgLine
+	case api.LogJournal:
+		args = []string{"journalctl", "--root", "/host", "--follow", "--output", "json",
+			"--since", fmt.Sprintf("@%d", since.Unix())}
+		parse = parseJournalLine
+	default:
+		msg = fmt.Sprintf("unsupported log source %s", req.Source)
+	}
+	if req.Filter != "" {
+		var err error
+		if filter, err = regexp.Compile(req.Filter); err != nil {
+			msg = fmt.Sprintf("invalid filter, Error: %s", err.Error())
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		msg = "streaming is not supported"
+	}
+	if msg != "" {
+		klog.Error(msg)
+		w.WriteHeader(UnprocessableEntityErrorCode)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+
+	klog.Info("follow log, data: ", req)
+	// the command is killed when the client closes the connection
+	cmd := exec.CommandContext(r.Context(), args[0], args[1:]...)
+	stdout, err := cmd.StdoutPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		msg = fmt.Sprintf("failed to follow log, Error: %s", err.Error())
+		klog.Error(msg)
+		w.WriteHeader(InternalServerErrorCode)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var last time.Time
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		line, ok := parse(scanner.Text(), last)
+		if !ok {
+			continue
+		}
+		last = line.Time
+		if line.Time.Before(since) || (filter != nil && !filter.MatchString(line.Text)) {
+			continue
+		}
+		if err = encoder.Encode(line); err != nil {
+			break
+		}
+		flusher.Flush()
+	}
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	klog.Info("stopped following log, data: ", req)
+}
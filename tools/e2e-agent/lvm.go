@@ -5,17 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
-type Lvm struct {
-	Pv                          string `json:"pv"`                          // Physical volume
-	Vg                          string `json:"vg"`                          // Volume group
-	ThinPoolAutoExtendThreshold int    `json:"thinPoolAutoExtendThreshold"` // thin pool auto extend threshold
-	ThinPoolAutoExtendPercent   int    `json:"thinPoolAutoExtendPercent"`   // thin pool auto extend percent
-}
-
 // LvmVersion check lvm version installed on node
 func LvmVersion(w http.ResponseWriter, r *http.Request) {
 	var msg string
@@ -26,10 +21,36 @@ func LvmVersion(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot get lvm version. Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
+}
+
+// lvmReport is the json report of lvm vgs and lvm pvs run with --units b --nosuffix,
+// every value is reported as a string
+type lvmReport struct {
+	Report []struct {
+		Vg []map[string]string `json:"vg"`
+		Pv []map[string]string `json:"pv"`
+	} `json:"report"`
+}
+
+// lvmReportEntries returns the entries of the lvm report of kind, vg or pv
+func lvmReportEntries(output string, kind string) ([]map[string]string, error) {
+	var report lvmReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		return nil, err
+	}
+	var entries []map[string]string
+	for _, r := range report.Report {
+		if kind == "vg" {
+			entries = append(entries, r.Vg...)
+		} else {
+			entries = append(entries, r.Pv...)
+		}
+	}
+	return entries, nil
 }
 
 // LvmListVg list lvm vg
@@ -37,15 +58,37 @@ func LvmListVg(w http.ResponseWriter, r *http.Request) {
 	var msg string
 	klog.Info("List lvm vgs")
 
-	lvmListCommand := "lvm vgs --reportformat json"
+	lvmListCommand := "lvm vgs --reportformat json --units b --nosuffix"
 	output, err := bashLocal(lvmListCommand)
 	if err != nil {
 		msg = fmt.Sprintf("cannot list lvm vgs Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	entries, err := lvmReportEntries(output, "vg")
+	if err != nil {
+		msg = fmt.Sprintf("failed to unmarshal lvm vgs %s, Error: %s", output, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	vgs := []api.LvmVg{}
+	for _, entry := range entries {
+		pvCount, _ := strconv.Atoi(entry["pv_count"])
+		lvCount, _ := strconv.Atoi(entry["lv_count"])
+		size, _ := strconv.ParseUint(entry["vg_size"], 10, 64)
+		free, _ := strconv.ParseUint(entry["vg_free"], 10, 64)
+		vgs = append(vgs, api.LvmVg{
+			Name:    entry["vg_name"],
+			PvCount: pvCount,
+			LvCount: lvCount,
+			Attr:    entry["vg_attr"],
+			Size:    size,
+			Free:    free,
+		})
+	}
+	WrapResponse(vgs, w)
 }
 
 // LvmListPv list lvm pv
@@ -53,26 +96,45 @@ func LvmListPv(w http.ResponseWriter, r *http.Request) {
 	var msg string
 	klog.Info("List lvm pvs")
 
-	lvmListCommand := "lvm pvs --reportformat json"
+	lvmListCommand := "lvm pvs --reportformat json --units b --nosuffix"
 	output, err := bashLocal(lvmListCommand)
 	if err != nil {
 		msg = fmt.Sprintf("cannot list lvm pvs Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	entries, err := lvmReportEntries(output, "pv")
+	if err != nil {
+		msg = fmt.Sprintf("failed to unmarshal lvm pvs %s, Error: %s", output, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	pvs := []api.LvmPv{}
+	for _, entry := range entries {
+		size, _ := strconv.ParseUint(entry["pv_size"], 10, 64)
+		free, _ := strconv.ParseUint(entry["pv_free"], 10, 64)
+		pvs = append(pvs, api.LvmPv{
+			Name:   entry["pv_name"],
+			VgName: entry["vg_name"],
+			Attr:   entry["pv_attr"],
+			Size:   size,
+			Free:   free,
+		})
+	}
+	WrapResponse(pvs, w)
 }
 
 // LvmCreatePv create lvm pv
 func LvmCreatePv(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if lvm.Pv == "" {
@@ -88,21 +150,21 @@ func LvmCreatePv(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot create lvm pv Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // LvmCreateVg create lvm vg
 func LvmCreateVg(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if lvm.Pv == "" {
@@ -124,21 +186,21 @@ func LvmCreateVg(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot create lvm vg Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // LvmRemovePv remove lvm pv
 func LvmRemovePv(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if lvm.Pv == "" {
@@ -154,21 +216,21 @@ func LvmRemovePv(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot remove lvm pv Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // LvmRemoveVg remove lvm vg
 func LvmRemoveVg(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if lvm.Vg == "" {
@@ -184,21 +246,21 @@ func LvmRemoveVg(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot create lvm vg Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // LvmThinPoolAutoExtendThreshold update lvm.conf thin pool auto extend threshold value
 func LvmThinPoolAutoExtendThreshold(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if lvm.ThinPoolAutoExtendThreshold <= 0 {
@@ -211,13 +273,13 @@ func LvmThinPoolAutoExtendThreshold(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("lvm conf file verification error, error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrFileNotExist, w)
+		WrapResult(msg, api.ErrFileNotExist, w)
 		return
 	}
 	if lvmConfFile == "" {
 		msg := "lvm conf file not found"
 		klog.Error(msg)
-		WrapResult(msg, ErrFileNotExist, w)
+		WrapResult(msg, api.ErrFileNotExist, w)
 		return
 	}
 	klog.Info("update %s  thin pool auto extend threshold value, data: %v", lvmConfFile, lvm)
@@ -229,21 +291,21 @@ func LvmThinPoolAutoExtendThreshold(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("update %s thin pool auto extend threshold value, Error %s", lvmConfFile, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // LvmThinPoolAutoExtendPercent update lvm.conf thin pool auto extend percent value
 func LvmThinPoolAutoExtendPercent(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if lvm.ThinPoolAutoExtendPercent <= 0 {
@@ -256,13 +318,13 @@ func LvmThinPoolAutoExtendPercent(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("lvm conf file verification error, error: %v", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrFileNotExist, w)
+		WrapResult(msg, api.ErrFileNotExist, w)
 		return
 	}
 	if lvmConfFile == "" {
 		msg := "lvm conf file not found"
 		klog.Error(msg)
-		WrapResult(msg, ErrFileNotExist, w)
+		WrapResult(msg, api.ErrFileNotExist, w)
 		return
 	}
 	klog.Info("update %s  thin pool auto extend threshold value, data: %v", lvmConfFile, lvm)
@@ -274,10 +336,10 @@ func LvmThinPoolAutoExtendPercent(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("update %s thin pool auto extend percent value, Error %s", lvmConfFile, err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // If it's Github action based kind cluster, lvm conf file will be
@@ -319,12 +381,12 @@ func isFilePresent(file string) (bool, error) {
 // LvmLvChangeMonitor monitor lvm lv
 func LvmLvChangeMonitor(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -341,21 +403,21 @@ func LvmLvChangeMonitor(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot monitor lvm lv, Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
 
 // LvmLvRemoveThinPool lv thin pool
 func LvmLvRemoveThinPool(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var lvm Lvm
+	var lvm api.Lvm
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&lvm); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -372,8 +434,8 @@ func LvmLvRemoveThinPool(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot remove lvm thin pool lv, Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(output, ErrNone, w)
+	WrapResult(output, api.ErrNone, w)
 }
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// handle of the prio qdisc installed by the agent, used to identify it on restart
+	netemRootHandle = "1e2e:"
+	// the prio qdisc has 4 bands, the default priomap only uses the first 3,
+	// so only traffic to the peers, classified by the filters, reaches the 4th band
+	netemClass  = "1e2e:4"
+	netemHandle = "1e2f:"
+)
+
+var tcRateRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([kmgt]?(bit|bps)|[KMGT]?bit|[KMGT]?bps)?$`)
+
+// netemArgs returns the tc netem parameters for the emulation
+func netemArgs(emulation api.NetworkEmulation) (string, error) {
+	var args []string
+	if emulation.DelayMs < 0 || emulation.JitterMs < 0 {
+		return "", fmt.Errorf("delay and jitter must not be negative")
+	}
+	if emulation.JitterMs != 0 && emulation.DelayMs == 0 {
+		return "", fmt.Errorf("jitter requires a delay")
+	}
+	if emulation.ReorderPercent != 0 && emulation.DelayMs == 0 {
+		return "", fmt.Errorf("reordering requires a delay")
+	}
+	if emulation.DelayMs != 0 {
+		args = append(args, fmt.Sprintf("delay %dms", emulation.DelayMs))
+		if emulation.JitterMs != 0 {
+			args = append(args, fmt.Sprintf("%dms", emulation.JitterMs))
+		}
+	}
+	for _, pc := range []struct {
+		name    string
+		percent float64
+	}{
+		{"loss", emulation.LossPercent},
+		{"duplicate", emulation.DuplicatePercent},
+		{"reorder", emulation.ReorderPercent},
+	} {
+		if pc.percent < 0 || pc.percent > 100 {
+			return "", fmt.Errorf("%s percentage %v is out of range", pc.name, pc.percent)
+		}
+		if pc.percent != 0 {
+			args = append(args, fmt.Sprintf("%s %g%%", pc.name, pc.percent))
+		}
+	}
+	if emulation.Rate != "" {
+		if !tcRateRegexp.MatchString(emulation.Rate) {
+			return "", fmt.Errorf("invalid rate %s", emulation.Rate)
+		}
+		args = append(args, "rate "+emulation.Rate)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("no network emulation parameters passed")
+	}
+	return strings.Join(args, " "), nil
+}
+
+// removeNetworkEmulation deletes the qdisc installed by the agent on the interface,
+// the filters and the netem qdisc are deleted with it
+func removeNetworkEmulation(iface string) error {
+	output, err := bashLocal(fmt.Sprintf("tc qdisc show dev %s root", iface))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, "qdisc prio "+netemRootHandle) {
+		return nil
+	}
+	_, err = bashLocal(fmt.Sprintf("tc qdisc del dev %s root handle %s", iface, netemRootHandle))
+	return err
+}
+
+// RemoveAllNetworkEmulation removes the network emulation left behind
+// by a previous instance of the agent, failures are logged and do not
+// stop the removal from the other interfaces
+func RemoveAllNetworkEmulation() {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		klog.Error("failed to list network interfaces, Error: ", err)
+		return
+	}
+	for _, iface := range ifaces {
+		if err = removeNetworkEmulation(iface.Name); err != nil {
+			klog.Error("failed to remove network emulation from ", iface.Name, ", Error: ", err)
+		}
+	}
+}
+
+// ApplyNetworkEmulation degrades the traffic sent to the peers, only egress
+// is shaped, replacing any emulation applied previously on the interface
+func ApplyNetworkEmulation(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var emulation api.NetworkEmulation
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&emulation); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	if emulation.NetworkInterface == "" {
+		msg = "no network interface name passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	if len(emulation.Peers) == 0 {
+		msg = "no peers passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	for _, peer := range emulation.Peers {
+		if ip := net.ParseIP(peer); ip == nil || ip.To4() == nil {
+			msg = fmt.Sprintf("peer %s is not an IPv4 address", peer)
+			klog.Error(msg)
+			WrapResult(msg, api.ErrUnprocessableEntity, w)
+			return
+		}
+	}
+	args, err := netemArgs(emulation)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("apply network emulation, data: ", emulation)
+
+	if err = removeNetworkEmulation(emulation.NetworkInterface); err != nil {
+		msg = fmt.Sprintf("failed to remove network emulation, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	commands := []string{
+		fmt.Sprintf("tc qdisc add dev %s root handle %s prio bands 4", emulation.NetworkInterface, netemRootHandle),
+		fmt.Sprintf("tc qdisc add dev %s parent %s handle %s netem %s", emulation.NetworkInterface, netemClass, netemHandle, args),
+	}
+	for _, peer := range emulation.Peers {
+		commands = append(commands, fmt.Sprintf("tc filter add dev %s parent %s protocol ip prio 1 u32 match ip dst %s/32 flowid %s",
+			emulation.NetworkInterface, netemRootHandle, peer, netemClass))
+	}
+	for _, command := range commands {
+		if _, err = bashLocal(command); err != nil {
+			msg = fmt.Sprintf("failed to apply network emulation, Error: %s", err.Error())
+			klog.Error(msg)
+			// do not leave a partially applied emulation behind
+			if err = removeNetworkEmulation(emulation.NetworkInterface); err != nil {
+				klog.Error("failed to remove network emulation, Error: ", err)
+			}
+			WrapResult(msg, api.ErrExecFailed, w)
+			return
+		}
+	}
+	registerFault(api.FaultNetworkEmulation, emulation.NetworkInterface, "", emulation.LeaseSeconds)
+	WrapResult("", api.ErrNone, w)
+}
+
+// RemoveNetworkEmulation removes the network emulation applied on the interface
+func RemoveNetworkEmulation(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var iface api.NetworkInterface
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&iface); err != nil {
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	if iface.NetworkInterface == "" {
+		msg = "no network interface name passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("remove network emulation, data: ", iface)
+	if err := removeNetworkEmulation(iface.NetworkInterface); err != nil {
+		msg = fmt.Sprintf("failed to remove network emulation, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	unregisterFault(api.FaultNetworkEmulation, iface.NetworkInterface)
+	WrapResult("", api.ErrNone, w)
+}
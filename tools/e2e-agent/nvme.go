@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+)
+
+// nvmeSubsysEntry is an element of the Subsystems array printed by nvme list-subsys
+type nvmeSubsysEntry struct {
+	Name  string         `json:"Name"`
+	NQN   string         `json:"NQN"`
+	Paths []api.NvmePath `json:"Paths"`
+}
+
+type nvmeSubsysHost struct {
+	HostNQN    string            `json:"HostNQN"`
+	HostID     string            `json:"HostID"`
+	Subsystems []nvmeSubsysEntry `json:"Subsystems"`
+}
+
+// parseNvmeListSubSys normalises the JSON output of nvme list-subsys.
+// nvme-cli 2.x prints an array of hosts each with its subsystems,
+// nvme-cli 1.x prints a single object where the paths of a subsystem may
+// follow the subsystem as a separate array element.
+func parseNvmeListSubSys(output string) (api.NvmeListSubSysResponse, error) {
+	var resp api.NvmeListSubSysResponse
+	var hosts []nvmeSubsysHost
+
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return resp, nil
+	}
+	if strings.HasPrefix(output, "[") {
+		if err := json.Unmarshal([]byte(output), &hosts); err != nil {
+			return resp, err
+		}
+	} else {
+		var host nvmeSubsysHost
+		if err := json.Unmarshal([]byte(output), &host); err != nil {
+			return resp, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	for _, host := range hosts {
+		if resp.HostNQN == "" {
+			resp.HostNQN = host.HostNQN
+			resp.HostID = host.HostID
+		}
+		for _, entry := range host.Subsystems {
+			if entry.NQN == "" && len(resp.Subsystems) != 0 {
+				last := &resp.Subsystems[len(resp.Subsystems)-1]
+				last.Paths = append(last.Paths, entry.Paths...)
+				continue
+			}
+			resp.Subsystems = append(resp.Subsystems, api.NvmeSubsystem{
+				Name:  entry.Name,
+				NQN:   entry.NQN,
+				Paths: entry.Paths,
+			})
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
+	"k8s.io/klog/v2"
+)
+
+const (
+	cgroupRoot = "/host/sys/fs/cgroup"
+	// cpu.max period used for CPU pressure
+	cpuMaxPeriodUs = 100000
+	// time allowed for the processes killed by the OOM killer to exit
+	oomKillWait = 10 * time.Second
+)
+
+var (
+	containerIdRegexp = regexp.MustCompile(`^[0-9a-f]{12,64}$`)
+	errCgroupFound    = errors.New("cgroup found")
+	errNoContainer    = errors.New("container cgroup not found")
+)
+
+// containerId returns the container id of the target without the runtime prefix
+func containerId(target api.ProcessTarget) (string, error) {
+	id := target.ContainerId
+	if ix := strings.Index(id, "://"); ix != -1 {
+		id = id[ix+3:]
+	}
+	if !containerIdRegexp.MatchString(id) {
+		return "", fmt.Errorf("invalid container id %s", target.ContainerId)
+	}
+	return id, nil
+}
+
+// containerCgroup returns the cgroup directory of the container, the name of
+// the directory contains the container id with both cgroupfs and systemd drivers
+func containerCgroup(id string) (string, error) {
+	var cgroup string
+	err := filepath.WalkDir(cgroupRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// cgroups are removed while walking
+			return nil
+		}
+		if d.IsDir() && strings.Contains(d.Name(), id) {
+			cgroup = path
+			return errCgroupFound
+		}
+		return nil
+	})
+	if errors.Is(err, errCgroupFound) {
+		return cgroup, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%w: %s", errNoContainer, id)
+}
+
+// checkCgroupV2 returns an error if the node does not use the unified cgroup hierarchy
+func checkCgroupV2() error {
+	if _, err := os.Stat(cgroupRoot + "/cgroup.controllers"); err != nil {
+		return fmt.Errorf("cgroup v2 is required, Error: %s", err.Error())
+	}
+	return nil
+}
+
+// processName returns the name of a process, truncated by the kernel to 15 characters
+func processName(pid int) string {
+	comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(comm))
+}
+
+// cgroupPids returns the processes of the cgroup named name, or all if name is empty
+func cgroupPids(cgroup string, name string) ([]int, error) {
+	procs, err := os.ReadFile(cgroup + "/cgroup.procs")
+	if err != nil {
+		return nil, err
+	}
+	if len(name) > 15 {
+		name = name[:15]
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(procs)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pid %s in %s/cgroup.procs", field, cgroup)
+		}
+		if name == "" || processName(pid) == name {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// targetProcesses returns the container id, the cgroup and the processes selected by the target
+func targetProcesses(target api.ProcessTarget) (string, string, []int, error) {
+	id, err := containerId(target)
+	if err != nil {
+		return "", "", nil, err
+	}
+	cgroup, err := containerCgroup(id)
+	if err != nil {
+		return id, "", nil, err
+	}
+	pids, err := cgroupPids(cgroup, target.ProcessName)
+	if err != nil {
+		return id, cgroup, nil, err
+	}
+	if len(pids) == 0 {
+		return id, cgroup, nil, fmt.Errorf("no process %s in container %s", target.ProcessName, id)
+	}
+	return id, cgroup, pids, nil
+}
+
+// signalProcesses sends the signal to the processes, ignoring those which have exited
+func signalProcesses(pids []int, signal syscall.Signal) error {
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, signal); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("failed to send %s to process %d, Error: %s", signal, pid, err.Error())
+		}
+	}
+	return nil
+}
+
+// pausedFaultTarget returns the target of the fault recording paused processes
+func pausedFaultTarget(id string, target api.ProcessTarget) string {
+	if target.ProcessName == "" {
+		return id
+	}
+	return id + "/" + target.ProcessName
+}
+
+// resumeProcesses continues the processes stopped by a process paused fault,
+// there is nothing to resume if the container has gone
+func resumeProcesses(faultTarget string) error {
+	id, name, _ := strings.Cut(faultTarget, "/")
+	_, _, pids, err := targetProcesses(api.ProcessTarget{ContainerId: id, ProcessName: name})
+	if errors.Is(err, errNoContainer) {
+		klog.Info("container ", id, " not found, not resuming processes")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return signalProcesses(pids, syscall.SIGCONT)
+}
+
+// restoreFiles writes back the previous content of files, files of
+// cgroups which have been removed are ignored
+func restoreFiles(restore map[string]string) error {
+	for file, content := range restore {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeProcessRequest decodes the request into v, writing the error response if it fails
+func decodeProcessRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(v); err != nil {
+		msg := fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return false
+	}
+	return true
+}
+
+// PauseProcess stops the processes of a container with SIGSTOP,
+// they are continued when the pause duration expires
+func PauseProcess(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var pause api.ProcessPause
+	if !decodeProcessRequest(w, r, &pause) {
+		return
+	}
+	if pause.DurationSeconds <= 0 {
+		msg = fmt.Sprintf("invalid pause duration %d", pause.DurationSeconds)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	id, cgroup, pids, err := targetProcesses(pause.ProcessTarget)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("pause processes ", pids, " data: ", pause)
+	// registered first so that processes stopped before a failure are continued
+	registerFault(api.FaultProcessPaused, pausedFaultTarget(id, pause.ProcessTarget), "", pause.DurationSeconds)
+	if err = signalProcesses(pids, syscall.SIGSTOP); err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResponse(api.ProcessFaultResponse{ContainerId: id, Cgroup: cgroup, Pids: pids}, w)
+}
+
+// ResumeProcess continues the processes stopped by PauseProcess before the pause duration expires
+func ResumeProcess(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var target api.ProcessTarget
+	if !decodeProcessRequest(w, r, &target) {
+		return
+	}
+	id, err := containerId(target)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	faultTarget := pausedFaultTarget(id, target)
+	klog.Info("resume processes, data: ", target)
+	if err = resumeProcesses(faultTarget); err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	unregisterFault(api.FaultProcessPaused, faultTarget)
+	WrapResult("", api.ErrNone, w)
+}
+
+// ApplyCgroupPressure limits the CPU and memory of a container, applying
+// pressure again changes the limits but keeps the limits to restore
+func ApplyCgroupPressure(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var pressure api.CgroupPressure
+	if !decodeProcessRequest(w, r, &pressure) {
+		return
+	}
+	if pressure.CpuPercent < 0 || pressure.MemoryHighBytes < 0 ||
+		(pressure.CpuPercent == 0 && pressure.MemoryHighBytes == 0) {
+		msg = fmt.Sprintf("invalid cgroup pressure cpu %d%% memory %d bytes", pressure.CpuPercent, pressure.MemoryHighBytes)
+	} else if err := checkCgroupV2(); err != nil {
+		msg = err.Error()
+	}
+	if msg != "" {
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	id, cgroup, pids, err := targetProcesses(api.ProcessTarget{ContainerId: pressure.ContainerId})
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	limits := make(map[string]string)
+	if pressure.CpuPercent != 0 {
+		limits[cgroup+"/cpu.max"] = fmt.Sprintf("%d %d", pressure.CpuPercent*cpuMaxPeriodUs/100, cpuMaxPeriodUs)
+	}
+	if pressure.MemoryHighBytes != 0 {
+		limits[cgroup+"/memory.high"] = strconv.FormatInt(pressure.MemoryHighBytes, 10)
+	}
+	restore := make(map[string]string)
+	for file, content := range restoreOfFault(api.FaultCgroupPressure, id) {
+		restore[file] = content
+	}
+	for file := range limits {
+		if _, ok := restore[file]; ok {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			msg = fmt.Sprintf("failed to read %s, Error: %s", file, err.Error())
+			klog.Error(msg)
+			WrapResult(msg, api.ErrExecFailed, w)
+			return
+		}
+		restore[file] = strings.TrimSpace(string(content))
+	}
+	klog.Info("apply cgroup pressure ", limits, " data: ", pressure)
+	registerRestorableFault(api.FaultCgroupPressure, id, restore, pressure.LeaseSeconds)
+	for file, content := range limits {
+		if err = os.WriteFile(file, []byte(content), 0644); err != nil {
+			msg = fmt.Sprintf("failed to write %s to %s, Error: %s", content, file, err.Error())
+			klog.Error(msg)
+			WrapResult(msg, api.ErrExecFailed, w)
+			return
+		}
+	}
+	WrapResponse(api.ProcessFaultResponse{ContainerId: id, Cgroup: cgroup, Pids: pids}, w)
+}
+
+// RemoveCgroupPressure restores the limits of a container changed by ApplyCgroupPressure
+func RemoveCgroupPressure(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var target api.ProcessTarget
+	if !decodeProcessRequest(w, r, &target) {
+		return
+	}
+	id, err := containerId(target)
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	klog.Info("remove cgroup pressure, data: ", target)
+	if err = restoreFiles(restoreOfFault(api.FaultCgroupPressure, id)); err != nil {
+		msg = fmt.Sprintf("failed to restore cgroup limits of container %s, Error: %s", id, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	unregisterFault(api.FaultCgroupPressure, id)
+	WrapResult("", api.ErrNone, w)
+}
+
+// OomKillContainer triggers the OOM killer in the cgroup of a container by lowering
+// its memory limit to 0, the limit is restored once the kernel has reclaimed what it can.
+// Returns the processes killed.
+func OomKillContainer(w http.ResponseWriter, r *http.Request) {
+	var msg string
+	var target api.ProcessTarget
+	if !decodeProcessRequest(w, r, &target) {
+		return
+	}
+	if err := checkCgroupV2(); err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	id, cgroup, pids, err := targetProcesses(api.ProcessTarget{ContainerId: target.ContainerId})
+	if err != nil {
+		msg = err.Error()
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	memoryMax := cgroup + "/memory.max"
+	limit, err := os.ReadFile(memoryMax)
+	if err != nil {
+		msg = fmt.Sprintf("failed to read %s, Error: %s", memoryMax, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	klog.Info("oom kill processes ", pids, " data: ", target)
+	// the write returns once the kernel has reclaimed or killed what it can
+	err = os.WriteFile(memoryMax, []byte("0"), 0644)
+	if restoreErr := restoreFiles(map[string]string{memoryMax: strings.TrimSpace(string(limit))}); restoreErr != nil {
+		klog.Error("failed to restore ", memoryMax, " Error: ", restoreErr)
+	}
+	if err != nil {
+		msg = fmt.Sprintf("failed to write %s, Error: %s", memoryMax, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	var killed []int
+	for deadline := time.Now().Add(oomKillWait); ; time.Sleep(100 * time.Millisecond) {
+		killed = killed[:0]
+		for _, pid := range pids {
+			if err = syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+				killed = append(killed, pid)
+			}
+		}
+		if len(killed) == len(pids) || time.Now().After(deadline) {
+			break
+		}
+	}
+	if len(killed) == 0 {
+		msg = fmt.Sprintf("no process of container %s was killed", id)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResponse(api.ProcessFaultResponse{ContainerId: id, Cgroup: cgroup, Pids: killed}, w)
+}
@@ -5,17 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
-type Rdma struct {
-	DeviceName    string `json:"deviceName"`
-	InterfaceName string `json:"interfaceName"`
-}
-
 // ListRdmaDevice list RDMA device
 func ListRdmaDevice(w http.ResponseWriter, r *http.Request) {
 	var msg string
+	var links []api.RdmaLink
 	klog.Info("List available RDMA device")
 
 	rdmaDeviceListCommand := "rdma link -j"
@@ -23,21 +20,27 @@ func ListRdmaDevice(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot list RDMA device. Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	if err = json.Unmarshal([]byte(output), &links); err != nil {
+		msg = fmt.Sprintf("failed to unmarshal RDMA device list %s, Error: %s", output, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	WrapResponse(links, w)
 }
 
 // CreateRdmaDevice create rdma device
 func CreateRdmaDevice(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var rdma Rdma
+	var rdma api.Rdma
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&rdma); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if rdma.DeviceName == "" {
@@ -59,21 +62,21 @@ func CreateRdmaDevice(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot create rdma device, Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 // DeleteRdmaDevice destroy rdma device
 func DeleteRdmaDevice(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var rdma Rdma
+	var rdma api.Rdma
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&rdma); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if rdma.DeviceName == "" {
@@ -89,8 +92,8 @@ func DeleteRdmaDevice(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot delete rdma device, Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
@@ -10,74 +10,19 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
-type NodeList struct {
-	Nodes            []string `json:"nodes"`
-	NetworkInterface string   `json:"networkInterface"`
-}
-
-type DiskPool struct {
-	Disk string `json:"disk"`
-}
-
-type Device struct {
-	Device     string `json:"device"`
-	Table      string `json:"table"`
-	DevicePath string `json:"devicePath"`
-	Uuid       string `json:"uuid"`
-	FsType     string `json:"fsType"`
-}
-
-type ControlledDevice struct {
-	Device string `json:"device"`
-	State  string `json:"state"`
-}
-
-type Product struct {
-	Product string `json:"product"`
-	Pid     string `json:"pid"`
-}
-
-type Nvme struct {
-	TargetIp string `json:"targetIp"`
-	Nqn      string `json:"nqn"`
-	HostNqn  string `json:"hostNqn"`
-	HostId   string `json:"hostId"`
-}
-
-type Disk struct {
-	Device         string `json:"device"`
-	SeekParam      string `json:"seekParam"`
-	BlockSizeParam string `json:"blockSizeParam"`
-}
-
-type blkDiscard struct {
-	Device  string `json:"device"`
-	Options string `json:"options"`
-}
-
-type CmpPaths struct {
-	Path1 string `json:"path1"`
-	Path2 string `json:"path2"`
-}
-
-type NetworkInterface struct {
-	NetworkInterface string `json:"networkInterface"`
-}
-
 func homePage(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "Welcome home!\n")
 }
 
-type CmdList struct {
-	Cmd string `json:"cmd"`
-}
-
 var Version = "undefined"
 
 const (
@@ -117,75 +62,105 @@ func handleRequests() {
 	podIP := os.Getenv("MY_POD_IP")
 	restPort := os.Getenv("REST_PORT")
 	router := mux.NewRouter().StrictSlash(true)
-	router.HandleFunc("/", homePage)
-	router.HandleFunc("/ungracefulReboot", ungracefulReboot).Methods("POST")
-	router.HandleFunc("/gracefulReboot", gracefulReboot).Methods("POST")
-	router.HandleFunc("/dropConnectionsFromNodes", dropConnectionsFromNodes).Methods("POST")
-	router.HandleFunc("/acceptConnectionsFromNodes", acceptConnectionsFromNodes).Methods("POST")
-	router.HandleFunc("/createFaultyDevice", createFaultyDevice).Methods("POST")
-	router.HandleFunc("/deleteFaultyDevice", deleteFaultyDevice).Methods("POST")
-	router.HandleFunc("/devicecontrol", controlDevice).Methods("POST")
-	router.HandleFunc("/killioengine", killIoEngine).Methods("POST")
-	router.HandleFunc("/killCsiController", killCsiController).Methods("POST")
-	router.HandleFunc("/killCsiNode", killCsiNode).Methods("POST")
-	router.HandleFunc("/getdevicestate", getDeviceState).Methods("POST")
-	router.HandleFunc("/nvmeconnect", NvmeConnect).Methods("POST")
-	router.HandleFunc("/nvmedisconnect", NvmeDisconnect).Methods("POST")
-	router.HandleFunc("/nvmelist", NvmeList).Methods("POST")
-	router.HandleFunc("/nvmelistsubsys", NvmeListSubSys).Methods("POST")
-	router.HandleFunc("/checksumdevice", ChecksumDevice).Methods("POST")
-	router.HandleFunc("/fscheckdevice", FsCheckDevice).Methods("POST")
-	router.HandleFunc("/xfscheckdevice", XFSCheckDevice).Methods("POST")
-	router.HandleFunc("/fsfreezedevice", FsFreezeDevice).Methods("POST")
-	router.HandleFunc("/fsunfreezedevice", FsUnfreezeDevice).Methods("POST")
-	router.HandleFunc("/listdevice", ListDevice).Methods("POST")
-	router.HandleFunc("/flushDiskWriteCache", flushDiskWriteCache).Methods("POST")
-	router.HandleFunc("/zeroingdisk", ZeroingDisk).Methods("POST")
-	router.HandleFunc("/parted", Parted).Methods("POST")
-	router.HandleFunc("/findmnt", Findmnt).Methods("POST")
-	router.HandleFunc("/lsblk", Lsblk).Methods("POST")
-	router.HandleFunc("/dmesg", Dmesg).Methods("POST")
-	router.HandleFunc("/syslog", Getsyslog).Methods("POST")
-	router.HandleFunc("/listreservation", ListReservation).Methods("POST")
-	router.HandleFunc("/nvmeconnectwithhostid", NvmeConnectWithHostId).Methods("POST")
-	router.HandleFunc("/blkdiscard", BlkDiscard).Methods("POST")
-	router.HandleFunc("/event/list", EventList).Methods("POST")
-	router.HandleFunc("/event/publish", EventPublish).Methods("POST")
-	router.HandleFunc("/event/subscribe", EventSubscribe).Methods("POST")
-	router.HandleFunc("/event/unsubscribe", EventUnsubscribe).Methods("POST")
-	router.HandleFunc("/event/unsubscribeall", EventUnsubscribeAll).Methods("POST")
-	router.HandleFunc("/stats", GetStats).Methods("POST")
-	router.HandleFunc("/cmp", Cmp).Methods("POST")
-	router.HandleFunc("/hugepagezero", ZeroingHugePages).Methods("POST")
+	router.Use(authMiddleware)
+	router.HandleFunc(api.RouteHome, homePage)
+	router.HandleFunc(api.RouteCapabilities, GetCapabilities).Methods("POST")
+	router.HandleFunc(api.RouteUngracefulReboot, ungracefulReboot).Methods("POST")
+	router.HandleFunc(api.RouteGracefulReboot, gracefulReboot).Methods("POST")
+	router.HandleFunc(api.RouteDropConnectionsFromNodes, dropConnectionsFromNodes).Methods("POST")
+	router.HandleFunc(api.RouteAcceptConnectionsFromNodes, acceptConnectionsFromNodes).Methods("POST")
+	router.HandleFunc(api.RouteCreateFaultyDevice, createFaultyDevice).Methods("POST")
+	router.HandleFunc(api.RouteDeleteFaultyDevice, deleteFaultyDevice).Methods("POST")
+	router.HandleFunc(api.RouteDeviceControl, controlDevice).Methods("POST")
+	router.HandleFunc(api.RouteKillIoEngine, killIoEngine).Methods("POST")
+	router.HandleFunc(api.RouteKillCsiController, killCsiController).Methods("POST")
+	router.HandleFunc(api.RouteKillCsiNode, killCsiNode).Methods("POST")
+	router.HandleFunc(api.RouteGetDeviceState, getDeviceState).Methods("POST")
+	router.HandleFunc(api.RouteNvmeConnect, NvmeConnect).Methods("POST")
+	router.HandleFunc(api.RouteNvmeDisconnect, NvmeDisconnect).Methods("POST")
+	router.HandleFunc(api.RouteNvmeList, NvmeList).Methods("POST")
+	router.HandleFunc(api.RouteNvmeListSubSys, NvmeListSubSys).Methods("POST")
+	router.HandleFunc(api.RouteChecksumDevice, ChecksumDevice).Methods("POST")
+	router.HandleFunc(api.RouteFsCheckDevice, FsCheckDevice).Methods("POST")
+	router.HandleFunc(api.RouteXFSCheckDevice, XFSCheckDevice).Methods("POST")
+	router.HandleFunc(api.RouteFsFreezeDevice, FsFreezeDevice).Methods("POST")
+	router.HandleFunc(api.RouteFsUnfreezeDevice, FsUnfreezeDevice).Methods("POST")
+	router.HandleFunc(api.RouteListDevice, ListDevice).Methods("POST")
+	router.HandleFunc(api.RouteFlushDiskWriteCache, flushDiskWriteCache).Methods("POST")
+	router.HandleFunc(api.RouteZeroingDisk, ZeroingDisk).Methods("POST")
+	router.HandleFunc(api.RouteParted, Parted).Methods("POST")
+	router.HandleFunc(api.RouteFindmnt, Findmnt).Methods("POST")
+	router.HandleFunc(api.RouteLsblk, Lsblk).Methods("POST")
+	router.HandleFunc(api.RouteDmesg, Dmesg).Methods("POST")
+	router.HandleFunc(api.RouteSyslog, Getsyslog).Methods("POST")
+	router.HandleFunc(api.RouteListReservation, ListReservation).Methods("POST")
+	router.HandleFunc(api.RouteNvmeConnectWithHostId, NvmeConnectWithHostId).Methods("POST")
+	router.HandleFunc(api.RouteBlkDiscard, BlkDiscard).Methods("POST")
+	router.HandleFunc(api.RouteEventList, EventList).Methods("POST")
+	router.HandleFunc(api.RouteEventPublish, EventPublish).Methods("POST")
+	router.HandleFunc(api.RouteEventSubscribe, EventSubscribe).Methods("POST")
+	router.HandleFunc(api.RouteEventUnsubscribe, EventUnsubscribe).Methods("POST")
+	router.HandleFunc(api.RouteEventUnsubscribeAll, EventUnsubscribeAll).Methods("POST")
+	router.HandleFunc(api.RouteStats, GetStats).Methods("POST")
+	router.HandleFunc(api.RouteCmp, Cmp).Methods("POST")
+	router.HandleFunc(api.RouteHugePageZero, ZeroingHugePages).Methods("POST")
 	//LVM
-	router.HandleFunc("/lvmversion", LvmVersion).Methods("POST")
-	router.HandleFunc("/lvmlistvg", LvmListVg).Methods("POST")
-	router.HandleFunc("/lvmlistpv", LvmListPv).Methods("POST")
-	router.HandleFunc("/lvmcreatepv", LvmCreatePv).Methods("POST")
-	router.HandleFunc("/lvmcreatevg", LvmCreateVg).Methods("POST")
-	router.HandleFunc("/lvmremovepv", LvmRemovePv).Methods("POST")
-	router.HandleFunc("/lvmremovevg", LvmRemoveVg).Methods("POST")
-	router.HandleFunc("/lvmthinpoolautoextendthreshold", LvmThinPoolAutoExtendThreshold).Methods("POST")
-	router.HandleFunc("/lvmthinpoolautoextendpercent", LvmThinPoolAutoExtendPercent).Methods("POST")
-	router.HandleFunc("/lvmlvchangemonitor", LvmLvChangeMonitor).Methods("POST")
-	router.HandleFunc("/lvmlvremovethinpool", LvmLvRemoveThinPool).Methods("POST")
+	router.HandleFunc(api.RouteLvmVersion, LvmVersion).Methods("POST")
+	router.HandleFunc(api.RouteLvmListVg, LvmListVg).Methods("POST")
+	router.HandleFunc(api.RouteLvmListPv, LvmListPv).Methods("POST")
+	router.HandleFunc(api.RouteLvmCreatePv, LvmCreatePv).Methods("POST")
+	router.HandleFunc(api.RouteLvmCreateVg, LvmCreateVg).Methods("POST")
+	router.HandleFunc(api.RouteLvmRemovePv, LvmRemovePv).Methods("POST")
+	router.HandleFunc(api.RouteLvmRemoveVg, LvmRemoveVg).Methods("POST")
+	router.HandleFunc(api.RouteLvmThinPoolAutoExtendThreshold, LvmThinPoolAutoExtendThreshold).Methods("POST")
+	router.HandleFunc(api.RouteLvmThinPoolAutoExtendPercent, LvmThinPoolAutoExtendPercent).Methods("POST")
+	router.HandleFunc(api.RouteLvmLvChangeMonitor, LvmLvChangeMonitor).Methods("POST")
+	router.HandleFunc(api.RouteLvmLvRemoveThinPool, LvmLvRemoveThinPool).Methods("POST")
 	//loop device
-	router.HandleFunc("/createloopdevice", CreateLoopDevice).Methods("POST")
-	router.HandleFunc("/deleteloopdevice", DeleteLoopDevice).Methods("POST")
+	router.HandleFunc(api.RouteCreateLoopDevice, CreateLoopDevice).Methods("POST")
+	router.HandleFunc(api.RouteDeleteLoopDevice, DeleteLoopDevice).Methods("POST")
 	//ZFS
-	router.HandleFunc("/zfsversion", ZfsVersion).Methods("POST")
-	router.HandleFunc("/zfslistpool", ZfsListPool).Methods("POST")
-	router.HandleFunc("/zfscreatepool", ZfsCreatePool).Methods("POST")
-	router.HandleFunc("/zfsdestroypool", ZfsDestroyPool).Methods("POST")
+	router.HandleFunc(api.RouteZfsVersion, ZfsVersion).Methods("POST")
+	router.HandleFunc(api.RouteZfsListPool, ZfsListPool).Methods("POST")
+	router.HandleFunc(api.RouteZfsCreatePool, ZfsCreatePool).Methods("POST")
+	router.HandleFunc(api.RouteZfsDestroyPool, ZfsDestroyPool).Methods("POST")
 	//localPV
-	router.HandleFunc("/createhostpathdisk", CreateHostPathDisk).Methods("POST")
-	router.HandleFunc("/removehostpathdisk", RemoveHostPathDisk).Methods("POST")
+	router.HandleFunc(api.RouteCreateHostPathDisk, CreateHostPathDisk).Methods("POST")
+	router.HandleFunc(api.RouteRemoveHostPathDisk, RemoveHostPathDisk).Methods("POST")
 	//RDMA
-	router.HandleFunc("/listrdmadevice", ListRdmaDevice).Methods("POST")
-	router.HandleFunc("/createrdmadevice", CreateRdmaDevice).Methods("POST")
-	router.HandleFunc("/deleterdmadevice", DeleteRdmaDevice).Methods("POST")
-	router.HandleFunc("/enablenetworkinterface", EnableNetworkInterface).Methods("POST")
-	router.HandleFunc("/disablenetworkinterface", DisableNetworkInterface).Methods("POST")
+	router.HandleFunc(api.RouteListRdmaDevice, ListRdmaDevice).Methods("POST")
+	router.HandleFunc(api.RouteCreateRdmaDevice, CreateRdmaDevice).Methods("POST")
+	router.HandleFunc(api.RouteDeleteRdmaDevice, DeleteRdmaDevice).Methods("POST")
+	router.HandleFunc(api.RouteEnableNetworkInterface, EnableNetworkInterface).Methods("POST")
+	router.HandleFunc(api.RouteDisableNetworkInterface, DisableNetworkInterface).Methods("POST")
+	//network emulation
+	router.HandleFunc(api.RouteApplyNetworkEmulation, ApplyNetworkEmulation).Methods("POST")
+	router.HandleFunc(api.RouteRemoveNetworkEmulation, RemoveNetworkEmulation).Methods("POST")
+	//fault leases
+	router.HandleFunc(api.RouteListFaults, ListFaults).Methods("POST")
+	router.HandleFunc(api.RouteClearFaults, ClearFaults).Methods("POST")
+	//device mapper faults
+	router.HandleFunc(api.RouteCreateDmFaultDevice, CreateDmFaultDevice).Methods("POST")
+	router.HandleFunc(api.RouteReloadDmFaultDevice, ReloadDmFaultDevice).Methods("POST")
+	router.HandleFunc(api.RouteRemoveDmFaultDevice, RemoveDmFaultDevice).Methods("POST")
+	router.HandleFunc(api.RouteUpdateDmDust, UpdateDmDust).Methods("POST")
+	//jobs
+	router.HandleFunc(api.RouteStartJob, StartJob).Methods("POST")
+	router.HandleFunc(api.RouteGetJob, GetJob).Methods("POST")
+	router.HandleFunc(api.RouteCancelJob, CancelJob).Methods("POST")
+	router.HandleFunc(api.RouteFollowLog, FollowLog).Methods("POST")
+	//datasets
+	router.HandleFunc(api.RouteWriteDataset, WriteDataset).Methods("POST")
+	router.HandleFunc(api.RouteVerifyDataset, VerifyDataset).Methods("POST")
+	//process faults
+	router.HandleFunc(api.RoutePauseProcess, PauseProcess).Methods("POST")
+	router.HandleFunc(api.RouteResumeProcess, ResumeProcess).Methods("POST")
+	router.HandleFunc(api.RouteApplyCgroupPressure, ApplyCgroupPressure).Methods("POST")
+	router.HandleFunc(api.RouteRemoveCgroupPressure, RemoveCgroupPressure).Methods("POST")
+	router.HandleFunc(api.RouteOomKillContainer, OomKillContainer).Methods("POST")
+	if err := initCapabilities(router); err != nil {
+		klog.Fatal(err)
+	}
 	log.Fatal(http.ListenAndServe(podIP+":"+restPort, router))
 }
 
@@ -203,11 +178,13 @@ func gracefulReboot(w http.ResponseWriter, r *http.Request) {
 }
 
 func dropConnectionsFromNodes(w http.ResponseWriter, r *http.Request) {
-	var list NodeList
+	var list api.NodeList
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&list); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("Dropping connection from nodes ", list.Nodes, list.NetworkInterface)
 	if err := DropConnectionsFromNodes(list.Nodes, list.NetworkInterface); err != nil {
@@ -216,15 +193,20 @@ func dropConnectionsFromNodes(w http.ResponseWriter, r *http.Request) {
 		klog.Error("failed to drop connection from nodes:", list.Nodes, "Error: ", err)
 		return
 	}
+	for _, node := range list.Nodes {
+		registerFault(api.FaultDropConnection, node, list.NetworkInterface, list.LeaseSeconds)
+	}
 	klog.Info("Successfully stopped network services")
 }
 
 func acceptConnectionsFromNodes(w http.ResponseWriter, r *http.Request) {
-	var list NodeList
+	var list api.NodeList
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&list); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("Accept connection from nodes ", list.Nodes, list.NetworkInterface)
 	err := AcceptConnectionsFromNodes(list.Nodes, list.NetworkInterface)
@@ -234,19 +216,24 @@ func acceptConnectionsFromNodes(w http.ResponseWriter, r *http.Request) {
 		klog.Error("failed to accept connection from nodes:", list.Nodes, "Error: ", err)
 		return
 	}
+	for _, node := range list.Nodes {
+		unregisterFault(api.FaultDropConnection, node)
+	}
 	fmt.Fprint(w, "Successfully started network services\n")
 	klog.Info("Successfully started network services")
 }
 
 func createFaultyDevice(w http.ResponseWriter, r *http.Request) {
 	var (
-		device Device
+		device api.Device
 		cmd    *exec.Cmd
 	)
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	if len(device.Device) == 0 {
 		w.WriteHeader(UnprocessableEntityErrorCode)
@@ -293,6 +280,7 @@ func createFaultyDevice(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, err.Error())
 		klog.Error(err)
 	} else {
+		registerFault(api.FaultFaultyDevice, devName[2], "", device.LeaseSeconds)
 		fmt.Fprint(w, string(output))
 		klog.Info(string(output))
 	}
@@ -300,13 +288,15 @@ func createFaultyDevice(w http.ResponseWriter, r *http.Request) {
 
 func deleteFaultyDevice(w http.ResponseWriter, r *http.Request) {
 	var (
-		device Device
+		device api.Device
 		cmd    *exec.Cmd
 	)
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	if len(device.Device) == 0 {
 		w.WriteHeader(UnprocessableEntityErrorCode)
@@ -332,81 +322,81 @@ func deleteFaultyDevice(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to delete faulty deice ", device, "Error: ", err)
 	} else {
+		unregisterFault(api.FaultFaultyDevice, devName[2])
 		fmt.Fprint(w, string(output))
 		klog.Info(string(output))
 	}
 }
 
 func controlDevice(w http.ResponseWriter, r *http.Request) {
-	var device ControlledDevice
-	var err error
-	params := make([]string, 2)
+	var device api.ControlledDevice
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if len(device.Device) == 0 {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no device passed")
-		klog.Error("no device passed")
+		msg = "no device passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 	if device.State != "offline" && device.State != "running" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "invalid state")
-		klog.Error("invalid state")
+		msg = "invalid state"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
 	// device link which e2e-agent will receive will be like /disk/by-id/scsi-0HC_Volume_29805493 or sdb
 	device.Device = fmt.Sprintf("/dev/%s", device.Device)
 	klog.Info("Resolving device ", device.Device)
-	if device.Device, err = filepath.EvalSymlinks(device.Device); err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to get disk name for dev link:", device.Device, "Error: ", err)
+	resolved, err := filepath.EvalSymlinks(device.Device)
+	if err != nil {
+		msg = fmt.Sprintf("failed to get disk name for dev link %s, Error: %s", device.Device, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	symLink := strings.Split(device.Device, "/")
+	symLink := strings.Split(resolved, "/")
 	device.Device = symLink[len(symLink)-1]
 
 	klog.Info("Successfully got device name ", device.Device)
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "echo " + device.State + " > /host/sys/block/" + device.Device + "/device/state"
-	klog.Info("running command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to run command ", params[1], "Error: ", err)
+	if _, err = bashLocal("echo " + device.State + " > /host/sys/block/" + device.Device + "/device/state"); err != nil {
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	if device.State == "offline" {
+		registerFault(api.FaultDeviceOffline, device.Device, "", device.LeaseSeconds)
+	} else {
+		unregisterFault(api.FaultDeviceOffline, device.Device)
+	}
+	WrapResponse(api.DeviceState{Device: device.Device, State: device.State}, w)
 }
 
 func killIoEngine(w http.ResponseWriter, r *http.Request) {
-	params := make([]string, 2)
-
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "MS=$(pidof io-engine) && kill -9 $MS"
-
 	klog.Info("kill io engine")
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal("MS=$(pidof io-engine) && kill -9 $MS && echo $MS")
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to run command ", params[1], "Error", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	killed := api.KilledProcess{Name: "io-engine"}
+	for _, field := range strings.Fields(output) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			msg := fmt.Sprintf("unexpected pidof output %s", output)
+			klog.Error(msg)
+			WrapResult(msg, api.ErrExecFailed, w)
+			return
+		}
+		killed.Pids = append(killed.Pids, pid)
+	}
+	WrapResponse(killed, w)
 }
 
 func killCsiController(w http.ResponseWriter, r *http.Request) {
@@ -426,7 +416,7 @@ func killCsiController(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	klog.Info(string(output))
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 func killCsiNode(w http.ResponseWriter, r *http.Request) {
@@ -446,31 +436,33 @@ func killCsiNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	klog.Info(string(output))
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 func getDeviceState(w http.ResponseWriter, r *http.Request) {
-	var disk DiskPool
+	var disk api.DiskPool
+	var msg string
 	var err error
-	params := make([]string, 2)
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&disk); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if disk.Disk == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no disk pool passed")
-		klog.Error("no disk pool passed")
+		msg = "no disk pool passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 	disk.Disk = fmt.Sprintf("/dev/%s", disk.Disk)
 	klog.Info("Resolving device ", disk.Disk)
 	if disk.Disk, err = filepath.EvalSymlinks(disk.Disk); err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to get disk name for dev link:", disk.Disk, "Error: ", err)
+		msg = fmt.Sprintf("failed to get disk name for dev link: %s, Error: %s", disk.Disk, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrFileNotExist, w)
 		return
 	}
 	symLink := strings.Split(disk.Disk, "/")
@@ -478,162 +470,181 @@ func getDeviceState(w http.ResponseWriter, r *http.Request) {
 
 	klog.Info("Successfully got device name ", disk.Disk)
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "cat /sys/block/" + disk.Disk + "/device/state"
-	klog.Info("running command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal("cat /sys/block/" + disk.Disk + "/device/state")
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to run command ", params[1], "Error: ", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	WrapResponse(api.DeviceState{Device: disk.Disk, State: output}, w)
 }
 
+// nvmeConnectCommand returns the nvme connect command of the request,
+// the host nqn and host id are passed if set
+func nvmeConnectCommand(nvme api.Nvme) string {
+	command := fmt.Sprintf("nvme connect -a %s -t tcp -s 8420 -n %s", nvme.TargetIp, nvme.Nqn)
+	if nvme.HostNqn != "" {
+		command += " -q " + nvme.HostNqn
+	}
+	if nvme.HostId != "" {
+		command += " -I " + nvme.HostId
+	}
+	return command
+}
+
+// disconnectedRegexp matches the count of controllers in the output of nvme disconnect
+var disconnectedRegexp = regexp.MustCompile(`disconnected (\d+) controller`)
+
 func NvmeConnect(w http.ResponseWriter, r *http.Request) {
-	var nvme Nvme
-	params := make([]string, 2)
+	var nvme api.Nvme
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&nvme); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if nvme.TargetIp == "" || nvme.Nqn == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no nvme target or nqn passed")
-		klog.Error("no nvme target or nqn passed")
+		msg = "no nvme target or nqn passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	if nvme.HostNqn == "" {
-		params[1] = fmt.Sprintf("nvme connect -a %s -t tcp -s 8420 -n %s", nvme.TargetIp, nvme.Nqn)
-	} else {
-		params[1] = fmt.Sprintf("nvme connect -a %s -t tcp -s 8420 -n %s -q %s", nvme.TargetIp, nvme.Nqn, nvme.HostNqn)
-	}
-	klog.Info("running command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal(nvmeConnectCommand(nvme))
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error: ", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	WrapResponse(api.NvmeConnection{
+		TargetIp: nvme.TargetIp,
+		Nqn:      nvme.Nqn,
+		HostNqn:  nvme.HostNqn,
+		HostId:   nvme.HostId,
+		Output:   output,
+	}, w)
 }
 
 func NvmeDisconnect(w http.ResponseWriter, r *http.Request) {
-	var nvme Nvme
-	params := make([]string, 2)
+	var nvme api.Nvme
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&nvme); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if nvme.Nqn == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no nvme nqn passed")
-		klog.Error("no nvme nqn passed")
+		msg = "no nvme nqn passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = fmt.Sprintf("nvme disconnect -n %s", nvme.Nqn)
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal(fmt.Sprintf("nvme disconnect -n %s", nvme.Nqn))
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error: ", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	disconnection := api.NvmeDisconnection{Nqn: nvme.Nqn}
+	// the output is NQN:<nqn> disconnected <n> controller(s)
+	if match := disconnectedRegexp.FindStringSubmatch(output); match != nil {
+		disconnection.Controllers, _ = strconv.Atoi(match[1])
+	}
+	WrapResponse(disconnection, w)
 }
 
 func NvmeList(w http.ResponseWriter, r *http.Request) {
-	params := make([]string, 2)
-
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "nvme list --output-format=json"
+	var msg string
+	var list api.NvmeListResponse
 
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal("nvme list --output-format=json")
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error: ", err)
+		msg = fmt.Sprintf("failed to list nvme devices, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	// nvme list prints nothing when there are no nvme devices
+	if output != "" {
+		if err = json.Unmarshal([]byte(output), &list); err != nil {
+			msg = fmt.Sprintf("failed to unmarshal nvme list output %s, Error: %s", output, err.Error())
+			klog.Error(msg)
+			WrapResult(msg, api.ErrJsonDecode, w)
+			return
+		}
+	}
+	WrapResponse(list, w)
 }
 
 func NvmeListSubSys(w http.ResponseWriter, r *http.Request) {
-	params := make([]string, 2)
-
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "nvme list-subsys --output-format=json"
+	var msg string
 
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal("nvme list-subsys --output-format=json")
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error: ", err)
+		msg = fmt.Sprintf("failed to list nvme subsystems, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	subsystems, err := parseNvmeListSubSys(output)
+	if err != nil {
+		msg = fmt.Sprintf("failed to parse nvme list-subsys output %s, Error: %s", output, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	WrapResponse(subsystems, w)
 }
 
 func ChecksumDevice(w http.ResponseWriter, r *http.Request) {
-	var device Device
-	params := make([]string, 2)
+	var device api.Device
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
-	klog.Info("Running cksum on device, data: %v", device)
+	klog.Info("Running cksum on device, data: ", device)
 	if device.DevicePath == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no device path passed")
-		klog.Error("no device path passed")
+		msg = "no device path passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = fmt.Sprintf("cksum %s", device.DevicePath)
-
-	klog.Info("executing command", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal(checksumDeviceCommand(device))
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error: ", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	// the output format is <checksum> <size> <device>
+	fields := strings.Fields(output)
+	if len(fields) != 3 {
+		msg = fmt.Sprintf("unexpected cksum output %s", output)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	checksum, errChecksum := strconv.ParseUint(fields[0], 10, 32)
+	size, errSize := strconv.ParseUint(fields[1], 10, 64)
+	if errChecksum != nil || errSize != nil {
+		msg = fmt.Sprintf("unexpected cksum output %s", output)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResponse(api.DeviceChecksum{
+		Checksum:   uint32(checksum),
+		Size:       size,
+		DevicePath: fields[2],
+	}, w)
 }
 
 // Executes bash command on the node and returns value to caller function
@@ -666,13 +677,15 @@ func detachLoopDevice(loDevice string) (string, error) {
 }
 
 func FsCheckDevice(w http.ResponseWriter, r *http.Request) {
-	var device Device
+	var device api.Device
 	var params string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("Running fsck on device, data: %v", device)
 	if device.DevicePath == "" {
@@ -730,7 +743,7 @@ func FsCheckDevice(w http.ResponseWriter, r *http.Request) {
 		klog.Error("failed to setup loop device for", device.DevicePath, "Error: ", err)
 		return
 	}
-	WrapResult(outputString, ErrNone, w)
+	WrapResult(outputString, api.ErrNone, w)
 }
 
 func createTempDir() (string, error) {
@@ -744,13 +757,15 @@ func createTempDir() (string, error) {
 //
 // This step is needed to replay metadata from log
 func XFSCheckDevice(w http.ResponseWriter, r *http.Request) {
-	var device Device
+	var device api.Device
 	var params string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("device data: %v", device)
 	if device.DevicePath == "" {
@@ -807,7 +822,8 @@ func XFSCheckDevice(w http.ResponseWriter, r *http.Request) {
 	klog.Info(outputString)
 
 	if device.FsType == "xfs" {
-		params = fmt.Sprintf("rm -rf %s; echo $?", tmpDir)
+		// rmdir, unlike rm -rf, does not delete the filesystem if the umount failed
+		params = fmt.Sprintf("rmdir %s; echo $?", tmpDir)
 	} else {
 		klog.Error("not a supported filesystem for fscheck", device.FsType, "Error: ", device.FsType)
 		return
@@ -837,7 +853,7 @@ func XFSCheckDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	klog.Info(outputString)
-	WrapResult(outputString, ErrNone, w)
+	WrapResult(outputString, api.ErrNone, w)
 }
 
 func listMountPoint(devicePath string) (string, error) {
@@ -847,13 +863,15 @@ func listMountPoint(devicePath string) (string, error) {
 }
 
 func FsFreezeDevice(w http.ResponseWriter, r *http.Request) {
-	var device Device
+	var device api.Device
 	var params string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("Running fs freeze on device, data: %v", device)
 	if device.DevicePath == "" {
@@ -880,17 +898,19 @@ func FsFreezeDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	klog.Info(outputString)
-	WrapResult(outputString, ErrNone, w)
+	WrapResult(outputString, api.ErrNone, w)
 }
 
 func FsUnfreezeDevice(w http.ResponseWriter, r *http.Request) {
-	var device Device
+	var device api.Device
 	var params string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("Running fs unfreeze on device, data: %v", device)
 	if device.DevicePath == "" {
@@ -917,25 +937,16 @@ func FsUnfreezeDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	klog.Info(outputString)
-	WrapResult(outputString, ErrNone, w)
+	WrapResult(outputString, api.ErrNone, w)
 }
 
 func ListDevice(w http.ResponseWriter, r *http.Request) {
-	params := make([]string, 2)
-
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "ls /dev/"
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal("ls /dev/")
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error: ", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
+	WrapResponse(api.DeviceList{Devices: strings.Fields(output)}, w)
 }
 
 func flushDiskWriteCache(w http.ResponseWriter, r *http.Request) {
@@ -950,75 +961,64 @@ func flushDiskWriteCache(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		w.WriteHeader(InternalServerErrorCode)
 		klog.Error("failed to execute command ", params[1], "Error: ", err)
-		WrapResult(fmt.Sprintf("failed to execute: %s, got error: %v", params[1], err), ErrJsonEncode, w)
+		WrapResult(fmt.Sprintf("failed to execute: %s, got error: %v", params[1], err), api.ErrJsonEncode, w)
 		return
 	}
 	klog.Info(string(output))
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 func ZeroingDisk(w http.ResponseWriter, r *http.Request) {
-	var disk Disk
-	params := make([]string, 2)
+	var disk api.Disk
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&disk); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if disk.Device == "" || disk.SeekParam == "" || disk.BlockSizeParam == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no device or seek or block param passed")
-		klog.Error("no device or seek or block param passed")
+		msg = "no device or seek or block param passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = fmt.Sprintf("dd if=/dev/zero of=/host%s count=1 oflag=direct %s %s && sync",
-		disk.Device,
-		disk.SeekParam,
-		disk.BlockSizeParam,
-	)
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal(zeroingDiskCommand(disk))
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	WrapResponse(api.DiskWrite{Device: disk.Device, Output: output}, w)
 }
 
 func ZeroingHugePages(w http.ResponseWriter, r *http.Request) {
-	params := make([]string, 2)
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = "echo 0 | sudo tee /proc/sys/vm/nr_hugepages"
-
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal("echo 0 | sudo tee /proc/sys/vm/nr_hugepages")
 	if err != nil {
-		klog.Error("failed to execute command ", params[1], "Error", err)
-		msg := fmt.Sprintf("failed to execute command %s, err: %v", params[1], err)
-		WrapResult(msg, ErrGeneral, w)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	klog.Info(string(output))
-	WrapResult(string(output), ErrNone, w)
+	pages, err := strconv.Atoi(output)
+	if err != nil {
+		msg := fmt.Sprintf("unexpected nr_hugepages %s", output)
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	WrapResponse(api.HugePages{NrHugePages: pages}, w)
 }
 
 func Parted(w http.ResponseWriter, r *http.Request) {
-	var cmdline CmdList
+	var cmdline api.CmdList
 	var cmd *exec.Cmd
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&cmdline); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	if len(cmdline.Cmd) == 0 {
 		w.WriteHeader(UnprocessableEntityErrorCode)
@@ -1060,8 +1060,63 @@ func Findmnt(w http.ResponseWriter, r *http.Request) {
 	bashit("findmnt --json", w, r)
 }
 
+// lsblkDevice is a block device reported by lsblk --json --bytes,
+// versions of lsblk report the size either as a number or as a string
+type lsblkDevice struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Size       interface{}   `json:"size"`
+	FsType     string        `json:"fstype"`
+	MountPoint string        `json:"mountpoint"`
+	Children   []lsblkDevice `json:"children"`
+}
+
+func (dev lsblkDevice) blockDevice() api.BlockDevice {
+	blockDevice := api.BlockDevice{
+		Name:       dev.Name,
+		Type:       dev.Type,
+		Size:       sizeBytes(dev.Size),
+		FsType:     dev.FsType,
+		MountPoint: dev.MountPoint,
+	}
+	for _, child := range dev.Children {
+		blockDevice.Children = append(blockDevice.Children, child.blockDevice())
+	}
+	return blockDevice
+}
+
+func sizeBytes(size interface{}) uint64 {
+	switch v := size.(type) {
+	case float64:
+		return uint64(v)
+	case string:
+		bytes, _ := strconv.ParseUint(v, 10, 64)
+		return bytes
+	}
+	return 0
+}
+
 func Lsblk(w http.ResponseWriter, r *http.Request) {
-	bashit("lsblk --json", w, r)
+	var devices struct {
+		BlockDevices []lsblkDevice `json:"blockdevices"`
+	}
+	var msg string
+	output, err := bashLocal("lsblk --json --bytes -o NAME,TYPE,SIZE,FSTYPE,MOUNTPOINT")
+	if err != nil {
+		WrapResult(err.Error(), api.ErrExecFailed, w)
+		return
+	}
+	if err = json.Unmarshal([]byte(output), &devices); err != nil {
+		msg = fmt.Sprintf("failed to unmarshal lsblk output %s, Error: %s", output, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
+	}
+	resp := api.LsblkResponse{BlockDevices: []api.BlockDevice{}}
+	for _, dev := range devices.BlockDevices {
+		resp.BlockDevices = append(resp.BlockDevices, dev.blockDevice())
+	}
+	WrapResponse(resp, w)
 }
 
 func Dmesg(w http.ResponseWriter, r *http.Request) {
@@ -1073,96 +1128,106 @@ func Getsyslog(w http.ResponseWriter, r *http.Request) {
 }
 
 func ListReservation(w http.ResponseWriter, r *http.Request) {
-	var device Device
+	var device api.Device
+	var report api.ReservationReport
+	var msg string
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&device); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if device.DevicePath == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no device path passed")
-		klog.Error("no device path passed")
+		msg = "no device path passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
+		return
+	}
+	output, err := bashLocal(fmt.Sprintf("nvme resv-report %s -c 1 --output-format=json", device.DevicePath))
+	if err != nil {
+		msg = fmt.Sprintf("failed to list reservations of %s, Error: %s", device.DevicePath, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrExecFailed, w)
+		return
+	}
+	if err = json.Unmarshal([]byte(output), &report); err != nil {
+		msg = fmt.Sprintf("failed to unmarshal reservation report %s, Error: %s", output, err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
-	cmd := fmt.Sprintf("nvme resv-report %s -c 1 --output-format=json", device.DevicePath)
-	bashit(cmd, w, r)
+	WrapResponse(report, w)
 }
 
 func NvmeConnectWithHostId(w http.ResponseWriter, r *http.Request) {
-	var nvme Nvme
-	params := make([]string, 2)
+	var nvme api.Nvme
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&nvme); err != nil {
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
+		return
 	}
 	if nvme.TargetIp == "" || nvme.Nqn == "" || nvme.HostId == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		fmt.Fprint(w, "no nvme target or nqn or host id passed")
-		klog.Error("no nvme target or nqn or host id passed")
+		msg = "no nvme target or nqn or host id passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = fmt.Sprintf("nvme connect -a %s -t tcp -s 8420 -n %s -q %s -I %s", nvme.TargetIp, nvme.Nqn, nvme.HostNqn, nvme.HostId)
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal(nvmeConnectCommand(nvme))
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	WrapResponse(api.NvmeConnection{
+		TargetIp: nvme.TargetIp,
+		Nqn:      nvme.Nqn,
+		HostNqn:  nvme.HostNqn,
+		HostId:   nvme.HostId,
+		Output:   output,
+	}, w)
 }
 
 func BlkDiscard(w http.ResponseWriter, r *http.Request) {
-	var data blkDiscard
-	params := make([]string, 2)
+	var data api.BlkDiscard
+	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&data); err != nil {
-		_, _ = fmt.Fprint(w, err.Error())
-		klog.Error("failed to read JSON encoded data, Error: ", err)
+		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
+		klog.Error(msg)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if data.Device == "" {
-		w.WriteHeader(UnprocessableEntityErrorCode)
-		_, _ = fmt.Fprint(w, "no device param passed")
-		klog.Error("no device  param passed")
+		msg = "no device param passed"
+		klog.Error(msg)
+		WrapResult(msg, api.ErrUnprocessableEntity, w)
 		return
 	}
 
-	cmdStr := "bash"
-	params[0] = "-c"
-	params[1] = fmt.Sprintf("blkdiscard %s %s", data.Device, data.Options)
-
-	klog.Info("executing command ", params[1])
-	cmd := exec.Command(cmdStr, params...)
-	output, err := cmd.CombinedOutput()
+	output, err := bashLocal(blkDiscardCommand(data))
 	if err != nil {
-		w.WriteHeader(InternalServerErrorCode)
-		_, _ = fmt.Fprint(w, err.Error())
-		klog.Error("failed to execute command ", params[1], "Error", err)
+		WrapResult(err.Error(), api.ErrExecFailed, w)
 		return
 	}
-	_, _ = fmt.Fprint(w, string(output))
-	klog.Info(string(output))
+	WrapResponse(api.DiskWrite{Device: data.Device, Output: output}, w)
 }
 
 func Cmp(w http.ResponseWriter, r *http.Request) {
-	var paths CmpPaths
+	var paths api.CmpPaths
 	params := make([]string, 2)
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&paths); err != nil {
+		w.WriteHeader(UnprocessableEntityErrorCode)
 		fmt.Fprint(w, err.Error())
 		klog.Error("failed to read JSON encoded data, Error: ", err)
+		return
 	}
 	klog.Info("Running cmp on device, data: %v", paths)
 	if paths.Path1 == "" {
@@ -1181,14 +1246,14 @@ func Cmp(w http.ResponseWriter, r *http.Request) {
 	params[0] = "-c"
 	params[1] = fmt.Sprintf("cmp -b %s %s", paths.Path1, paths.Path2)
 
-	var errCode E2eAgentErrcode = ErrNone
+	var errCode api.E2eAgentErrcode = api.ErrNone
 
 	klog.Info("executing command ", params[1])
 	cmd := exec.Command(cmdStr, params...)
 	output, err := cmd.CombinedOutput()
 	b64out := base64.StdEncoding.EncodeToString(output)
 	if err != nil {
-		errCode = ErrGeneral
+		errCode = api.ErrGeneral
 		klog.Error("failed command ", params[1], " Error: ", err)
 	}
 	klog.Info(string(output))
@@ -1198,12 +1263,12 @@ func Cmp(w http.ResponseWriter, r *http.Request) {
 // EnableNetworkInterface enable network interface
 func EnableNetworkInterface(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var iface NetworkInterface
+	var iface api.NetworkInterface
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&iface); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -1220,21 +1285,22 @@ func EnableNetworkInterface(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot enable network interface, Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	unregisterFault(api.FaultNetworkInterfaceDown, iface.NetworkInterface)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 // DisableNetworkInterface disable network interface
 func DisableNetworkInterface(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var iface NetworkInterface
+	var iface api.NetworkInterface
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&iface); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if iface.NetworkInterface == "" {
@@ -1250,8 +1316,9 @@ func DisableNetworkInterface(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot disable network interface, Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	registerFault(api.FaultNetworkInterfaceDown, iface.NetworkInterface, "", iface.LeaseSeconds)
+	WrapResult(string(output), api.ErrNone, w)
 }
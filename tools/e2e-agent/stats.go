@@ -6,22 +6,19 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
-type Stats struct {
-	ServiceAddr string `json:"serviceAddr"`
-}
-
 func GetStats(w http.ResponseWriter, r *http.Request) {
-	var stats_req Stats
+	var stats_req api.Stats
 	var msg string
 
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&stats_req); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 
@@ -29,7 +26,7 @@ func GetStats(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to connect to stats module, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrConnectFail, w)
+		WrapResult(msg, api.ErrConnectFail, w)
 		return
 	}
 
@@ -37,11 +34,11 @@ func GetStats(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("failed to connect to read response, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrReadFail, w)
+		WrapResult(msg, api.ErrReadFail, w)
 		return
 	}
 
 	sb := string(body)
 	klog.Info("stats response: ", sb)
-	WrapResult(sb, ErrNone, w)
+	WrapResult(sb, api.ErrNone, w)
 }
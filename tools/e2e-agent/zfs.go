@@ -4,15 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/openebs/openebs-e2e/tools/e2e-agent/api"
 	"k8s.io/klog/v2"
 )
 
-type Zpool struct {
-	PoolDiskPath string `json:"poolDiskPath"`
-	PoolName     string `json:"poolName"`
-}
-
 // ZfsVersion check zfs version installed on node
 func ZfsVersion(w http.ResponseWriter, r *http.Request) {
 	var msg string
@@ -23,10 +21,10 @@ func ZfsVersion(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot get zfs version. Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 // ZfsListPool list zfs pool
@@ -34,26 +32,44 @@ func ZfsListPool(w http.ResponseWriter, r *http.Request) {
 	var msg string
 	klog.Info("List zfs pool")
 
-	zfsPoolListCommand := "zpool list"
+	// scripted mode, tab separated exact values
+	zfsPoolListCommand := "zpool list -H -p -o name,size,alloc,free,health"
 	output, err := bashLocal(zfsPoolListCommand)
 	if err != nil {
 		msg = fmt.Sprintf("cannot list zfs pool Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	pools := []api.ZfsPool{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			continue
+		}
+		size, _ := strconv.ParseUint(fields[1], 10, 64)
+		alloc, _ := strconv.ParseUint(fields[2], 10, 64)
+		free, _ := strconv.ParseUint(fields[3], 10, 64)
+		pools = append(pools, api.ZfsPool{
+			Name:   fields[0],
+			Size:   size,
+			Alloc:  alloc,
+			Free:   free,
+			Health: fields[4],
+		})
+	}
+	WrapResponse(pools, w)
 }
 
 // ZfsCreatePool create zfs pool
 func ZfsCreatePool(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var zPool Zpool
+	var zPool api.Zpool
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&zPool); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if zPool.PoolDiskPath == "" {
@@ -75,21 +91,21 @@ func ZfsCreatePool(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot create zfs pool Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }
 
 // ZfsDestroyPool destroy zfs pool
 func ZfsDestroyPool(w http.ResponseWriter, r *http.Request) {
 	var msg string
-	var zPool Zpool
+	var zPool api.Zpool
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&zPool); err != nil {
 		msg = fmt.Sprintf("failed to read JSON encoded data, Error: %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrJsonDecode, w)
+		WrapResult(msg, api.ErrJsonDecode, w)
 		return
 	}
 	if zPool.PoolName == "" {
@@ -106,8 +122,8 @@ func ZfsDestroyPool(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		msg = fmt.Sprintf("cannot destroy zfs pool Error %s", err.Error())
 		klog.Error(msg)
-		WrapResult(msg, ErrExecFailed, w)
+		WrapResult(msg, api.ErrExecFailed, w)
 		return
 	}
-	WrapResult(string(output), ErrNone, w)
+	WrapResult(string(output), api.ErrNone, w)
 }